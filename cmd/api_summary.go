@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+var apiSummary bool
+var apiSummarySpec = `Print at exit the calls and the time spent on each AWS API operation (also printed by --debug)`
+
+type apiCallStats struct {
+	operation string
+	calls     int
+	retries   int
+	throttles int
+	errors    int
+	elapsed   time.Duration
+}
+
+// apiCalls collects the stats of every AWS API operation called by the invocation
+var apiCalls = struct {
+	sync.Mutex
+	byOperation map[string]*apiCallStats
+}{byOperation: make(map[string]*apiCallStats)}
+
+// apiCallStatsOf must be called with apiCalls locked
+func apiCallStatsOf(r *request.Request) *apiCallStats {
+	operation := r.ClientInfo.ServiceID + " " + r.Operation.Name
+
+	stats, ok := apiCalls.byOperation[operation]
+	if !ok {
+		stats = &apiCallStats{operation: operation}
+		apiCalls.byOperation[operation] = stats
+	}
+	return stats
+}
+
+// countAPIThrottle runs on every failed attempt, throttled ones included even when retried by the SDK
+func countAPIThrottle(r *request.Request) {
+	if !request.IsErrorThrottle(r.Error) {
+		return
+	}
+
+	apiCalls.Lock()
+	defer apiCalls.Unlock()
+	apiCallStatsOf(r).throttles++
+}
+
+// recordAPICall runs once per call, after its last attempt. The time includes the retries and their backoff.
+func recordAPICall(r *request.Request) {
+	apiCalls.Lock()
+	defer apiCalls.Unlock()
+
+	stats := apiCallStatsOf(r)
+	stats.calls++
+	stats.retries += r.RetryCount
+	stats.elapsed += time.Since(r.Time)
+	if r.Error != nil {
+		stats.errors++
+	}
+}
+
+// collectAPICalls adds the handlers to the session, so every client created from it is measured
+func collectAPICalls() {
+	if !apiSummary && !debug {
+		return
+	}
+
+	awsSession.Handlers.Retry.PushBack(countAPIThrottle)
+	awsSession.Handlers.Complete.PushBack(recordAPICall)
+}
+
+// printAPISummary writes the operations called to the standard error, the slowest first
+func printAPISummary() {
+	if !apiSummary && !debug {
+		return
+	}
+
+	apiCalls.Lock()
+	defer apiCalls.Unlock()
+
+	if len(apiCalls.byOperation) == 0 {
+		return
+	}
+
+	var operations []*apiCallStats
+	total := &apiCallStats{operation: "total"}
+	for _, stats := range apiCalls.byOperation {
+		operations = append(operations, stats)
+
+		total.calls += stats.calls
+		total.retries += stats.retries
+		total.throttles += stats.throttles
+		total.errors += stats.errors
+		total.elapsed += stats.elapsed
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].elapsed > operations[j].elapsed
+	})
+
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tCALLS\tRETRIES\tTHROTTLED\tERRORS\tTIME")
+	for _, stats := range append(operations, total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\n", stats.operation, stats.calls, stats.retries, stats.throttles, stats.errors, stats.elapsed.Round(100*time.Millisecond))
+	}
+	w.Flush()
+}
+
+// exit ends the invocation with the code, waiting for the pager and printing the API summary first
+func exit(code int) {
+	stopPager()
+	printAPISummary()
+	os.Exit(code)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&apiSummary, "api-summary", false, apiSummarySpec)
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/sts"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+// auditRecord is a line of the audit log, one per change requested to AWS
+type auditRecord struct {
+	Time       time.Time        `json:"time"`
+	Invocation string           `json:"invocation"`
+	Identity   string           `json:"identity"`
+	Region     string           `json:"region"`
+	Command    string           `json:"command"`
+	Cluster    string           `json:"cluster,omitempty"`
+	Operation  string           `json:"operation"`
+	Resources  []*auditResource `json:"resources,omitempty"`
+	Outcome    string           `json:"outcome"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// auditResource is a resource changed, with its key fields before and after the change when known
+type auditResource struct {
+	Resource string            `json:"resource"`
+	Before   map[string]string `json:"before,omitempty"`
+	After    map[string]string `json:"after,omitempty"`
+}
+
+// readOnlyOperationPrefixes are the API operations not journaled, the others change something
+var readOnlyOperationPrefixes = []string{"Describe", "List", "Get", "BatchGet", "Filter", "Search", "Lookup", "Validate", "Test"}
+
+var readOnlyOperations = map[string]bool{
+	"StartQuery": true,
+	"StopQuery":  true,
+}
+
+// auditIdentifierFields are the parameters naming the resource of the operations without a dedicated summary
+var auditIdentifierFields = []string{
+	"ResourceArn", "Service", "Task", "TaskDefinition", "ContainerInstance", "RepositoryName", "TargetGroupArn",
+	"ResourceId", "LogGroupName", "DashboardName", "PolicyName", "Name", "Id",
+}
+
+var secretNamePattern = regexp.MustCompile(`(?i)(secret|password|passwd|token|credential|api_?key|private_?key|access_?key)`)
+
+var auditJournal = struct {
+	sync.Mutex
+	path       string
+	invocation string
+	command    string
+	identity   string
+	warned     bool
+
+	// observed keeps the key fields of the services last described, the state before they are changed
+	observed map[string]map[string]string
+}{observed: make(map[string]map[string]string)}
+
+var auditIdentityOnce sync.Once
+
+// auditIdentityResolved is closed once the caller identity is known, or given up after auditIdentityTimeout
+var auditIdentityResolved = make(chan struct{})
+
+const auditIdentityTimeout = 3 * time.Second
+
+// auditLogPath is the audit_log of the config file, empty when journaling is off
+func auditLogPath() string {
+	path := viper.GetString("audit_log")
+	if path == "" {
+		return ""
+	}
+
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return path
+	}
+	return expanded
+}
+
+// redactAssignment hides the value of NAME=VALUE when the name looks like a secret, or always when informed
+func redactAssignment(arg string, always bool) string {
+	i := strings.Index(arg, "=")
+	if i <= 0 || !(always || secretNamePattern.MatchString(arg[:i])) {
+		return arg
+	}
+	return arg[:i] + "=<redacted>"
+}
+
+// redactCommandLine joins the arguments with the values of --env and of secret-like assignments hidden
+func redactCommandLine(args []string) string {
+	line := []string{"ecsctl"}
+	envValue := false
+	for _, arg := range args {
+		switch {
+		case envValue:
+			arg = redactAssignment(arg, true)
+		case strings.HasPrefix(arg, "--env="):
+			arg = "--env=" + redactAssignment(strings.TrimPrefix(arg, "--env="), true)
+		default:
+			arg = redactAssignment(arg, false)
+		}
+		envValue = arg == "--env"
+
+		if strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		line = append(line, arg)
+	}
+	return strings.Join(line, " ")
+}
+
+func isReadOnlyOperation(operation string) bool {
+	if readOnlyOperations[operation] {
+		return true
+	}
+
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceKeyFields are the fields of a service worth comparing before and after a change
+func serviceKeyFields(s *ecs.Service) map[string]string {
+	family, revision := splitTaskDefinitionArn(aws.StringValue(s.TaskDefinition))
+	return map[string]string{
+		"desiredCount":   strconv.FormatInt(aws.Int64Value(s.DesiredCount), 10),
+		"taskDefinition": family + ":" + strconv.FormatInt(revision, 10),
+	}
+}
+
+// requestResources summarizes the resources changed by the request, from its output when the operation is known.
+// Must be called with auditJournal locked.
+func requestResources(r *request.Request) (resources []*auditResource) {
+	switch out := r.Data.(type) {
+	case *ecs.UpdateServiceOutput:
+		if s := out.Service; s != nil {
+			arn := aws.StringValue(s.ServiceArn)
+			after := serviceKeyFields(s)
+			resources = append(resources, &auditResource{Resource: arn, Before: auditJournal.observed[arn], After: after})
+			auditJournal.observed[arn] = after
+		}
+	case *ecs.CreateServiceOutput:
+		if s := out.Service; s != nil {
+			resources = append(resources, &auditResource{Resource: aws.StringValue(s.ServiceArn), After: serviceKeyFields(s)})
+		}
+	case *ecs.DeleteServiceOutput:
+		if s := out.Service; s != nil {
+			resources = append(resources, &auditResource{Resource: aws.StringValue(s.ServiceArn), Before: serviceKeyFields(s)})
+		}
+	case *ecs.RunTaskOutput:
+		for _, t := range out.Tasks {
+			family, revision := splitTaskDefinitionArn(aws.StringValue(t.TaskDefinitionArn))
+			resources = append(resources, &auditResource{
+				Resource: aws.StringValue(t.TaskArn),
+				After:    map[string]string{"taskDefinition": family + ":" + strconv.FormatInt(revision, 10)},
+			})
+		}
+	case *ecs.StopTaskOutput:
+		if t := out.Task; t != nil {
+			resources = append(resources, &auditResource{
+				Resource: aws.StringValue(t.TaskArn),
+				Before:   map[string]string{"lastStatus": aws.StringValue(t.LastStatus)},
+				After:    map[string]string{"desiredStatus": aws.StringValue(t.DesiredStatus)},
+			})
+		}
+	case *ecs.RegisterTaskDefinitionOutput:
+		if td := out.TaskDefinition; td != nil {
+			resources = append(resources, &auditResource{
+				Resource: aws.StringValue(td.TaskDefinitionArn),
+				After:    map[string]string{"revision": strconv.FormatInt(aws.Int64Value(td.Revision), 10)},
+			})
+		}
+	case *ecs.UpdateContainerInstancesStateOutput:
+		for _, ci := range out.ContainerInstances {
+			resources = append(resources, &auditResource{
+				Resource: aws.StringValue(ci.ContainerInstanceArn),
+				After:    map[string]string{"status": aws.StringValue(ci.Status)},
+			})
+		}
+	}
+
+	if len(resources) > 0 {
+		return
+	}
+
+	for _, field := range auditIdentifierFields {
+		if value := requestField(r.Params, field); value != "" {
+			return []*auditResource{{Resource: value}}
+		}
+	}
+	return
+}
+
+// lookupAuditIdentity starts looking the caller identity up, once, when the first change is about to be sent.
+// It runs alongside the change, so the journal waits at most what is left of the timeout and never retries STS.
+func lookupAuditIdentity(r *request.Request) {
+	if isReadOnlyOperation(r.Operation.Name) {
+		return
+	}
+
+	auditIdentityOnce.Do(func() {
+		go func() {
+			defer close(auditIdentityResolved)
+
+			ctx, cancel := context.WithTimeout(context.Background(), auditIdentityTimeout)
+			defer cancel()
+
+			identity, err := stsI.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{}, func(r *request.Request) {
+				r.Retryer = client.NoOpRetryer{}
+			})
+			if err != nil {
+				auditJournal.identity = "unknown"
+				debugf("audit log: %s", err.Error())
+				return
+			}
+			auditJournal.identity = aws.StringValue(identity.Arn)
+		}()
+	})
+}
+
+// auditIdentity is the caller identity of the session, waiting for the lookup started with the change
+func auditIdentity(r *request.Request) string {
+	lookupAuditIdentity(r)
+	<-auditIdentityResolved
+	return auditJournal.identity
+}
+
+// auditWarning warns once per invocation, the change itself is never stopped by the journal.
+// Must be called with auditJournal locked.
+func auditWarning(err error) {
+	if auditJournal.warned {
+		return
+	}
+
+	auditJournal.warned = true
+	fmt.Fprintf(os.Stderr, "warning: audit log %s not written: %s\n", auditJournal.path, err.Error())
+}
+
+func appendAuditRecord(record *auditRecord) (err error) {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(auditJournal.path), 0700); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(auditJournal.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(content, '\n'))
+	return
+}
+
+// journalMutation runs once per call, after its last attempt, appending the calls that change something to the audit log
+func journalMutation(r *request.Request) {
+	if out, ok := r.Data.(*ecs.DescribeServicesOutput); ok && r.Error == nil {
+		auditJournal.Lock()
+		for _, s := range out.Services {
+			auditJournal.observed[aws.StringValue(s.ServiceArn)] = serviceKeyFields(s)
+		}
+		auditJournal.Unlock()
+	}
+
+	if isReadOnlyOperation(r.Operation.Name) {
+		return
+	}
+
+	identity := auditIdentity(r)
+
+	auditJournal.Lock()
+	defer auditJournal.Unlock()
+
+	record := &auditRecord{
+		Time:       time.Now().UTC(),
+		Invocation: auditJournal.invocation,
+		Identity:   identity,
+		Region:     aws.StringValue(r.Config.Region),
+		Command:    auditJournal.command,
+		Cluster:    requestCluster(r.Params),
+		Operation:  r.ClientInfo.ServiceID + " " + r.Operation.Name,
+		Resources:  requestResources(r),
+		Outcome:    "ok",
+	}
+
+	if r.Error != nil {
+		record.Outcome = "failed"
+		record.Error = r.Error.Error()
+	}
+
+	if err := appendAuditRecord(record); err != nil {
+		auditWarning(err)
+	}
+}
+
+// journalMutations adds the handler to the session when audit_log is set, so every client created from it is journaled
+func journalMutations() {
+	path := auditLogPath()
+	if path == "" {
+		return
+	}
+
+	auditJournal.path = path
+	auditJournal.invocation = strconv.FormatInt(time.Now().UnixNano(), 36)
+	auditJournal.command = redactCommandLine(os.Args[1:])
+
+	awsSession.Handlers.Build.PushBack(lookupAuditIdentity)
+	awsSession.Handlers.Complete.PushBack(journalMutation)
+}
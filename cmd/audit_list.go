@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type auditListOptions struct {
+	since       string
+	cluster     string
+	allClusters bool
+}
+
+var auditListOpts auditListOptions
+
+// readAuditLog reads the records of the audit log, skipping and counting the lines that do not decode,
+// as a line may be cut by an interrupted write
+func readAuditLog(path string) (records []*auditRecord, skipped int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		record := &auditRecord{}
+		if json.Unmarshal(scanner.Bytes(), record) != nil {
+			skipped++
+			continue
+		}
+		records = append(records, record)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// auditClusterMatches compares the cluster of the record, a name or an ARN, with the cluster informed
+func auditClusterMatches(record *auditRecord, cluster string) bool {
+	if record.Cluster == cluster {
+		return true
+	}
+	return strings.HasSuffix(record.Cluster, ":cluster/"+cluster)
+}
+
+func auditResourcesSummary(record *auditRecord) string {
+	var resources []string
+	for _, r := range record.Resources {
+		name := r.Resource[strings.LastIndex(r.Resource, "/")+1:]
+
+		var changes []string
+		for key, after := range r.After {
+			if before, ok := r.Before[key]; ok && before != after {
+				changes = append(changes, fmt.Sprintf("%s %s→%s", key, before, after))
+			} else if !ok {
+				changes = append(changes, key+" "+after)
+			}
+		}
+
+		sort.Strings(changes)
+		if len(changes) > 0 {
+			name += " (" + strings.Join(changes, ", ") + ")"
+		}
+		resources = append(resources, name)
+	}
+
+	if len(resources) == 0 {
+		return "-"
+	}
+	return strings.Join(resources, ", ")
+}
+
+func auditListRun(cmd *cobra.Command, args []string) {
+	opts := &auditListOpts
+
+	must(checkOutputFormat(outputFormat, "text", "json"))
+
+	path := auditLogPath()
+	if path == "" {
+		must(errors.New("No audit log, set audit_log on the config file (e.g. ecsctl config set audit_log ~/.ecsctl/audit.ndjson)"))
+	}
+
+	var since time.Time
+	if opts.since != "" {
+		var err error
+		since, err = parseSince(opts.since)
+		must(err)
+	}
+
+	cluster := opts.cluster
+	if opts.allClusters {
+		cluster = ""
+	}
+
+	records, skipped, err := readAuditLog(path)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	must(err)
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d lines of %s could not be read\n", skipped, path)
+	}
+
+	listed := []*auditRecord{}
+	for _, record := range records {
+		if record.Time.Before(since) || (cluster != "" && !auditClusterMatches(record, cluster)) {
+			continue
+		}
+		listed = append(listed, record)
+	}
+
+	if outputFormat == "json" {
+		must(printJSON(listed))
+		return
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "TIME\tIDENTITY\tCLUSTER\tOPERATION\tRESOURCES\tOUTCOME\tCOMMAND")
+	for _, record := range listed {
+		identity := record.Identity[strings.LastIndex(record.Identity, "/")+1:]
+		cluster := firstSetting(record.Cluster[strings.LastIndex(record.Cluster, "/")+1:], "-")
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", humanizeTime(&record.Time), identity, cluster, record.Operation,
+			auditResourcesSummary(record), record.Outcome, record.Command)
+	}
+	w.Flush()
+}
+
+var auditListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the changes made by ecsctl, as journaled on the audit log",
+	Args:        cobra.NoArgs,
+	Run:         auditListRun,
+	Annotations: pagedOutput,
+}
+
+func auditRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [command]",
+	Short: "Commands to query the audit log of the changes made by ecsctl",
+	Run:   auditRun,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditListCmd)
+
+	flags := auditListCmd.Flags()
+	flags.StringVar(&auditListOpts.since, "since", "", auditSinceSpec)
+	flags.StringVarP(&auditListOpts.cluster, "cluster", "c", "", auditClusterSpec)
+	flags.BoolVar(&auditListOpts.allClusters, "all-clusters", false, auditAllClustersSpec)
+
+	registerOutputSchema(auditListCmd, "audit list", []*auditRecord{})
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+// firstSetting is the first value not empty, in order of precedence
+func firstSetting(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// awsRegion is --region, then AWS_REGION or AWS_DEFAULT_REGION, then region of the config file.
+// Empty leaves it to the shared config of the profile (~/.aws/config).
+func awsRegion() string {
+	return firstSetting(region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), viper.GetString("region"))
+}
+
+// awsProfile is --profile, then AWS_PROFILE, then profile of the config file. Empty is the default profile.
+func awsProfile() string {
+	return firstSetting(profile, os.Getenv("AWS_PROFILE"), viper.GetString("profile"))
+}
+
+// sharedProfileExists looks for the section of the profile in the shared config and credentials files,
+// where the SDK looks for them
+func sharedProfileExists(name string) bool {
+	home, _ := homedir.Dir()
+	files := []string{
+		firstSetting(os.Getenv("AWS_CONFIG_FILE"), filepath.Join(home, ".aws", "config")),
+		firstSetting(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), filepath.Join(home, ".aws", "credentials")),
+	}
+
+	section := regexp.MustCompile(`(?m)^\s*\[\s*(profile\s+)?` + regexp.QuoteMeta(name) + `\s*\]`)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err == nil && section.Match(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAWSSession builds the session every client is created from, failing on an invalid region or
+// a profile missing from the shared config files before any request is sent
+func newAWSSession() (*session.Session, error) {
+	r := awsRegion()
+	if r != "" {
+		if err := validateRegion(r); err != nil {
+			return nil, err
+		}
+	}
+
+	p := awsProfile()
+
+	options := session.Options{
+		Profile:           p,
+		SharedConfigState: session.SharedConfigEnable,
+	}
+
+	if r != "" {
+		options.Config.Region = aws.String(r)
+	}
+
+	// The SDK falls back silently to no credentials for a profile it does not find
+	if p != "" && !sharedProfileExists(p) {
+		return nil, fmt.Errorf("Profile '%s' not found in the shared config files (~/.aws/config and ~/.aws/credentials)", p)
+	}
+
+	s, err := session.NewSessionWithOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The AWS CLI run for ECS Exec and Session Manager uses the same profile
+	profile = p
+
+	debugf("region %s, profile %s", aws.StringValue(s.Config.Region), firstSetting(p, "default"))
+	return s, nil
+}
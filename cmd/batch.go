@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	typistPkg "github.com/gumieri/typist"
+)
+
+var taskPattern = regexp.MustCompile(`^(arn:aws[a-z-]*:ecs:[a-z0-9-]+:\d{12}:task/\S+|[0-9a-f-]{32,36})$`)
+
+var taskDefinitionRevisionPattern = regexp.MustCompile(`^(arn:aws[a-z-]*:ecs:[a-z0-9-]+:\d{12}:task-definition/)?[a-zA-Z0-9_-]{1,255}:\d+$`)
+
+var servicePattern = regexp.MustCompile(`^(arn:aws[a-z-]*:ecs:[a-z0-9-]+:\d{12}:service/\S+|[a-zA-Z0-9_-]{1,255})$`)
+
+// readItems reads one identifier per line, ignoring blank lines and # comments
+func readItems(r io.Reader) (items []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		items = append(items, line)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// batchItems merges the arguments with the identifiers read from the standard input, validating every one of them
+func batchItems(args []string, fromStdin bool, valid *regexp.Regexp, kind string) (items []string, err error) {
+	items = append(items, args...)
+
+	if fromStdin {
+		read, err := readItems(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, read...)
+	}
+
+	if len(items) == 0 {
+		err = fmt.Errorf("No %s informed", kind)
+		return
+	}
+
+	var invalid []string
+	for _, item := range items {
+		if !valid.MatchString(item) {
+			invalid = append(invalid, item)
+		}
+	}
+
+	if len(invalid) > 0 {
+		err = fmt.Errorf("Invalid %s:\n\t%s", kind, strings.Join(invalid, "\n\t"))
+	}
+
+	return
+}
+
+// confirmBatch asks for confirmation on the terminal when the standard input is the list of items itself
+func confirmBatch(question string, fromStdin bool) bool {
+	if !fromStdin {
+		return typist.Confirm(question)
+	}
+
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		must(errors.New("Unable to ask for confirmation while reading from the standard input, use --yes"))
+	}
+	defer tty.Close()
+
+	t := &typistPkg.Typist{In: tty, Out: os.Stderr}
+	return t.Confirm(question)
+}
+
+type batchFailure struct {
+	item string
+	err  error
+}
+
+// runBatch applies fn to every item, at most concurrency at the same time, reporting each result.
+// The failures are returned in the order they happened.
+func runBatch(items []string, concurrency int, fn func(item string) error) (failures []batchFailure) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, item := range items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(item string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := fn(item)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				failures = append(failures, batchFailure{item, err})
+				fmt.Fprintf(os.Stderr, "%s failed: %s\n", item, err.Error())
+				return
+			}
+
+			printAffected(item, item+" done")
+		}(item)
+	}
+
+	wg.Wait()
+
+	return
+}
+
+// reportBatch exits non-zero when any of the items failed
+func reportBatch(total int, failures []batchFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	must(fmt.Errorf("%d of %d failed", len(failures), total))
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// maxStopAttempts bounds the retries of a throttled StopTask
+const maxStopAttempts = 6
+
+// stopBackoff is the wait before the next attempt of a throttled StopTask, shortened by the tests
+var stopBackoff = nextBackoff
+
+// taskStopper is the part of the ECS client used by bulkStopTasks, so it can be replaced by a fake
+type taskStopper interface {
+	StopTaskWithContext(aws.Context, *ecs.StopTaskInput, ...request.Option) (*ecs.StopTaskOutput, error)
+}
+
+type stopResult struct {
+	task     string
+	attempts int
+	err      error
+}
+
+// stopProgress keeps a single status line updated in place on the standard error
+type stopProgress struct {
+	mutex   sync.Mutex
+	total   int
+	done    int
+	failed  int
+	enabled bool
+}
+
+func (p *stopProgress) add(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.done++
+	if err != nil {
+		p.failed++
+	}
+
+	if p.enabled {
+		fmt.Fprintf(os.Stderr, "\rstopping tasks: %d/%d, %d failed", p.done, p.total, p.failed)
+	}
+}
+
+func (p *stopProgress) finish() {
+	if p.enabled && p.done > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// stopTaskWithRetry stops a task, backing off while ECS throttles the requests
+func stopTaskWithRetry(ctx context.Context, client taskStopper, cluster, task, reason string) (result stopResult) {
+	result.task = task
+
+	var backoff time.Duration
+	for result.attempts < maxStopAttempts {
+		// A task picked up as the context is canceled is reported as not attempted
+		if result.err = ctx.Err(); result.err != nil {
+			return
+		}
+
+		result.attempts++
+
+		_, result.err = client.StopTaskWithContext(ctx, &ecs.StopTaskInput{
+			Cluster: aws.String(cluster),
+			Task:    aws.String(task),
+			Reason:  aws.String(reason),
+		})
+		if result.err == nil || !isThrottledOrUnavailable(result.err) {
+			return
+		}
+
+		backoff = stopBackoff(backoff)
+		if !sleepContext(ctx, backoff) {
+			result.err = ctx.Err()
+			return
+		}
+	}
+	return
+}
+
+// bulkStopTasks stops the tasks, at most concurrency at the same time, until done or the context is canceled.
+// The tasks not attempted before the cancellation are reported with the context error.
+func bulkStopTasks(ctx context.Context, client taskStopper, cluster string, tasks []string, reason string, concurrency int) (results []stopResult) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	progress := &stopProgress{total: len(tasks), enabled: !quiet}
+	defer progress.finish()
+
+	results = make([]stopResult, len(tasks))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, task := range tasks {
+		select {
+		case <-ctx.Done():
+			results[i] = stopResult{task: task, err: ctx.Err()}
+			progress.add(ctx.Err())
+			continue
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, task string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i] = stopTaskWithRetry(ctx, client, cluster, task, reason)
+			progress.add(results[i].err)
+		}(i, task)
+	}
+
+	wg.Wait()
+	return
+}
+
+// stopErrorKind groups the failures by the AWS error code, or by the message when there is none
+func stopErrorKind(err error) string {
+	if code := awsErrorCode(err); code != "" {
+		return code
+	}
+
+	if err == context.Canceled {
+		return "interrupted"
+	}
+	return err.Error()
+}
+
+// reportBulkStop prints the stopped tasks and the failures grouped by error, returning how many failed
+func reportBulkStop(results []stopResult) (failed int) {
+	groups := make(map[string][]string)
+	for _, result := range results {
+		if result.err == nil {
+			printAffected(result.task, result.task+" stopped")
+			continue
+		}
+
+		kind := stopErrorKind(result.err)
+		groups[kind] = append(groups[kind], result.task)
+		failed++
+	}
+
+	var kinds []string
+	for kind := range groups {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		fmt.Fprintf(os.Stderr, "%d failed with %s:\n\t%s\n", len(groups[kind]), kind, strings.Join(groups[kind], "\n\t"))
+	}
+	return
+}
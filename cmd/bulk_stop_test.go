@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// fakeStopper throttles the first calls of each task, every call when throttled is negative,
+// and fails the tasks named missing
+type fakeStopper struct {
+	sync.Mutex
+	throttled   int
+	attempts    map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeStopper) StopTaskWithContext(ctx aws.Context, input *ecs.StopTaskInput, options ...request.Option) (*ecs.StopTaskOutput, error) {
+	task := aws.StringValue(input.Task)
+
+	f.Lock()
+	f.attempts[task]++
+	attempt := f.attempts[task]
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	f.Lock()
+	f.inFlight--
+	f.Unlock()
+
+	switch {
+	case strings.HasPrefix(task, "missing"):
+		return nil, awserr.New(ecs.ErrCodeInvalidParameterException, "The referenced task was not found.", nil)
+	case f.throttled < 0 || attempt <= f.throttled:
+		return nil, awserr.New("ThrottlingException", "Rate exceeded", nil)
+	}
+	return &ecs.StopTaskOutput{Task: &ecs.Task{TaskArn: input.Task}}, nil
+}
+
+func fastStopBackoff(t *testing.T) {
+	backoff := stopBackoff
+	stopBackoff = func(time.Duration) time.Duration { return time.Millisecond }
+	t.Cleanup(func() { stopBackoff = backoff })
+
+	q := quiet
+	quiet = true
+	t.Cleanup(func() { quiet = q })
+}
+
+func TestBulkStopTasks(t *testing.T) {
+	fastStopBackoff(t)
+
+	tasks := []string{"a1", "a2", "missing1", "a3", "a4", "missing2", "a5", "a6"}
+	fake := &fakeStopper{throttled: 2, attempts: make(map[string]int)}
+
+	results := bulkStopTasks(context.Background(), fake, "staging", tasks, "test", 3)
+
+	if fake.maxInFlight > 3 {
+		t.Errorf("got %d StopTask calls at the same time, want at most 3", fake.maxInFlight)
+	}
+
+	for i, result := range results {
+		if result.task != tasks[i] {
+			t.Fatalf("got %s as the result %d, want the results in the order of the tasks", result.task, i)
+		}
+
+		if strings.HasPrefix(result.task, "missing") {
+			if awsErrorCode(result.err) != ecs.ErrCodeInvalidParameterException || result.attempts != 1 {
+				t.Errorf("%s: got %v after %d attempts, want the error not retried", result.task, result.err, result.attempts)
+			}
+			continue
+		}
+
+		if result.err != nil || result.attempts != 3 {
+			t.Errorf("%s: got %v after %d attempts, want stopped on the third", result.task, result.err, result.attempts)
+		}
+	}
+
+	var failed int
+	stdout := captureStdout(t, func() { failed = reportBulkStop(results) })
+	if failed != 2 {
+		t.Errorf("got %d failed, want 2", failed)
+	}
+	if stdout != "a1\na2\na3\na4\na5\na6\n" {
+		t.Errorf("got %q, want only the stopped tasks", stdout)
+	}
+}
+
+func TestBulkStopTasksThrottledOut(t *testing.T) {
+	fastStopBackoff(t)
+
+	fake := &fakeStopper{throttled: -1, attempts: make(map[string]int)}
+	results := bulkStopTasks(context.Background(), fake, "staging", []string{"a1", "a2"}, "test", 5)
+
+	for _, result := range results {
+		if stopErrorKind(result.err) != "ThrottlingException" || result.attempts != maxStopAttempts {
+			t.Errorf("%s: got %v after %d attempts, want throttled after %d", result.task, result.err, result.attempts, maxStopAttempts)
+		}
+	}
+}
+
+func TestBulkStopTasksCanceled(t *testing.T) {
+	fastStopBackoff(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fake := &fakeStopper{attempts: make(map[string]int)}
+	results := bulkStopTasks(ctx, fake, "staging", []string{"a1", "a2", "a3"}, "test", 1)
+
+	for _, result := range results {
+		if stopErrorKind(result.err) != "interrupted" || result.attempts != 0 {
+			t.Errorf("%s: got %v after %d attempts, want interrupted before any", result.task, result.err, result.attempts)
+		}
+	}
+
+	if len(fake.attempts) > 0 {
+		t.Errorf("got StopTask called for %v after the cancellation", fake.attempts)
+	}
+}
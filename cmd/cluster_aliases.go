@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// clusterFlags are the flags taking a cluster, resolved through the cluster_aliases of the config file
+var clusterFlags = []string{"cluster", "to-cluster"}
+
+// resolvedClusterAliases keeps the alias each resolved cluster was informed as, to be shown on errors
+var resolvedClusterAliases = make(map[string]string)
+
+func validateClusterAliases(value interface{}) error {
+	for alias, cluster := range value.(map[string]interface{}) {
+		if _, ok := cluster.(string); !ok {
+			return fmt.Errorf("cluster_aliases.%s must be a cluster name, got %s", alias, configValueKind(cluster))
+		}
+	}
+	return nil
+}
+
+// clusterAliases reads the cluster_aliases of the config file. Viper lowercases the keys, so aliases are case insensitive.
+func clusterAliases() map[string]string {
+	return viper.GetStringMapString("cluster_aliases")
+}
+
+// resolveClusterAlias returns the cluster an alias stands for.
+// A cluster really named as the alias wins over it, to avoid surprises.
+func resolveClusterAlias(name string) string {
+	cluster, ok := clusterAliases()[strings.ToLower(name)]
+	if !ok {
+		return name
+	}
+
+	described, err := ecsxI.DescribeAllClusters([]*string{aws.String(name)})
+	if err == nil && len(described) > 0 && aws.StringValue(described[0].Status) == "ACTIVE" {
+		debugf("cluster %s exists, ignoring the alias '%s' → %s", name, name, cluster)
+		return name
+	}
+
+	debugf("alias '%s' → %s", name, cluster)
+	resolvedClusterAliases[cluster] = name
+	return cluster
+}
+
+// clusterAliasNote describes the alias the cluster was informed as, empty when it was not an alias
+func clusterAliasNote(cluster string) string {
+	alias, ok := resolvedClusterAliases[cluster]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (alias '%s' → %s)", alias, cluster)
+}
+
+// resolveClusterFlags replaces the aliases informed on the cluster flags of the command, before anything uses them
+func resolveClusterFlags(cmd *cobra.Command) {
+	for _, name := range clusterFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+
+		if resolved := resolveClusterAlias(flag.Value.String()); resolved != flag.Value.String() {
+			flag.Value.Set(resolved)
+		}
+	}
+}
+
+func completeClusterAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var aliases []string
+	for alias, cluster := range clusterAliases() {
+		if strings.HasPrefix(alias, toComplete) {
+			aliases = append(aliases, alias+"\t"+cluster)
+		}
+	}
+	sort.Strings(aliases)
+
+	return aliases, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerClusterCompletion offers the clusters and their aliases on the cluster flags of every command
+func registerClusterCompletion(cmd *cobra.Command) {
+	for _, name := range clusterFlags {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.RegisterFlagCompletionFunc(name, completeClusters)
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		registerClusterCompletion(child)
+	}
+}
@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
 	"github.com/spf13/cobra"
 )
 
 type templateUserData struct {
-	Cluster string
-	Region  string
+	Cluster        string
+	Region         string
+	SigtermTimeout string
 }
 
 func parseTags(tags []string) (parsed []*ec2.Tag) {
@@ -158,6 +162,137 @@ func findSubnet(s string) (subnet *ec2.Subnet, err error) {
 	return
 }
 
+// listClusters returns the ARN of every cluster of the account in the region
+func listClusters() (clusterArns []*string, err error) {
+	return ecsxI.ListAllClusters()
+}
+
+// describeCluster describes a single cluster, failing when it does not exist
+func describeCluster(cluster string) (c *ecs.Cluster, err error) {
+	described, err := ecsxI.DescribeAllClusters([]*string{aws.String(cluster)})
+	if ecsx.IsMissing(err) || (err == nil && (len(described) == 0 || aws.StringValue(described[0].Status) == "INACTIVE")) {
+		err = errors.New("Cluster informed not found" + clusterAliasNote(cluster))
+	}
+	if err != nil {
+		return
+	}
+
+	c = described[0]
+	return
+}
+
+// describeContainerInstances lists and describes every container instance of the cluster
+func describeContainerInstances(cluster string) (instances []*ecs.ContainerInstance, err error) {
+	arns, err := ecsxI.ListAllContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	return ecsxI.DescribeAllContainerInstances(cluster, arns)
+}
+
+type clusterCapabilities struct {
+	name              string
+	capacityProviders []string
+	containerInsights bool
+	instances         int
+	outpostInstances  int
+	outpostArn        string
+}
+
+var clusterCapabilitiesCache = make(map[string]*clusterCapabilities)
+
+// probeCluster finds out once per execution what the cluster supports,
+// so commands can fail early instead of sending requests doomed to fail
+func probeCluster(cluster string) (capabilities *clusterCapabilities, err error) {
+	if capabilities, ok := clusterCapabilitiesCache[cluster]; ok {
+		return capabilities, nil
+	}
+
+	described, err := ecsxI.DescribeAllClusters([]*string{aws.String(cluster)}, ecs.ClusterFieldSettings)
+	if ecsx.IsMissing(err) || (err == nil && len(described) == 0) {
+		err = fmt.Errorf("Cluster %s not found", cluster)
+	}
+	if err != nil {
+		return
+	}
+
+	c := described[0]
+	capabilities = &clusterCapabilities{
+		name:              aws.StringValue(c.ClusterName),
+		capacityProviders: aws.StringValueSlice(c.CapacityProviders),
+	}
+
+	for _, setting := range c.Settings {
+		if aws.StringValue(setting.Name) == ecs.ClusterSettingNameContainerInsights {
+			capabilities.containerInsights = aws.StringValue(setting.Value) != "disabled"
+		}
+	}
+
+	containerInstances, err := describeContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	var instanceIDs []*string
+	for _, ci := range containerInstances {
+		instanceIDs = append(instanceIDs, ci.Ec2InstanceId)
+	}
+
+	capabilities.instances = len(instanceIDs)
+
+	if len(instanceIDs) > 0 {
+		err = ec2I.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+			InstanceIds: instanceIDs,
+		}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					if instance.OutpostArn != nil {
+						capabilities.outpostInstances = capabilities.outpostInstances + 1
+						capabilities.outpostArn = aws.StringValue(instance.OutpostArn)
+					}
+				}
+			}
+			return !lastPage
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	clusterCapabilitiesCache[cluster] = capabilities
+	return
+}
+
+// outpostsOnly is true when every container instance of the cluster runs on AWS Outposts
+func (c *clusterCapabilities) outpostsOnly() bool {
+	return c.instances > 0 && c.outpostInstances == c.instances
+}
+
+func (c *clusterCapabilities) checkLaunchType(launchType string) error {
+	if launchType != ecs.LaunchTypeFargate {
+		return nil
+	}
+
+	if c.outpostsOnly() {
+		return fmt.Errorf("Cluster %s runs only on AWS Outposts (%s), where the FARGATE launch type is not available. Use the EC2 launch type", c.name, c.outpostArn)
+	}
+
+	return nil
+}
+
+func (c *clusterCapabilities) checkContainerInsights() error {
+	if c.outpostsOnly() {
+		return fmt.Errorf("Container Insights metrics are not available for cluster %s, which runs only on AWS Outposts (%s)", c.name, c.outpostArn)
+	}
+
+	if !c.containerInsights {
+		return fmt.Errorf("Container Insights is not enabled on cluster %s", c.name)
+	}
+
+	return nil
+}
+
 func clustersRun(cmd *cobra.Command, args []string) {
 	cmd.Help()
 }
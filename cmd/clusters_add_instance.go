@@ -3,14 +3,11 @@ package cmd
 import (
 	"bytes"
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"html/template"
-	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/spf13/cobra"
 )
@@ -20,35 +17,45 @@ var ec2InstanceUserData = `
 echo ECS_CLUSTER={{.Cluster}} >> /etc/ecs/ecs.config;echo ECS_BACKEND_HOST= >> /etc/ecs/ecs.config;
 `
 
-func clustersAddInstanceRun(cmd *cobra.Command, clusters []string) {
-	clustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: []*string{
-			aws.String(clusters[0]),
-		},
-	})
-	typist.Must(err)
+type clustersAddInstanceOptions struct {
+	instanceType    string
+	subnet          string
+	securityGroups  []string
+	instanceProfile string
+	key             string
+	tags            []string
+	minimum         int64
+	maximum         int64
+	credit          string
+	kernelID        string
+	ebs             bool
+	monitoring      bool
+}
 
-	if len(clustersDescription.Clusters) == 0 {
-		typist.Must(errors.New("Cluster informed not found"))
-	}
+var clustersAddInstanceOpts clustersAddInstanceOptions
+
+func clustersAddInstanceRun(cmd *cobra.Command, clusters []string) {
+	opts := &clustersAddInstanceOpts
 
-	c := clustersDescription.Clusters[0]
+	c, err := describeCluster(clusters[0])
+	must(err)
 
 	tmpl, err := template.New("UserData").Parse(ec2InstanceUserData)
-	typist.Must(err)
+	must(err)
 
 	userDataF := new(bytes.Buffer)
-	typist.Must(tmpl.Execute(userDataF, templateUserData{Cluster: *c.ClusterName}))
+	must(tmpl.Execute(userDataF, templateUserData{Cluster: *c.ClusterName}))
 
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		exit(1)
 	}
 
 	latestImage, err := latestAmiEcsOptimized()
-	typist.Must(err)
+	must(err)
 
 	// TODO: automaticaly --create-roles if does not exist
+	instanceProfile := opts.instanceProfile
 	if instanceProfile == "" {
 		instanceProfile = "ecsInstanceRole"
 	}
@@ -56,60 +63,60 @@ func clustersAddInstanceRun(cmd *cobra.Command, clusters []string) {
 	instanceProfileResponse, err := iamI.GetInstanceProfile(&iam.GetInstanceProfileInput{
 		InstanceProfileName: aws.String(instanceProfile),
 	})
-	typist.Must(err)
+	must(err)
 
-	subnetDescription, err := findSubnet(subnet)
-	typist.Must(err)
+	subnetDescription, err := findSubnet(opts.subnet)
+	must(err)
 
 	// TODO: AWS Tags
 	RunInstancesInput := ec2.RunInstancesInput{
 		IamInstanceProfile: &ec2.IamInstanceProfileSpecification{Arn: instanceProfileResponse.InstanceProfile.Arn},
-		EbsOptimized:       aws.Bool(ebs),
+		EbsOptimized:       aws.Bool(opts.ebs),
 		ImageId:            latestImage.ImageId,
 		SubnetId:           subnetDescription.SubnetId,
-		InstanceType:       aws.String(instanceType),
+		InstanceType:       aws.String(opts.instanceType),
 		UserData:           aws.String(base64.StdEncoding.EncodeToString(userDataF.Bytes())),
-		MinCount:           aws.Int64(minimum),
-		MaxCount:           aws.Int64(maximum),
+		MinCount:           aws.Int64(opts.minimum),
+		MaxCount:           aws.Int64(opts.maximum),
 	}
 
-	if len(tags) > 0 {
+	if len(opts.tags) > 0 {
 		RunInstancesInput.TagSpecifications = []*ec2.TagSpecification{
 			&ec2.TagSpecification{
 				ResourceType: aws.String("instance"),
-				Tags:         parseTags(tags),
+				Tags:         parseTags(opts.tags),
 			},
 		}
 	}
 
-	if credit != "" {
+	if opts.credit != "" {
 		RunInstancesInput.CreditSpecification = &ec2.CreditSpecificationRequest{
-			CpuCredits: aws.String(credit),
+			CpuCredits: aws.String(opts.credit),
 		}
 	}
 
 	var sgs []*string
-	for _, securityGroup := range securityGroups {
+	for _, securityGroup := range opts.securityGroups {
 		sg, err := findSecurityGroup(securityGroup)
-		typist.Must(err)
+		must(err)
 		sgs = append(sgs, sg.GroupId)
 	}
 	RunInstancesInput.SecurityGroupIds = sgs
 
-	if kernelID != "" {
-		RunInstancesInput.KernelId = aws.String(kernelID)
+	if opts.kernelID != "" {
+		RunInstancesInput.KernelId = aws.String(opts.kernelID)
 	}
 
-	if key != "" {
-		RunInstancesInput.KeyName = aws.String(key)
+	if opts.key != "" {
+		RunInstancesInput.KeyName = aws.String(opts.key)
 	}
 
-	if monitoring {
-		RunInstancesInput.Monitoring = &ec2.RunInstancesMonitoringEnabled{Enabled: aws.Bool(monitoring)}
+	if opts.monitoring {
+		RunInstancesInput.Monitoring = &ec2.RunInstancesMonitoringEnabled{Enabled: aws.Bool(opts.monitoring)}
 	}
 
 	_, err = ec2I.RunInstances(&RunInstancesInput)
-	typist.Must(err)
+	must(err)
 }
 
 var clustersAddInstanceCmd = &cobra.Command{
@@ -125,18 +132,18 @@ func init() {
 	flags := clustersAddInstanceCmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringVarP(&instanceType, "instance-type", "i", "", requiredSpec+instanceTypeSpec)
-	flags.StringVarP(&subnet, "subnet", "n", "", requiredSpec+subnetSpec)
-	flags.StringSliceVarP(&securityGroups, "security-groups", "g", []string{}, securityGroupsSpec)
+	flags.StringVarP(&clustersAddInstanceOpts.instanceType, "instance-type", "i", "", requiredSpec+instanceTypeSpec)
+	flags.StringVarP(&clustersAddInstanceOpts.subnet, "subnet", "n", "", requiredSpec+subnetSpec)
+	flags.StringSliceVarP(&clustersAddInstanceOpts.securityGroups, "security-groups", "g", []string{}, securityGroupsSpec)
 
-	flags.StringVar(&instanceProfile, "instance-profile", "ecsInstanceRole", instanceProfileSpec)
+	flags.StringVar(&clustersAddInstanceOpts.instanceProfile, "instance-profile", "ecsInstanceRole", instanceProfileSpec)
 
-	flags.StringVarP(&key, "key", "k", "", keySpec)
-	flags.StringSliceVarP(&tags, "tag", "t", []string{}, tagsSpec)
-	flags.Int64Var(&minimum, "min", 1, minimumSpec)
-	flags.Int64Var(&maximum, "max", 1, maximumSpec)
-	flags.StringVar(&credit, "credit", "", creditSpec)
+	flags.StringVarP(&clustersAddInstanceOpts.key, "key", "k", "", keySpec)
+	flags.StringSliceVarP(&clustersAddInstanceOpts.tags, "tag", "t", []string{}, tagsSpec)
+	flags.Int64Var(&clustersAddInstanceOpts.minimum, "min", 1, minimumSpec)
+	flags.Int64Var(&clustersAddInstanceOpts.maximum, "max", 1, maximumSpec)
+	flags.StringVar(&clustersAddInstanceOpts.credit, "credit", "", creditSpec)
 
-	clustersAddSpotFleetCmd.MarkFlagRequired("subnet")
-	clustersAddSpotFleetCmd.MarkFlagRequired("instance-type")
+	clustersAddInstanceCmd.MarkFlagRequired("subnet")
+	clustersAddInstanceCmd.MarkFlagRequired("instance-type")
 }
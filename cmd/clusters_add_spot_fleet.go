@@ -3,14 +3,12 @@ package cmd
 import (
 	"bytes"
 	"encoding/base64"
-	"errors"
 	"html/template"
 	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/spf13/cobra"
 )
@@ -60,49 +58,60 @@ EOF
 chmod +x /usr/local/bin/spot-instance-termination-notice-handler.sh
 `
 
-func clustersAddSpotFleetRun(cmd *cobra.Command, clusters []string) {
-	clustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: []*string{
-			aws.String(clusters[0]),
-		},
-	})
-	typist.Must(err)
+type clustersAddSpotFleetOptions struct {
+	subnets            []string
+	instanceTypes      []string
+	securityGroups     []string
+	targetCapacity     int64
+	instanceProfile    string
+	spotFleetRole      string
+	allocationStrategy string
+	spotPrice          string
+	monitoring         bool
+	kernelID           string
+	ebs                bool
+	key                string
+	sigtermTimeout     string
+	tags               []string
+}
 
-	if len(clustersDescription.Clusters) == 0 {
-		typist.Must(errors.New("Cluster informed not found"))
-	}
+var clustersAddSpotFleetOpts clustersAddSpotFleetOptions
+
+func clustersAddSpotFleetRun(cmd *cobra.Command, clusters []string) {
+	opts := &clustersAddSpotFleetOpts
 
-	c := clustersDescription.Clusters[0]
+	c, err := describeCluster(clusters[0])
+	must(err)
 
 	tmpl, err := template.New("UserData").Parse(spotFleetUserData)
-	typist.Must(err)
+	must(err)
 
 	userDataF := new(bytes.Buffer)
-	typist.Must(tmpl.Execute(userDataF, templateUserData{
-		Cluster: *c.ClusterName,
-		SigtermTimeout: sigtermTimeout,
-		Region:  aws.StringValue(awsSession.Config.Region),
+	must(tmpl.Execute(userDataF, templateUserData{
+		Cluster:        *c.ClusterName,
+		SigtermTimeout: opts.sigtermTimeout,
+		Region:         aws.StringValue(awsSession.Config.Region),
 	}))
 
 	latestImage, err := latestAmiEcsOptimized()
-	typist.Must(err)
+	must(err)
 
 	// TODO: automaticaly --create-roles if does not exist
 	spotFleetRoleResponse, err := iamI.GetRole(&iam.GetRoleInput{
-		RoleName: aws.String(spotFleetRole),
+		RoleName: aws.String(opts.spotFleetRole),
 	})
-	typist.Must(err)
+	must(err)
 
 	// TODO: automaticaly --create-roles if does not exist
 	instanceProfileResponse, err := iamI.GetInstanceProfile(&iam.GetInstanceProfileInput{
-		InstanceProfileName: aws.String(instanceProfile),
+		InstanceProfileName: aws.String(opts.instanceProfile),
 	})
-	typist.Must(err)
+	must(err)
 
 	var SecurityGroups []*ec2.GroupIdentifier
-	for _, securityGroup := range securityGroups {
+	for _, securityGroup := range opts.securityGroups {
 		sg, err := findSecurityGroup(securityGroup)
-		typist.Must(err)
+		must(err)
 
 		SecurityGroups = append(SecurityGroups, &ec2.GroupIdentifier{
 			GroupId: sg.GroupId,
@@ -110,28 +119,28 @@ func clustersAddSpotFleetRun(cmd *cobra.Command, clusters []string) {
 	}
 
 	var subnetsIds []string
-	for _, subnet := range subnets {
+	for _, subnet := range opts.subnets {
 		Subnet, err := findSubnet(subnet)
-		typist.Must(err)
+		must(err)
 		subnetsIds = append(subnetsIds, aws.StringValue(Subnet.SubnetId))
 	}
 
 	var LaunchSpecifications []*ec2.SpotFleetLaunchSpecification
-	for _, instanceTypeAndWeight := range instanceTypes {
+	for _, instanceTypeAndWeight := range opts.instanceTypes {
 		iTWSlice := strings.Split(instanceTypeAndWeight, ":")
 		instanceType := iTWSlice[0]
 
 		var weight float64
 		if len(iTWSlice) > 1 {
 			weight, err = strconv.ParseFloat(iTWSlice[1], 64)
-			typist.Must(err)
+			must(err)
 		}
 
 		SpotFleetLaunchSpecification := ec2.SpotFleetLaunchSpecification{
 			IamInstanceProfile: &ec2.IamInstanceProfileSpecification{
 				Arn: instanceProfileResponse.InstanceProfile.Arn,
 			},
-			EbsOptimized:   aws.Bool(ebs),
+			EbsOptimized:   aws.Bool(opts.ebs),
 			ImageId:        latestImage.ImageId,
 			InstanceType:   aws.String(instanceType),
 			SecurityGroups: SecurityGroups,
@@ -143,23 +152,23 @@ func clustersAddSpotFleetRun(cmd *cobra.Command, clusters []string) {
 			SpotFleetLaunchSpecification.WeightedCapacity = aws.Float64(weight)
 		}
 
-		if kernelID != "" {
-			SpotFleetLaunchSpecification.KernelId = aws.String(kernelID)
+		if opts.kernelID != "" {
+			SpotFleetLaunchSpecification.KernelId = aws.String(opts.kernelID)
 		}
 
-		if key != "" {
-			SpotFleetLaunchSpecification.KeyName = aws.String(key)
+		if opts.key != "" {
+			SpotFleetLaunchSpecification.KeyName = aws.String(opts.key)
 		}
 
-		if monitoring {
-			SpotFleetLaunchSpecification.Monitoring = &ec2.SpotFleetMonitoring{Enabled: aws.Bool(monitoring)}
+		if opts.monitoring {
+			SpotFleetLaunchSpecification.Monitoring = &ec2.SpotFleetMonitoring{Enabled: aws.Bool(opts.monitoring)}
 		}
 
-		if len(tags) > 0 {
+		if len(opts.tags) > 0 {
 			SpotFleetLaunchSpecification.TagSpecifications = []*ec2.SpotFleetTagSpecification{
 				&ec2.SpotFleetTagSpecification{
 					ResourceType: aws.String("instance"),
-					Tags:         parseTags(tags),
+					Tags:         parseTags(opts.tags),
 				},
 			}
 		}
@@ -170,21 +179,21 @@ func clustersAddSpotFleetRun(cmd *cobra.Command, clusters []string) {
 	SpotFleetRequestConfig := ec2.SpotFleetRequestConfigData{
 		IamFleetRole:         spotFleetRoleResponse.Role.Arn,
 		LaunchSpecifications: LaunchSpecifications,
-		TargetCapacity:       aws.Int64(targetCapacity),
+		TargetCapacity:       aws.Int64(opts.targetCapacity),
 	}
 
-	if spotPrice != "" {
-		SpotFleetRequestConfig.SpotPrice = aws.String(spotPrice)
+	if opts.spotPrice != "" {
+		SpotFleetRequestConfig.SpotPrice = aws.String(opts.spotPrice)
 	}
 
-	if allocationStrategy != "" {
-		SpotFleetRequestConfig.AllocationStrategy = aws.String(allocationStrategy)
+	if opts.allocationStrategy != "" {
+		SpotFleetRequestConfig.AllocationStrategy = aws.String(opts.allocationStrategy)
 	}
 
 	_, err = ec2I.RequestSpotFleet(&ec2.RequestSpotFleetInput{
 		SpotFleetRequestConfig: &SpotFleetRequestConfig,
 	})
-	typist.Must(err)
+	must(err)
 }
 
 var clustersAddSpotFleetCmd = &cobra.Command{
@@ -201,22 +210,23 @@ func init() {
 	flags := clustersAddSpotFleetCmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringSliceVarP(&subnets, "subnet", "n", []string{}, requiredSpec+subnetsSpec)
-	flags.StringSliceVarP(&instanceTypes, "instance-type", "i", []string{}, requiredSpec+instanceTypesSpec)
-	flags.StringSliceVarP(&securityGroups, "security-group", "g", []string{}, requiredSpec+securityGroupsSpec)
-	flags.Int64VarP(&targetCapacity, "target-capacity", "c", 1, targetCapacitySpec)
-	flags.StringVar(&instanceProfile, "instance-profile", "ecsInstanceRole", instanceProfileSpec)
-	flags.StringVar(&spotFleetRole, "spot-fleet-role", "ecsSpotFleetRole", spotFleetRoleSpec)
-	flags.StringVarP(&allocationStrategy, "allocation-strategy", "s", "", allocationStrategySpec)
-	flags.StringVar(&spotPrice, "spot-price", "", spotPriceSpec)
-	flags.BoolVar(&monitoring, "monitoring", false, monitoringSpec)
-	flags.StringVar(&kernelID, "kernel-id", "", kernelIDSpec)
-	flags.BoolVar(&ebs, "ebs", false, ebsSpec)
-	flags.StringVarP(&key, "key", "k", "", keySpec)
-	flags.StringVar(&sigtermTimeout, "sigterm-timeout", "30s", sigtermTimeoutSpec)
-	flags.StringSliceVarP(&tags, "tag", "t", []string{}, tagsSpec)
-
-	clustersAddSpotFleetCmd.MarkFlagRequired("subnets")
-	clustersAddSpotFleetCmd.MarkFlagRequired("instance-types")
-	clustersAddSpotFleetCmd.MarkFlagRequired("security-groups")
+	flags.StringSliceVarP(&clustersAddSpotFleetOpts.subnets, "subnet", "n", []string{}, requiredSpec+subnetsSpec)
+	flags.StringSliceVarP(&clustersAddSpotFleetOpts.instanceTypes, "instance-type", "i", []string{}, requiredSpec+instanceTypesSpec)
+	flags.StringSliceVarP(&clustersAddSpotFleetOpts.securityGroups, "security-group", "g", []string{}, requiredSpec+securityGroupsSpec)
+	pluralNetworkFlags(flags)
+	flags.Int64VarP(&clustersAddSpotFleetOpts.targetCapacity, "target-capacity", "c", 1, targetCapacitySpec)
+	flags.StringVar(&clustersAddSpotFleetOpts.instanceProfile, "instance-profile", "ecsInstanceRole", instanceProfileSpec)
+	flags.StringVar(&clustersAddSpotFleetOpts.spotFleetRole, "spot-fleet-role", "ecsSpotFleetRole", spotFleetRoleSpec)
+	flags.StringVarP(&clustersAddSpotFleetOpts.allocationStrategy, "allocation-strategy", "s", "", allocationStrategySpec)
+	flags.StringVar(&clustersAddSpotFleetOpts.spotPrice, "spot-price", "", spotPriceSpec)
+	flags.BoolVar(&clustersAddSpotFleetOpts.monitoring, "monitoring", false, monitoringSpec)
+	flags.StringVar(&clustersAddSpotFleetOpts.kernelID, "kernel-id", "", kernelIDSpec)
+	flags.BoolVar(&clustersAddSpotFleetOpts.ebs, "ebs", false, ebsSpec)
+	flags.StringVarP(&clustersAddSpotFleetOpts.key, "key", "k", "", keySpec)
+	flags.StringVar(&clustersAddSpotFleetOpts.sigtermTimeout, "sigterm-timeout", "30s", sigtermTimeoutSpec)
+	flags.StringSliceVarP(&clustersAddSpotFleetOpts.tags, "tag", "t", []string{}, tagsSpec)
+
+	clustersAddSpotFleetCmd.MarkFlagRequired("subnet")
+	clustersAddSpotFleetCmd.MarkFlagRequired("instance-type")
+	clustersAddSpotFleetCmd.MarkFlagRequired("security-group")
 }
@@ -1,24 +1,31 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
 )
 
+type clustersCreateOptions struct {
+	tags []string
+}
+
+var clustersCreateOpts clustersCreateOptions
+
 func clustersCreateRun(cmd *cobra.Command, clusters []string) {
+	opts := &clustersCreateOpts
+
+	tags, err := parseResourceTags(opts.tags)
+	must(err)
+
 	for _, cluster := range clusters {
-		_, err := ecsI.CreateCluster(&ecs.CreateClusterInput{
+		result, err := ecsI.CreateCluster(&ecs.CreateClusterInput{
 			ClusterName: aws.String(cluster),
+			Tags:        resourceTags(tags),
 		})
+		must(err)
 
-		if err != nil {
-			fmt.Println(err.Error())
-			os.Exit(1)
-		}
+		printAffected(aws.StringValue(result.Cluster.ClusterArn), cluster+" created")
 	}
 }
 
@@ -30,4 +37,8 @@ var clustersCreateCmd = &cobra.Command{
 
 func init() {
 	clustersCmd.AddCommand(clustersCreateCmd)
+
+	flags := clustersCreateCmd.Flags()
+
+	flags.StringSliceVarP(&clustersCreateOpts.tags, "tag", "t", []string{}, resourceTagsSpec)
 }
@@ -6,19 +6,30 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
 	"github.com/spf13/cobra"
 )
 
+type clustersDeleteOptions struct {
+	yes   bool
+	force bool
+}
+
+var clustersDeleteOpts clustersDeleteOptions
+
 func clustersDeleteRun(cmd *cobra.Command, clusters []string) {
-	clustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: aws.StringSlice(clusters),
-	})
-	typist.Must(err)
+	opts := &clustersDeleteOpts
+
+	foundClusters, err := ecsxI.DescribeAllClusters(aws.StringSlice(clusters))
+
+	var failures *ecsx.FailuresError
+	if !errors.As(err, &failures) {
+		must(err)
+	}
 
 	var missing []string
 	var activeClusters []*ecs.Cluster
 
-	foundClusters := clustersDescription.Clusters
 	for _, cluster := range foundClusters {
 		if aws.StringValue(cluster.Status) == "ACTIVE" {
 			activeClusters = append(activeClusters, cluster)
@@ -27,15 +38,17 @@ func clustersDeleteRun(cmd *cobra.Command, clusters []string) {
 		}
 	}
 
-	for _, notFound := range clustersDescription.Failures {
-		missing = append(missing, aws.StringValue(notFound.Arn))
+	if failures != nil {
+		for _, notFound := range failures.Failures {
+			missing = append(missing, aws.StringValue(notFound.Arn))
+		}
 	}
 
-	if !force && len(missing) > 0 {
-		typist.Must(errors.New("Some clusters were not found:\n\t" + strings.Join(missing, "\n\t")))
+	if !opts.force && len(missing) > 0 {
+		must(errors.New("Some clusters were not found:\n\t" + strings.Join(missing, "\n\t")))
 	}
 
-	if !force && !yes && len(activeClusters) > 0 {
+	if !opts.force && !opts.yes && len(activeClusters) > 0 {
 		typist.Println("clusters to be deleted:")
 		for _, cluster := range activeClusters {
 			typist.Println(aws.StringValue(cluster.ClusterArn))
@@ -51,7 +64,7 @@ func clustersDeleteRun(cmd *cobra.Command, clusters []string) {
 			Cluster: cluster.ClusterArn,
 		})
 
-		typist.Must(err)
+		must(err)
 
 		typist.Printf("%s deleted\n", aws.StringValue(cluster.ClusterArn))
 	}
@@ -67,6 +80,10 @@ var clustersDeleteCmd = &cobra.Command{
 func init() {
 	clustersCmd.AddCommand(clustersDeleteCmd)
 	flags := clustersDeleteCmd.Flags()
-	flags.BoolVarP(&yes, "yes", "y", false, yesSpec)
-	flags.BoolVarP(&force, "force", "f", false, forceSpec)
+	flags.BoolVarP(&clustersDeleteOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVarP(&clustersDeleteOpts.force, "force", "f", false, forceSpec)
+
+	protectClusters(clustersDeleteCmd, "delete the cluster", func(clusters []string) []string {
+		return clusters
+	})
 }
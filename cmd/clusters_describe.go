@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type instanceTypeCount struct {
+	InstanceType string `json:"instanceType"`
+	Count        int    `json:"count"`
+	Connected    int    `json:"connected"`
+	Disconnected int    `json:"disconnected"`
+}
+
+type clusterDescription struct {
+	clusterRow
+	Statistics    map[string]string    `json:"statistics"`
+	Settings      map[string]string    `json:"settings"`
+	Tags          map[string]string    `json:"tags"`
+	InstanceTypes []*instanceTypeCount `json:"instanceTypes"`
+}
+
+// countInstanceTypes breaks the container instances down by instance type and agent connectivity.
+// Instances without the instance type attribute (e.g. external ones) are counted as "-".
+func countInstanceTypes(instances []*ecs.ContainerInstance) (counts []*instanceTypeCount) {
+	byType := make(map[string]*instanceTypeCount)
+	for _, ci := range instances {
+		instanceType, found := instanceAttribute(ci, "ecs.instance-type")
+		if !found {
+			instanceType = "-"
+		}
+
+		count := byType[instanceType]
+		if count == nil {
+			count = &instanceTypeCount{InstanceType: instanceType}
+			byType[instanceType] = count
+			counts = append(counts, count)
+		}
+
+		count.Count++
+		if aws.BoolValue(ci.AgentConnected) {
+			count.Connected++
+		} else {
+			count.Disconnected++
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].InstanceType < counts[j].InstanceType
+	})
+	return
+}
+
+func newClusterDescription(c *ecs.Cluster) (d *clusterDescription, err error) {
+	d = &clusterDescription{
+		clusterRow: *newClusterRow(c),
+		Statistics: make(map[string]string),
+		Settings:   make(map[string]string),
+		Tags:       make(map[string]string),
+	}
+
+	for _, s := range c.Statistics {
+		d.Statistics[aws.StringValue(s.Name)] = aws.StringValue(s.Value)
+	}
+
+	for _, s := range c.Settings {
+		d.Settings[aws.StringValue(s.Name)] = aws.StringValue(s.Value)
+	}
+
+	for _, t := range c.Tags {
+		d.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+
+	instances, err := describeContainerInstances(aws.StringValue(c.ClusterArn))
+	if err != nil {
+		return
+	}
+
+	d.InstanceTypes = countInstanceTypes(instances)
+	return
+}
+
+func sortedPairs(values map[string]string) string {
+	var pairs []string
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	if len(pairs) == 0 {
+		return "-"
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func printClusterDescription(d *clusterDescription) {
+	providers := strings.Join(d.CapacityProviders, ", ")
+	if providers == "" {
+		providers = "-"
+	}
+
+	typist.Printf("Cluster:            %s\n", d.ClusterName)
+	typist.Printf("ARN:                %s\n", d.ClusterArn)
+	typist.Printf("Status:             %s\n", d.Status)
+	typist.Printf("Services:           %d active\n", d.ActiveServicesCount)
+	typist.Printf("Tasks:              %d running, %d pending\n", d.RunningTasksCount, d.PendingTasksCount)
+	typist.Printf("Instances:          %d registered\n", d.RegisteredContainerInstancesCount)
+	typist.Printf("Capacity Providers: %s\n", providers)
+	typist.Printf("Settings:           %s\n", sortedPairs(d.Settings))
+	typist.Printf("Tags:               %s\n", sortedPairs(d.Tags))
+
+	if len(d.InstanceTypes) == 0 {
+		return
+	}
+
+	typist.Println("Instance Types:")
+	w := newTable()
+	fmt.Fprintln(w, "  TYPE\tCOUNT\tCONNECTED\tDISCONNECTED")
+	for _, count := range d.InstanceTypes {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\n", count.InstanceType, count.Count, count.Connected, count.Disconnected)
+	}
+	w.Flush()
+}
+
+func clustersDescribeRun(cmd *cobra.Command, args []string) {
+	must(checkOutputFormat(outputFormat, "text", "json"))
+
+	var clusters []*string
+	for _, name := range args {
+		clusters = append(clusters, aws.String(resolveClusterAlias(name)))
+	}
+
+	if len(clusters) == 0 {
+		var err error
+		clusters, err = listClusters()
+		must(err)
+	}
+
+	described, err := ecsxI.DescribeAllClusters(clusters, ecs.ClusterFieldStatistics, ecs.ClusterFieldTags, ecs.ClusterFieldSettings)
+	must(err)
+
+	descriptions := []*clusterDescription{}
+	for _, c := range described {
+		d, err := newClusterDescription(c)
+		must(err)
+
+		descriptions = append(descriptions, d)
+	}
+
+	if outputFormat == "json" {
+		must(printJSON(descriptions))
+		return
+	}
+
+	for i, d := range descriptions {
+		if quiet {
+			printID(d.ClusterArn)
+			continue
+		}
+
+		if i > 0 {
+			typist.Println("")
+		}
+		printClusterDescription(d)
+	}
+}
+
+var clustersDescribeCmd = &cobra.Command{
+	Use:         "describe [clusters...]",
+	Short:       "Describe clusters: tasks, services, container instances by type and capacity providers",
+	Run:         clustersDescribeRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	clustersCmd.AddCommand(clustersDescribeCmd)
+
+	registerOutputSchema(clustersDescribeCmd, "clusters describe", []*clusterDescription{})
+}
@@ -2,47 +2,88 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
 )
 
+type clusterRow struct {
+	ClusterArn                        string   `json:"clusterArn"`
+	ClusterName                       string   `json:"clusterName"`
+	Status                            string   `json:"status"`
+	ActiveServicesCount               int64    `json:"activeServicesCount"`
+	RunningTasksCount                 int64    `json:"runningTasksCount"`
+	PendingTasksCount                 int64    `json:"pendingTasksCount"`
+	RegisteredContainerInstancesCount int64    `json:"registeredContainerInstancesCount"`
+	CapacityProviders                 []string `json:"capacityProviders"`
+}
+
+func newClusterRow(c *ecs.Cluster) *clusterRow {
+	return &clusterRow{
+		ClusterArn:                        aws.StringValue(c.ClusterArn),
+		ClusterName:                       aws.StringValue(c.ClusterName),
+		Status:                            aws.StringValue(c.Status),
+		ActiveServicesCount:               aws.Int64Value(c.ActiveServicesCount),
+		RunningTasksCount:                 aws.Int64Value(c.RunningTasksCount),
+		PendingTasksCount:                 aws.Int64Value(c.PendingTasksCount),
+		RegisteredContainerInstancesCount: aws.Int64Value(c.RegisteredContainerInstancesCount),
+		CapacityProviders:                 aws.StringValueSlice(c.CapacityProviders),
+	}
+}
+
 func clustersListRun(cmd *cobra.Command, clusters []string) {
-	input := &ecs.ListClustersInput{}
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	clusterArns, err := listClusters()
+	must(err)
 
-	var nextToken *string
-	for {
-		if nextToken != nil {
-			input.NextToken = nextToken
+	if outputFormat == "text" || quiet {
+		for _, arn := range clusterArns {
+			printID(aws.StringValue(arn))
 		}
+		return
+	}
 
-		result, err := ecsI.ListClusters(input)
+	described, err := ecsxI.DescribeAllClusters(clusterArns)
+	must(err)
 
-		if err != nil {
-			fmt.Println(err.Error())
-			os.Exit(1)
-		}
+	rows := []*clusterRow{}
+	for _, c := range described {
+		rows = append(rows, newClusterRow(c))
+	}
 
-		for _, f := range result.ClusterArns {
-			fmt.Println(aws.StringValue(f))
-		}
+	if outputFormat == "json" {
+		must(printJSON(rows))
+		return
+	}
 
-		if result.NextToken == nil {
-			break
+	w := newTable()
+	fmt.Fprintln(w, "NAME\tSTATUS\tSERVICES\tRUNNING\tPENDING\tINSTANCES\tCAPACITY PROVIDERS")
+	for _, row := range rows {
+		providers := strings.Join(row.CapacityProviders, ",")
+		if providers == "" {
+			providers = "-"
 		}
 
-		nextToken = result.NextToken
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", row.ClusterName, row.Status, row.ActiveServicesCount,
+			row.RunningTasksCount, row.PendingTasksCount, row.RegisteredContainerInstancesCount, providers)
 	}
+	w.Flush()
 }
 
 var clustersListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List clusters",
-	Run:   clustersListRun,
+	Use:         "list",
+	Short:       "List clusters",
+	Run:         clustersListRun,
+	Annotations: pagedOutput,
 }
 
 func init() {
 	clustersCmd.AddCommand(clustersListCmd)
+
+	registerOutputSchema(clustersListCmd, "clusters list", []*clusterRow{})
+
+	getAlias(clustersListCmd, "clusters", "cluster")
 }
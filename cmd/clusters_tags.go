@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+func clustersTagsRun(cmd *cobra.Command, args []string) {
+	c, err := describeCluster(args[0])
+	must(err)
+
+	result, err := ecsI.ListTagsForResource(&ecs.ListTagsForResourceInput{
+		ResourceArn: c.ClusterArn,
+	})
+	must(err)
+
+	if quiet {
+		for _, tag := range result.Tags {
+			printID(aws.StringValue(tag.Key))
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE")
+	for _, tag := range result.Tags {
+		fmt.Fprintf(w, "%s\t%s\n", aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+	}
+	w.Flush()
+}
+
+var clustersTagsCmd = &cobra.Command{
+	Use:   "tags [cluster]",
+	Short: "Show, set or remove the tags of a cluster",
+	Args:  cobra.ExactArgs(1),
+	Run:   clustersTagsRun,
+}
+
+func init() {
+	clustersCmd.AddCommand(clustersTagsCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+func clustersTagsRmRun(cmd *cobra.Command, args []string) {
+	c, err := describeCluster(args[0])
+	must(err)
+
+	_, err = ecsI.UntagResource(&ecs.UntagResourceInput{
+		ResourceArn: c.ClusterArn,
+		TagKeys:     aws.StringSlice(args[1:]),
+	})
+	must(err)
+}
+
+var clustersTagsRmCmd = &cobra.Command{
+	Use:   "rm [cluster] [keys...]",
+	Short: "Remove tags of a cluster",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   clustersTagsRmRun,
+}
+
+func init() {
+	clustersTagsCmd.AddCommand(clustersTagsRmCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+func clustersTagsSetRun(cmd *cobra.Command, args []string) {
+	tags, err := parseResourceTags(args[1:])
+	must(err)
+
+	if len(tags) == 0 {
+		must(errors.New("No tag informed"))
+	}
+
+	c, err := describeCluster(args[0])
+	must(err)
+
+	_, err = ecsI.TagResource(&ecs.TagResourceInput{
+		ResourceArn: c.ClusterArn,
+		Tags:        tags,
+	})
+	must(err)
+}
+
+var clustersTagsSetCmd = &cobra.Command{
+	Use:   "set [cluster] [key=value...]",
+	Short: "Set tags of a cluster",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   clustersTagsSetRun,
+}
+
+func init() {
+	clustersTagsCmd.AddCommand(clustersTagsSetCmd)
+}
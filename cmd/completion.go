@@ -1,28 +1,179 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
 )
 
+// completionTimeout bounds the API calls of a completion, the shell is waiting for them
+const completionTimeout = 3 * time.Second
+
+// completionECS is an ECS client without retries for the completions, nil when no session can be created
+// (e.g. a missing profile), so the completions are only empty and never break the shell
+func completionECS() *ecs.ECS {
+	s, err := newAWSSession()
+	if err != nil {
+		return nil
+	}
+
+	return ecs.New(s, aws.NewConfig().WithMaxRetries(0).WithHTTPClient(&http.Client{Timeout: completionTimeout}))
+}
+
+// completeNames keeps the names starting with what was typed and not informed yet, sorted
+func completeNames(names []string, args []string, toComplete string) (candidates []string) {
+	informed := make(map[string]bool)
+	for _, arg := range args {
+		informed[arg] = true
+	}
+
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) && !informed[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return
+}
+
+// resourceName is the last part of an ARN, e.g. the name of a cluster or of a service
+func resourceName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// completeClusters offers the clusters of the region along with the aliases of the config file
+func completeClusters(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	candidates, _ := completeClusterAliases(cmd, args, toComplete)
+
+	client := completionECS()
+	if client == nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	var names []string
+	client.ListClustersPagesWithContext(ctx, &ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		for _, arn := range page.ClusterArns {
+			names = append(names, resourceName(aws.StringValue(arn)))
+		}
+		return !lastPage
+	})
+
+	return append(candidates, completeNames(names, nil, toComplete)...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionCluster is the cluster informed on the command line being completed, or the default one
+func completionCluster(cmd *cobra.Command) string {
+	cluster, _ := cmd.Flags().GetString("cluster")
+	if cluster == "" {
+		cluster, _ = defaultCluster()
+	}
+
+	if aliased, ok := clusterAliases()[strings.ToLower(cluster)]; ok {
+		return aliased
+	}
+	return cluster
+}
+
+// completeServices offers the services of the cluster informed, nothing before a cluster is known
+func completeServices(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cluster := completionCluster(cmd)
+	client := completionECS()
+	if cluster == "" || client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	var names []string
+	client.ListServicesPagesWithContext(ctx, &ecs.ListServicesInput{Cluster: aws.String(cluster)}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		for _, arn := range page.ServiceArns {
+			names = append(names, resourceName(aws.StringValue(arn)))
+		}
+		return !lastPage
+	})
+
+	return completeNames(names, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskDefinitionFamilies offers the families with an ACTIVE revision, starting with what was typed
+func completeTaskDefinitionFamilies(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client := completionECS()
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	input := &ecs.ListTaskDefinitionFamiliesInput{Status: aws.String(ecs.TaskDefinitionFamilyStatusActive)}
+	if toComplete != "" {
+		input.FamilyPrefix = aws.String(toComplete)
+	}
+
+	var families []string
+	client.ListTaskDefinitionFamiliesPagesWithContext(ctx, input, func(page *ecs.ListTaskDefinitionFamiliesOutput, lastPage bool) bool {
+		families = append(families, aws.StringValueSlice(page.Families)...)
+		return !lastPage
+	})
+
+	return completeNames(families, nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerResourceCompletion completes the arguments naming existing services or Task Definitions, as told by
+// their usage, and the --service flags of every command
+func registerResourceCompletion(cmd *cobra.Command) {
+	if cmd.ValidArgsFunction == nil {
+		switch use := cmd.Use; {
+		case cmd == servicesCreateCmd || cmd == servicesQuickstartCmd:
+		case cmd.Parent() == servicesCmd && strings.Contains(use, "[service"):
+			cmd.ValidArgsFunction = completeServices
+		case cmd.Parent() == taskDefinitionsCmd && (strings.Contains(use, "[family") || strings.Contains(use, "[task-definition]")):
+			cmd.ValidArgsFunction = completeTaskDefinitionFamilies
+		}
+	}
+
+	if flag := cmd.Flags().Lookup("service"); flag != nil && flag.Value.Type() == "string" {
+		cmd.RegisterFlagCompletionFunc("service", completeServices)
+	}
+
+	for _, child := range cmd.Commands() {
+		registerResourceCompletion(child)
+	}
+}
+
 func completionRun(cmd *cobra.Command, args []string) {
 	switch args[0] {
 	case "bash":
-		rootCmd.GenBashCompletion(os.Stdout)
+		rootCmd.GenBashCompletionV2(os.Stdout, true)
 	case "zsh":
 		rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
 	default:
-		typist.Must(errors.New("specied shell is not yet supported"))
+		must(errors.New("Unsupported shell " + args[0] + ", expected bash, zsh, fish or powershell"))
 	}
 }
 
 var completionCmd = &cobra.Command{
-	Use:   "completion [shell]",
-	Short: "Output the completion script for the specified shell language ('bash' or 'zsh')",
-	Args:  cobra.ExactArgs(1),
-	Run:   completionRun,
+	Use:       "completion [shell]",
+	Short:     "Output the completion script for the specified shell ('bash', 'zsh', 'fish' or 'powershell')",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Run:       completionRun,
 }
 
 func init() {
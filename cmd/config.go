@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type configKey struct {
+	kind     string
+	validate func(value interface{}) error
+}
+
+// configSchema has every key accepted on the top level of the config file
+var configSchema = map[string]configKey{
+	"profile": {kind: "string"},
+	"cluster": {kind: "string"},
+	"region":  {kind: "string", validate: validateRegion},
+	"quiet":   {kind: "bool"},
+	"debug":   {kind: "bool"},
+
+	"default_tags":       {kind: "map", validate: validateDefaultTags},
+	"protected_clusters": {kind: "list", validate: validateProtectedClusters},
+	"cluster_aliases":    {kind: "map", validate: validateClusterAliases},
+	"known_regions":      {kind: "list", validate: validateKnownRegions},
+
+	"default_subnets":         {kind: "list"},
+	"default_security_groups": {kind: "list"},
+
+	"audit_log": {kind: "string"},
+}
+
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d$`)
+
+func validateRegion(value interface{}) error {
+	if !regionPattern.MatchString(value.(string)) {
+		return fmt.Errorf("'%s' is not a valid AWS region (e.g. us-east-1)", value)
+	}
+	return nil
+}
+
+func validateDefaultTags(value interface{}) error {
+	for key, v := range value.(map[string]interface{}) {
+		switch v.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return fmt.Errorf("default_tags.%s must be a string, got %s", key, configValueKind(v))
+		}
+	}
+	return nil
+}
+
+// defaultTags reads the default_tags of the config file.
+// Viper lowercases the keys, but tag keys are case sensitive, so their original case is looked up on the file.
+func defaultTags() (tags map[string]string) {
+	tags = make(map[string]string)
+
+	settings, content, err := readConfigFile()
+	if err != nil {
+		return
+	}
+
+	values, ok := settings["default_tags"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for key, value := range values {
+		tags[originalKeyCase(content, key)] = fmt.Sprint(value)
+	}
+	return
+}
+
+// originalKeyCase finds how a key is written on the config file, the key itself when not found
+func originalKeyCase(content []byte, key string) string {
+	pattern := regexp.MustCompile(`(?im)^[\s{]*"?(` + regexp.QuoteMeta(key) + `)"?\s*[:=]`)
+
+	if match := pattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return key
+}
+
+// resourceTags merges the default_tags of the config file into the tags of resources created by ecsctl.
+// The explicit tags take precedence.
+func resourceTags(explicit []*ecs.Tag) (tags []*ecs.Tag) {
+	merged := defaultTags()
+	for _, tag := range explicit {
+		merged[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	var keys []string
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		tags = append(tags, &ecs.Tag{Key: aws.String(key), Value: aws.String(merged[key])})
+	}
+	return
+}
+
+type configProblem struct {
+	key     string
+	line    int
+	message string
+}
+
+func (p configProblem) String() string {
+	if p.line > 0 {
+		return fmt.Sprintf("%s:%d: %s", viper.ConfigFileUsed(), p.line, p.message)
+	}
+	return fmt.Sprintf("%s: %s", viper.ConfigFileUsed(), p.message)
+}
+
+// configKeyLine finds the line where a top-level key is set, 0 when not found
+func configKeyLine(content []byte, key string) int {
+	pattern := regexp.MustCompile(`^("` + regexp.QuoteMeta(key) + `"|` + regexp.QuoteMeta(key) + `)\s*[:=]`)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		if pattern.MatchString(strings.TrimLeft(scanner.Text(), "{ \t")) {
+			return line
+		}
+	}
+	return 0
+}
+
+func configValueKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int64, float64:
+		return "number"
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// readConfigFile reads only the settings of the config file, ignoring flags and environment variables
+func readConfigFile() (settings map[string]interface{}, content []byte, err error) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return
+	}
+
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err = v.ReadInConfig(); err != nil {
+		return
+	}
+
+	settings = v.AllSettings()
+	return
+}
+
+// validateConfig checks the config file against the configSchema
+func validateConfig(onlyUnknown bool) (problems []configProblem, err error) {
+	settings, content, err := readConfigFile()
+	if err != nil {
+		return
+	}
+
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := settings[key]
+		line := configKeyLine(content, key)
+
+		schema, known := configSchema[key]
+		if !known {
+			problems = append(problems, configProblem{key, line, fmt.Sprintf("unknown key '%s'", key)})
+			continue
+		}
+
+		if onlyUnknown {
+			continue
+		}
+
+		if kind := configValueKind(value); kind != schema.kind {
+			problems = append(problems, configProblem{key, line, fmt.Sprintf("'%s' must be a %s, got %s", key, schema.kind, kind)})
+			continue
+		}
+
+		if schema.validate != nil {
+			if err := schema.validate(value); err != nil {
+				problems = append(problems, configProblem{key, line, err.Error()})
+			}
+		}
+	}
+
+	return
+}
+
+// warnUnknownConfigKeys warns, without stopping the execution, about keys that would be silently ignored
+func warnUnknownConfigKeys() {
+	problems, err := validateConfig(true)
+	if err != nil {
+		return
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "warning: %s (see 'ecsctl config validate')\n", problem)
+	}
+}
+
+func configRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config [command]",
+	Short: "Commands to manage the ecsctl config file",
+	Run:   configRun,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
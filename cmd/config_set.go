@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var plainYAMLPattern = regexp.MustCompile(`^[A-Za-z0-9_./:@-]+$`)
+
+// configFilePath is the config file in use, or the one to be created
+func configFilePath() (string, error) {
+	if file := viper.ConfigFileUsed(); file != "" {
+		return file, nil
+	}
+
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ecsctl.yaml"), nil
+}
+
+// setConfigLine replaces the line of the top-level key, or appends it, keeping the rest of the file as written
+func setConfigLine(content []byte, key, value string) []byte {
+	line := key + ": " + value
+
+	lines := strings.Split(string(content), "\n")
+	if n := configKeyLine(content, key); n > 0 {
+		lines[n-1] = line
+		return []byte(strings.Join(lines, "\n"))
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	return append(content, []byte(line+"\n")...)
+}
+
+func configSetRun(cmd *cobra.Command, args []string) {
+	key, value := args[0], args[1]
+
+	schema, known := configSchema[key]
+	if !known {
+		must(fmt.Errorf("Unknown key '%s'", key))
+	}
+
+	// Lists and maps are edited on the file, a single line can not hold them
+	var parsed interface{} = value
+	switch schema.kind {
+	case "string":
+		if !plainYAMLPattern.MatchString(value) {
+			value = strconv.Quote(value)
+		}
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			must(fmt.Errorf("'%s' must be true or false", key))
+		}
+		parsed, value = b, strconv.FormatBool(b)
+	default:
+		must(fmt.Errorf("'%s' is a %s, edit it on the config file", key, schema.kind))
+	}
+
+	if schema.validate != nil {
+		must(schema.validate(parsed))
+	}
+
+	file, err := configFilePath()
+	must(err)
+
+	if ext := filepath.Ext(file); ext != "" && ext != ".yaml" && ext != ".yml" {
+		must(errors.New("Only YAML config files can be changed, edit " + file))
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		must(err)
+	}
+
+	must(os.WriteFile(file, setConfigLine(content, key, value), 0600))
+
+	printAffected(key, fmt.Sprintf("%s set to %s on %s", key, args[1], file))
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a key of the config file, e.g. the default cluster",
+	Args:  cobra.ExactArgs(2),
+	Run:   configSetRun,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+}
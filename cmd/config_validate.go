@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func configValidateRun(cmd *cobra.Command, args []string) {
+	if viper.ConfigFileUsed() == "" {
+		must(errors.New("No config file found"))
+	}
+
+	problems, err := validateConfig(false)
+	must(err)
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	if len(problems) > 0 {
+		must(fmt.Errorf("%d problem(s) found", len(problems)))
+	}
+
+	typist.Printf("%s is valid\n", viper.ConfigFileUsed())
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the config file, reporting unknown keys and invalid values",
+	Args:  cobra.NoArgs,
+	Run:   configValidateRun,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
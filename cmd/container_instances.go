@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+// resolveContainerInstances describes the informed container instances, accepting their ARN, ID
+// or the EC2 instance ID, which is the one usually at hand (e.g. from an Auto Scaling event)
+func resolveContainerInstances(cluster string, refs []string) (resolved []*ecs.ContainerInstance, err error) {
+	instances, err := describeContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	for _, ref := range refs {
+		var found *ecs.ContainerInstance
+		for _, ci := range instances {
+			arn := aws.StringValue(ci.ContainerInstanceArn)
+			if ref == arn || ref == arn[strings.LastIndex(arn, "/")+1:] || ref == aws.StringValue(ci.Ec2InstanceId) {
+				found = ci
+				break
+			}
+		}
+
+		if found == nil {
+			err = fmt.Errorf("Container instance %s not found on cluster %s", ref, cluster)
+			return
+		}
+
+		resolved = append(resolved, found)
+	}
+	return
+}
+
+// containerInstanceTasks lists and describes the tasks placed on the container instance
+func containerInstanceTasks(cluster string, ci *ecs.ContainerInstance) (tasks []*ecs.Task, err error) {
+	taskArns, err := ecsxI.ListAllTasks(&ecs.ListTasksInput{
+		Cluster:           aws.String(cluster),
+		ContainerInstance: ci.ContainerInstanceArn,
+	})
+	if err != nil {
+		return
+	}
+
+	return describeTasks(cluster, taskArns)
+}
+
+// instancesAlias mirrors a container-instances command as 'clusters instances'.
+// It must be called on the init of the canonical command, after its flags are defined.
+func instancesAlias(canonical *cobra.Command) *cobra.Command {
+	return mirrorCommand(clustersInstancesCmd, canonical, canonical.Use)
+}
+
+func containerInstancesRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var containerInstancesCmd = &cobra.Command{
+	Use:     "container-instances [command]",
+	Short:   "Commands to manage the container instances of EC2 backed clusters",
+	Aliases: []string{"container-instance", "instances", "ci"},
+	Run:     containerInstancesRun,
+}
+
+var clustersInstancesCmd = &cobra.Command{
+	Use:   "instances [command]",
+	Short: "Aliases of the container-instances commands",
+	Run:   containerInstancesRun,
+}
+
+func init() {
+	rootCmd.AddCommand(containerInstancesCmd)
+	clustersCmd.AddCommand(clustersInstancesCmd)
+}
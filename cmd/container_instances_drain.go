@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type containerInstancesDrainOptions struct {
+	cluster   string
+	batchSize int
+	plan      bool
+	yes       bool
+	wait      bool
+	timeout   time.Duration
+}
+
+var containerInstancesDrainOpts containerInstancesDrainOptions
+
+// drainServiceImpact is how a service is affected by draining a batch of instances
+type drainServiceImpact struct {
+	service        string
+	moving         int64
+	desired        int64
+	minimumHealthy int64
+}
+
+// floor is the least running tasks the service may have while its tasks are moved.
+// With minimumHealthyPercent below 100 ECS may stop tasks before their replacements are running.
+func (i drainServiceImpact) floor() int64 {
+	if i.minimumHealthy >= 100 {
+		return i.desired
+	}
+
+	healthy := int64(math.Ceil(float64(i.desired) * float64(i.minimumHealthy) / 100))
+	if left := i.desired - i.moving; left > healthy {
+		return left
+	}
+	return healthy
+}
+
+type drainBatch struct {
+	instances []*ecs.ContainerInstance
+	tasks     int64
+	services  []drainServiceImpact
+}
+
+// planDrain splits the instances in batches, in the informed order, and finds out the services affected by each
+func planDrain(cluster string, instances []*ecs.ContainerInstance, batchSize int) (batches []drainBatch, err error) {
+	if batchSize < 1 {
+		batchSize = len(instances)
+	}
+
+	for start := 0; start < len(instances); start += batchSize {
+		end := start + batchSize
+		if end > len(instances) {
+			end = len(instances)
+		}
+
+		batch := drainBatch{instances: instances[start:end]}
+		moving := make(map[string]int64)
+
+		for _, ci := range batch.instances {
+			batch.tasks += aws.Int64Value(ci.RunningTasksCount) + aws.Int64Value(ci.PendingTasksCount)
+
+			tasks, err := containerInstanceTasks(cluster, ci)
+			if err != nil {
+				return batches, err
+			}
+
+			for _, t := range tasks {
+				if group := aws.StringValue(t.Group); strings.HasPrefix(group, "service:") {
+					moving[strings.TrimPrefix(group, "service:")]++
+				}
+			}
+		}
+
+		var names []*string
+		for name := range moving {
+			names = append(names, aws.String(name))
+		}
+
+		services, err := describeServices(cluster, names)
+		if err != nil {
+			return batches, err
+		}
+
+		for _, s := range services {
+			impact := drainServiceImpact{
+				service:        aws.StringValue(s.ServiceName),
+				moving:         moving[aws.StringValue(s.ServiceName)],
+				desired:        aws.Int64Value(s.DesiredCount),
+				minimumHealthy: 100,
+			}
+
+			if s.DeploymentConfiguration != nil && s.DeploymentConfiguration.MinimumHealthyPercent != nil {
+				impact.minimumHealthy = aws.Int64Value(s.DeploymentConfiguration.MinimumHealthyPercent)
+			}
+
+			batch.services = append(batch.services, impact)
+		}
+
+		sort.Slice(batch.services, func(i, j int) bool { return batch.services[i].service < batch.services[j].service })
+
+		batches = append(batches, batch)
+	}
+	return
+}
+
+func printDrainPlan(batches []drainBatch) {
+	if quiet {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, batch := range batches {
+		var ids []string
+		for _, ci := range batch.instances {
+			ids = append(ids, aws.StringValue(ci.Ec2InstanceId))
+		}
+
+		fmt.Fprintf(w, "batch %d:\t%s\t%d tasks to move\n", i+1, strings.Join(ids, ", "), batch.tasks)
+
+		for _, impact := range batch.services {
+			dip := "stays at desired count"
+			if floor := impact.floor(); floor < impact.desired {
+				dip = fmt.Sprintf("may dip to %d (minimumHealthyPercent %d)", floor, impact.minimumHealthy)
+			}
+
+			fmt.Fprintf(w, "\t%s\t%d of %d tasks move, %s\n", impact.service, impact.moving, impact.desired, dip)
+		}
+	}
+	w.Flush()
+}
+
+// waitDrained waits until no task is left on the container instances, or the context is canceled.
+// The instances still running tasks are printed whenever their count changes.
+func waitDrained(ctx context.Context, cluster string, instances []*ecs.ContainerInstance, timeout time.Duration) (err error) {
+	var arns []*string
+	for _, ci := range instances {
+		arns = append(arns, ci.ContainerInstanceArn)
+	}
+
+	lastProgress := ""
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		described, err := ecsxI.DescribeAllContainerInstances(cluster, arns)
+		if err != nil {
+			return err
+		}
+
+		var left int64
+		var progress []string
+		for _, ci := range described {
+			tasks := aws.Int64Value(ci.RunningTasksCount) + aws.Int64Value(ci.PendingTasksCount)
+			if tasks > 0 {
+				progress = append(progress, fmt.Sprintf("%s %d", aws.StringValue(ci.Ec2InstanceId), tasks))
+			}
+			left += tasks
+		}
+
+		if left == 0 {
+			return nil
+		}
+
+		if p := strings.Join(progress, ", "); p != lastProgress {
+			typist.Printf("%d tasks left: %s\n", left, p)
+			lastProgress = p
+		}
+
+		if !sleepContext(ctx, 10*time.Second) {
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("Container instances still running tasks after %s", timeout)
+}
+
+func containerInstancesDrainRun(cmd *cobra.Command, args []string) {
+	opts := &containerInstancesDrainOpts
+
+	instances, err := resolveContainerInstances(opts.cluster, args)
+	must(err)
+
+	batches, err := planDrain(opts.cluster, instances, opts.batchSize)
+	must(err)
+
+	printDrainPlan(batches)
+
+	if opts.plan {
+		return
+	}
+
+	if !opts.yes && !typist.Confirm("Do you really want to drain these container instances?") {
+		must(errors.New("Canceled"))
+	}
+
+	ctx := interruptContext()
+	interruptedAt := func(drained int) {
+		fmt.Fprintf(os.Stderr, "drained %d of %d instances before interruption\n", drained, len(instances))
+		exit(130)
+	}
+
+	drained := 0
+	for i, batch := range batches {
+		if ctx.Err() != nil {
+			interruptedAt(drained)
+		}
+
+		var arns []*string
+		for _, ci := range batch.instances {
+			arns = append(arns, ci.ContainerInstanceArn)
+		}
+
+		// UpdateContainerInstancesState accepts up to 10 instances per request
+		for start := 0; start < len(arns); start += 10 {
+			end := start + 10
+			if end > len(arns) {
+				end = len(arns)
+			}
+
+			result, err := ecsI.UpdateContainerInstancesState(&ecs.UpdateContainerInstancesStateInput{
+				Cluster:            aws.String(opts.cluster),
+				ContainerInstances: arns[start:end],
+				Status:             aws.String(ecs.ContainerInstanceStatusDraining),
+			})
+			must(err)
+
+			for _, f := range result.Failures {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", aws.StringValue(f.Arn), aws.StringValue(f.Reason))
+			}
+
+			drained += len(result.ContainerInstances)
+			for _, ci := range result.ContainerInstances {
+				printAffected(aws.StringValue(ci.Ec2InstanceId), aws.StringValue(ci.Ec2InstanceId)+" draining")
+			}
+		}
+
+		// The next batch only starts once the tasks moved out of this one, the last one is only waited for with --wait
+		if i < len(batches)-1 || opts.wait {
+			err := waitDrained(ctx, opts.cluster, batch.instances, opts.timeout)
+			if ctx.Err() != nil {
+				interruptedAt(drained)
+			}
+			must(err)
+		}
+	}
+
+	if opts.wait {
+		typist.Printf("%d instances drained, no task left on them\n", drained)
+	}
+}
+
+var containerInstancesDrainCmd = &cobra.Command{
+	Use:   "drain [instances...]",
+	Short: "Drain container instances, in batches, showing the plan first",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   containerInstancesDrainRun,
+}
+
+func init() {
+	containerInstancesCmd.AddCommand(containerInstancesDrainCmd)
+
+	flags := containerInstancesDrainCmd.Flags()
+
+	flags.StringVarP(&containerInstancesDrainOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+
+	protectClusters(containerInstancesDrainCmd, "drain container instances", func(args []string) []string {
+		if containerInstancesDrainOpts.plan {
+			return nil
+		}
+		return []string{containerInstancesDrainOpts.cluster}
+	})
+	flags.IntVar(&containerInstancesDrainOpts.batchSize, "batch-size", 0, drainBatchSizeSpec)
+	flags.BoolVar(&containerInstancesDrainOpts.plan, "plan", false, drainPlanSpec)
+	flags.BoolVar(&containerInstancesDrainOpts.plan, "dry-run", false, drainPlanSpec)
+	flags.BoolVarP(&containerInstancesDrainOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVarP(&containerInstancesDrainOpts.wait, "wait", "w", false, drainWaitSpec)
+	flags.DurationVar(&containerInstancesDrainOpts.timeout, "timeout", 30*time.Minute, drainTimeoutSpec)
+
+	containerInstancesDrainCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/spf13/cobra"
+)
+
+type containerInstancesListOptions struct {
+	cluster     string
+	outdatedAMI bool
+}
+
+var containerInstancesListOpts containerInstancesListOptions
+
+type containerInstanceRow struct {
+	ContainerInstanceArn string `json:"containerInstanceArn"`
+	InstanceID           string `json:"ec2InstanceId"`
+	Status               string `json:"status"`
+	AgentVersion         string `json:"agentVersion"`
+	RunningTasks         int64  `json:"runningTasks"`
+	RemainingCPU         int64  `json:"remainingCpu"`
+	RemainingMemory      int64  `json:"remainingMemory"`
+	AMI                  string `json:"ami,omitempty"`
+	AMIAgeDays           int    `json:"amiAgeDays,omitempty"`
+	LatestAMI            string `json:"latestAmi,omitempty"`
+	AMIStatus            string `json:"amiStatus,omitempty"`
+}
+
+func remainingResource(ci *ecs.ContainerInstance, name string) int64 {
+	for _, r := range ci.RemainingResources {
+		if aws.StringValue(r.Name) == name {
+			return aws.Int64Value(r.IntegerValue)
+		}
+	}
+	return 0
+}
+
+// recommendedAMIParameter is the public SSM parameter of the latest ECS-optimized AMI of the family of the image,
+// empty when the image is not an ECS-optimized one (e.g. a custom AMI)
+func recommendedAMIParameter(image *ec2.Image) string {
+	name := aws.StringValue(image.Name)
+	arm := aws.StringValue(image.Architecture) == ec2.ArchitectureValuesArm64
+
+	var family string
+	switch {
+	case strings.HasPrefix(name, "al2023-ami-ecs"):
+		family = "amazon-linux-2023"
+	case strings.HasPrefix(name, "amzn2-ami-ecs-gpu"):
+		return "/aws/service/ecs/optimized-ami/amazon-linux-2/gpu/recommended/image_id"
+	case strings.HasPrefix(name, "amzn2-ami-ecs"):
+		family = "amazon-linux-2"
+	default:
+		return ""
+	}
+
+	if arm {
+		return "/aws/service/ecs/optimized-ami/" + family + "/arm64/recommended/image_id"
+	}
+	return "/aws/service/ecs/optimized-ami/" + family + "/recommended/image_id"
+}
+
+// compareAMIs fills the AMI columns of the rows, comparing the image of each instance with the latest
+// ECS-optimized AMI of its family and architecture
+func compareAMIs(rows []*containerInstanceRow) (err error) {
+	var instanceIDs []*string
+	for _, row := range rows {
+		if row.InstanceID != "" {
+			instanceIDs = append(instanceIDs, aws.String(row.InstanceID))
+		}
+	}
+
+	if len(instanceIDs) == 0 {
+		return
+	}
+
+	imageOf := make(map[string]string)
+	err = ec2I.DescribeInstancesPages(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				imageOf[aws.StringValue(instance.InstanceId)] = aws.StringValue(instance.ImageId)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return
+	}
+
+	var imageIDs []*string
+	seen := make(map[string]bool)
+	for _, id := range imageOf {
+		if !seen[id] {
+			seen[id] = true
+			imageIDs = append(imageIDs, aws.String(id))
+		}
+	}
+
+	described, err := ec2I.DescribeImages(&ec2.DescribeImagesInput{ImageIds: imageIDs})
+	if err != nil {
+		return
+	}
+
+	images := make(map[string]*ec2.Image)
+	for _, image := range described.Images {
+		images[aws.StringValue(image.ImageId)] = image
+	}
+
+	latest := make(map[string]string)
+	for _, row := range rows {
+		row.AMI = imageOf[row.InstanceID]
+
+		image, ok := images[row.AMI]
+		if !ok {
+			// deregistered images are not described anymore, they are surely outdated
+			row.AMIStatus = "unknown"
+			continue
+		}
+
+		if created, err := time.Parse(time.RFC3339, aws.StringValue(image.CreationDate)); err == nil {
+			row.AMIAgeDays = int(time.Since(created).Hours() / 24)
+		}
+
+		parameter := recommendedAMIParameter(image)
+		if parameter == "" {
+			row.AMIStatus = "custom"
+			continue
+		}
+
+		if _, ok := latest[parameter]; !ok {
+			result, err := ssmI.GetParameter(&ssm.GetParameterInput{Name: aws.String(parameter)})
+			if err != nil {
+				return err
+			}
+			latest[parameter] = aws.StringValue(result.Parameter.Value)
+		}
+
+		row.LatestAMI = latest[parameter]
+		row.AMIStatus = "current"
+		if row.AMI != row.LatestAMI {
+			row.AMIStatus = "outdated"
+		}
+	}
+	return
+}
+
+func containerInstancesListRun(cmd *cobra.Command, args []string) {
+	opts := &containerInstancesListOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	instances, err := describeContainerInstances(opts.cluster)
+	must(err)
+
+	var rows []*containerInstanceRow
+	for _, ci := range instances {
+		agentVersion := ""
+		if ci.VersionInfo != nil {
+			agentVersion = aws.StringValue(ci.VersionInfo.AgentVersion)
+		}
+
+		rows = append(rows, &containerInstanceRow{
+			ContainerInstanceArn: aws.StringValue(ci.ContainerInstanceArn),
+			InstanceID:           aws.StringValue(ci.Ec2InstanceId),
+			Status:               aws.StringValue(ci.Status),
+			AgentVersion:         agentVersion,
+			RunningTasks:         aws.Int64Value(ci.RunningTasksCount),
+			RemainingCPU:         remainingResource(ci, "CPU"),
+			RemainingMemory:      remainingResource(ci, "MEMORY"),
+		})
+	}
+
+	// The report only keeps the instances to be recycled
+	if opts.outdatedAMI {
+		must(compareAMIs(rows))
+
+		var outdated []*containerInstanceRow
+		for _, row := range rows {
+			if row.AMIStatus == "outdated" || row.AMIStatus == "unknown" {
+				outdated = append(outdated, row)
+			}
+		}
+		rows = outdated
+	}
+
+	if outputFormat == "json" {
+		if rows == nil {
+			rows = []*containerInstanceRow{}
+		}
+
+		must(printVersionedJSON(rows))
+		return
+	}
+
+	if quiet {
+		for _, row := range rows {
+			printID(row.ContainerInstanceArn)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if opts.outdatedAMI {
+		fmt.Fprintln(w, "INSTANCE\tSTATUS\tTASKS\tAMI\tAMI AGE\tLATEST AMI")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%dd\t%s\n", row.InstanceID, row.Status, row.RunningTasks, row.AMI, row.AMIAgeDays, row.LatestAMI)
+		}
+	} else {
+		fmt.Fprintln(w, "CONTAINER INSTANCE\tINSTANCE\tSTATUS\tAGENT\tTASKS\tCPU LEFT\tMEMORY LEFT")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n", taskID(row.ContainerInstanceArn), row.InstanceID, row.Status, row.AgentVersion, row.RunningTasks, row.RemainingCPU, row.RemainingMemory)
+		}
+	}
+	w.Flush()
+}
+
+var containerInstancesListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the container instances of a cluster",
+	Args:        cobra.NoArgs,
+	Run:         containerInstancesListRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	containerInstancesCmd.AddCommand(containerInstancesListCmd)
+
+	registerOutputSchema(containerInstancesListCmd, "container-instances list", []*containerInstanceRow{})
+
+	flags := containerInstancesListCmd.Flags()
+
+	flags.StringVarP(&containerInstancesListOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&containerInstancesListOpts.outdatedAMI, "outdated-ami", false, outdatedAMISpec)
+
+	containerInstancesListCmd.MarkFlagRequired("cluster")
+
+	instancesAlias(containerInstancesListCmd)
+}
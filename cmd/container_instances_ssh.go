@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/spf13/cobra"
+)
+
+type containerInstancesSSHOptions struct {
+	cluster string
+	command string
+}
+
+var containerInstancesSSHOpts containerInstancesSSHOptions
+
+// checkSSMConnectivity fails naming the missing prerequisite when the instance is not reachable by Session Manager
+func checkSSMConnectivity(instanceID string) error {
+	result, err := ssmI.DescribeInstanceInformation(&ssm.DescribeInstanceInformationInput{
+		Filters: []*ssm.InstanceInformationStringFilter{
+			{Key: aws.String("InstanceIds"), Values: []*string{aws.String(instanceID)}},
+		},
+	})
+	if awsErrorCode(err) == "AccessDeniedException" {
+		return errors.New("Access denied checking the instance on SSM, ssm:DescribeInstanceInformation is needed")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(result.InstanceInformationList) == 0 {
+		return fmt.Errorf("Instance %s is not registered on SSM: the SSM agent must be running on it and its instance profile needs the AmazonSSMManagedInstanceCore policy (or equivalent ssm:UpdateInstanceInformation, ssmmessages:* and ec2messages:* permissions)", instanceID)
+	}
+
+	if status := aws.StringValue(result.InstanceInformationList[0].PingStatus); status != ssm.PingStatusOnline {
+		return fmt.Errorf("SSM agent of instance %s is %s: check that the agent is running and the instance reaches the SSM endpoints", instanceID, status)
+	}
+	return nil
+}
+
+// startSession starts an interactive session, which is only possible through the AWS CLI and its Session Manager plugin
+func startSession(instanceID string) error {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return errors.New("The AWS CLI is needed to start an interactive session")
+	}
+
+	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+		return errors.New("The Session Manager plugin for the AWS CLI is needed to start an interactive session\nSee https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html")
+	}
+
+	args := []string{"ssm", "start-session", "--target", instanceID, "--region", aws.StringValue(awsSession.Config.Region)}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	session := exec.Command("aws", args...)
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+	return session.Run()
+}
+
+// runCommand runs a shell command with SendCommand, printing the output as it is made available,
+// and returns the exit code of the command
+func runCommand(instanceID, command string) (exitCode int, err error) {
+	sent, err := ssmI.SendCommand(&ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []*string{aws.String(instanceID)},
+		Parameters:   map[string][]*string{"commands": {aws.String(command)}},
+		Comment:      aws.String("ecsctl container-instances ssh"),
+	})
+	if awsErrorCode(err) == "AccessDeniedException" {
+		return 1, errors.New("Access denied sending the command, ssm:SendCommand is needed on the instance and the AWS-RunShellScript document")
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	ctx := interruptContext()
+	var printedOut, printedErr int
+	for {
+		// The output printed so far stays, the command is canceled on the instance rather than left running
+		if !sleepContext(ctx, time.Second) {
+			_, err := ssmI.CancelCommand(&ssm.CancelCommandInput{
+				CommandId:   sent.Command.CommandId,
+				InstanceIds: []*string{aws.String(instanceID)},
+			})
+			if err != nil {
+				return 130, fmt.Errorf("Interrupted, but the command %s could not be canceled on %s: %s", aws.StringValue(sent.Command.CommandId), instanceID, err.Error())
+			}
+
+			fmt.Fprintf(os.Stderr, "interrupted, the command %s was canceled on %s\n", aws.StringValue(sent.Command.CommandId), instanceID)
+			return 130, nil
+		}
+
+		invocation, err := ssmI.GetCommandInvocation(&ssm.GetCommandInvocationInput{
+			CommandId:  sent.Command.CommandId,
+			InstanceId: aws.String(instanceID),
+		})
+		if awsErrorCode(err) == ssm.ErrCodeInvocationDoesNotExist {
+			continue
+		}
+		if err != nil {
+			return 1, err
+		}
+
+		stdout := aws.StringValue(invocation.StandardOutputContent)
+		if len(stdout) > printedOut {
+			fmt.Print(stdout[printedOut:])
+			printedOut = len(stdout)
+		}
+
+		stderr := aws.StringValue(invocation.StandardErrorContent)
+		if len(stderr) > printedErr {
+			fmt.Fprint(os.Stderr, stderr[printedErr:])
+			printedErr = len(stderr)
+		}
+
+		switch aws.StringValue(invocation.Status) {
+		case ssm.CommandInvocationStatusPending, ssm.CommandInvocationStatusInProgress, ssm.CommandInvocationStatusDelayed:
+			continue
+		case ssm.CommandInvocationStatusSuccess:
+			return 0, nil
+		}
+
+		exitCode = int(aws.Int64Value(invocation.ResponseCode))
+		if exitCode <= 0 {
+			exitCode = 1
+		}
+
+		fmt.Fprintf(os.Stderr, "command %s\n", aws.StringValue(invocation.StatusDetails))
+		return exitCode, nil
+	}
+}
+
+func containerInstancesSSHRun(cmd *cobra.Command, args []string) {
+	opts := &containerInstancesSSHOpts
+
+	instances, err := resolveContainerInstances(opts.cluster, args)
+	must(err)
+
+	instanceID := aws.StringValue(instances[0].Ec2InstanceId)
+	if instanceID == "" {
+		must(errors.New("Container instance informed is not an EC2 instance"))
+	}
+
+	must(checkSSMConnectivity(instanceID))
+
+	if opts.command == "" {
+		must(startSession(instanceID))
+		return
+	}
+
+	exitCode, err := runCommand(instanceID, opts.command)
+	must(err)
+	exit(exitCode)
+}
+
+var containerInstancesSSHCmd = &cobra.Command{
+	Use:   "ssh [container-instance]",
+	Short: "Open a shell on a container instance, or run a command on it, through SSM Session Manager",
+	Args:  cobra.ExactArgs(1),
+	Run:   containerInstancesSSHRun,
+}
+
+func init() {
+	containerInstancesCmd.AddCommand(containerInstancesSSHCmd)
+
+	flags := containerInstancesSSHCmd.Flags()
+
+	flags.StringVarP(&containerInstancesSSHOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&containerInstancesSSHOpts.command, "command", "", sshCommandSpec)
+
+	containerInstancesSSHCmd.MarkFlagRequired("cluster")
+
+	instancesAlias(containerInstancesSSHCmd)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is an Application Auto Scaling / EventBridge cron expression:
+// cron(minutes hours day-of-month month day-of-week year)
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays, years map[int]bool
+	anyDay, anyWeekday                            bool
+}
+
+var cronExpressionPattern = regexp.MustCompile(`^cron\((.+)\)$`)
+
+var cronMonths = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+var cronWeekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// parseCron validates the expression client-side, so mistakes are not only found by the API.
+// The L, W and # wildcards are not supported.
+func parseCron(expression string) (c *cronSchedule, err error) {
+	match := cronExpressionPattern.FindStringSubmatch(strings.TrimSpace(expression))
+	if match == nil {
+		err = fmt.Errorf("Invalid schedule '%s', expected cron(minutes hours day-of-month month day-of-week year)", expression)
+		return
+	}
+
+	fields := strings.Fields(match[1])
+	if len(fields) != 6 {
+		err = fmt.Errorf("Invalid schedule '%s', a cron expression has 6 fields, got %d", expression, len(fields))
+		return
+	}
+
+	if (fields[2] == "?") == (fields[4] == "?") {
+		err = fmt.Errorf("Invalid schedule '%s', either day-of-month or day-of-week must be '?'", expression)
+		return
+	}
+
+	c = &cronSchedule{anyDay: fields[2] == "?", anyWeekday: fields[4] == "?"}
+
+	fieldsSpec := []struct {
+		name     string
+		set      *map[int]bool
+		min, max int
+		names    []string
+	}{
+		{"minutes", &c.minutes, 0, 59, nil},
+		{"hours", &c.hours, 0, 23, nil},
+		{"day-of-month", &c.days, 1, 31, nil},
+		{"month", &c.months, 1, 12, cronMonths},
+		{"day-of-week", &c.weekdays, 1, 7, cronWeekdays},
+		{"year", &c.years, 1970, 2199, nil},
+	}
+
+	for i, spec := range fieldsSpec {
+		if fields[i] == "?" {
+			continue
+		}
+
+		*spec.set, err = parseCronField(fields[i], spec.min, spec.max, spec.names)
+		if err != nil {
+			err = fmt.Errorf("Invalid %s on schedule '%s': %s", spec.name, expression, err.Error())
+			return
+		}
+	}
+
+	return
+}
+
+func parseCronField(field string, min, max int, names []string) (set map[int]bool, err error) {
+	set = make(map[int]bool)
+
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				return min + i, nil
+			}
+		}
+
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("'%s' is not between %d and %d", s, min, max)
+		}
+		return v, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		// Names like JUL and WED have the letters, so L and W are only looked for on numeric fields
+		if strings.Contains(part, "#") || (names == nil && strings.ContainsAny(part, "LW")) {
+			err = errors.New("the L, W and # wildcards are not supported")
+			return
+		}
+
+		base, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			base = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				err = fmt.Errorf("invalid increment '%s'", part[i+1:])
+				return
+			}
+		}
+
+		lo, hi := min, max
+		switch {
+		case base == "*":
+		case strings.Contains(base, "-"):
+			bounds := strings.SplitN(base, "-", 2)
+			if lo, err = value(bounds[0]); err != nil {
+				return
+			}
+			if hi, err = value(bounds[1]); err != nil {
+				return
+			}
+			if hi < lo {
+				err = fmt.Errorf("invalid range '%s'", base)
+				return
+			}
+		default:
+			if lo, err = value(base); err != nil {
+				return
+			}
+			if step == 1 && !strings.Contains(part, "/") {
+				hi = lo
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	return
+}
+
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] || !c.years[t.Year()] {
+		return false
+	}
+
+	if c.anyDay {
+		return c.weekdays[int(t.Weekday())+1]
+	}
+	return c.days[t.Day()]
+}
+
+// next returns the first time after the informed one the schedule runs, looking up to one year ahead
+func (c *cronSchedule) next(after time.Time) (t time.Time, found bool) {
+	t = after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(1, 0, 0)
+
+	for ; t.Before(limit); t = t.Add(time.Minute) {
+		if c.matches(t) {
+			found = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// clusterEnv is the default cluster of the commands, before the cluster of the config file
+const clusterEnv = "ECSCTL_CLUSTER"
+
+// defaultCluster is the cluster of the environment, or of the config file, with where it was found
+func defaultCluster() (cluster, source string) {
+	if cluster = os.Getenv(clusterEnv); cluster != "" {
+		return cluster, clusterEnv
+	}
+
+	settings, _, err := readConfigFile()
+	if err != nil {
+		return
+	}
+
+	if c, ok := settings["cluster"].(string); ok && c != "" {
+		return c, viper.ConfigFileUsed()
+	}
+	return
+}
+
+// applyDefaultCluster informs --cluster with the default cluster when it was not, before cobra checks the required flags.
+// The commands able to read every cluster are left alone with --all-clusters.
+func applyDefaultCluster(cmd *cobra.Command) {
+	flag := cmd.Flags().Lookup("cluster")
+	if flag == nil || flag.Changed {
+		return
+	}
+
+	if all := cmd.Flags().Lookup("all-clusters"); all != nil && all.Changed {
+		return
+	}
+
+	cluster, source := defaultCluster()
+	if cluster == "" {
+		return
+	}
+
+	debugf("cluster %s from %s", cluster, source)
+	cmd.Flags().Set("cluster", cluster)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// execExitMarker is printed by the wrapped command with its exit status,
+// the Session Manager plugin does not propagate the exit status of the remote command
+const execExitMarker = "__ecsctl_exit_status="
+
+// checkECSExecPrerequisites fails naming what is missing to use ECS Exec, which is only possible
+// through the AWS CLI and its Session Manager plugin
+func checkECSExecPrerequisites() error {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return errors.New("The AWS CLI is needed to run a command with ECS Exec")
+	}
+
+	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+		return errors.New("The Session Manager plugin for the AWS CLI is needed to run a command with ECS Exec\nSee https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html")
+	}
+	return nil
+}
+
+// shellQuote quotes the argument for sh
+func shellQuote(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// ecsExecCommand builds the aws ecs execute-command call of the command in a container of a task
+func ecsExecCommand(cluster, task, container, command string) *exec.Cmd {
+	args := []string{"ecs", "execute-command", "--cluster", cluster, "--task", task,
+		"--interactive", "--command", command, "--region", aws.StringValue(awsSession.Config.Region)}
+	if container != "" {
+		args = append(args, "--container", container)
+	}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	return exec.Command("aws", args...)
+}
+
+// runECSExec runs a command in a container of a task with ECS Exec. The output is captured and returned.
+func runECSExec(cluster, task, container string, command []string) (output string, err error) {
+	if err = checkECSExecPrerequisites(); err != nil {
+		return
+	}
+
+	out, err := ecsExecCommand(cluster, task, container, strings.Join(command, " ")).CombinedOutput()
+	output = string(out)
+	if len(output) > maxVerifyOutput {
+		output = output[len(output)-maxVerifyOutput:]
+	}
+	return
+}
+
+// attachECSExec runs the command in the container with the standard input piped through the session,
+// returning the exit status of the remote command
+func attachECSExec(cluster, task, container string, command []string, stdin io.Reader) (exitCode int, err error) {
+	var quoted []string
+	for _, arg := range command {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	wrapped := "sh -c " + shellQuote(strings.Join(quoted, " ")+"; echo "+execExitMarker+"$?")
+
+	session := ecsExecCommand(cluster, task, container, wrapped)
+	session.Stdin = stdin
+	session.Stderr = os.Stderr
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return 1, err
+	}
+
+	if err = session.Start(); err != nil {
+		return 1, err
+	}
+
+	exitCode = -1
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, execExitMarker); i >= 0 {
+			if code, err := strconv.Atoi(strings.TrimSpace(line[i+len(execExitMarker):])); err == nil {
+				exitCode = code
+			}
+			continue
+		}
+		fmt.Println(line)
+	}
+
+	if err = session.Wait(); err != nil && exitCode < 0 {
+		return 1, err
+	}
+
+	if exitCode < 0 {
+		return 1, errors.New("The session ended without the exit status of the command")
+	}
+	return exitCode, nil
+}
+
+// waitExecAgent waits until the task is RUNNING and the ECS Exec agent of the container is ready
+func waitExecAgent(cluster, task, container string, timeout time.Duration) error {
+	ctx := interruptContext()
+	deadline := time.Now().Add(timeout)
+	for {
+		tasks, err := describeTasks(cluster, []*string{aws.String(task)})
+		if err != nil {
+			return err
+		}
+
+		t := tasks[0]
+		if aws.StringValue(t.LastStatus) == ecs.DesiredStatusStopped {
+			return fmt.Errorf("Task %s stopped before the command could be run: %s", taskID(task), aws.StringValue(t.StoppedReason))
+		}
+
+		for _, c := range t.Containers {
+			if container != "" && aws.StringValue(c.Name) != container {
+				continue
+			}
+
+			for _, agent := range c.ManagedAgents {
+				if aws.StringValue(agent.Name) == ecs.ManagedAgentNameExecuteCommandAgent && aws.StringValue(agent.LastStatus) == "RUNNING" {
+					return nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out after %s waiting for the ECS Exec agent of task %s", timeout, taskID(task))
+		}
+
+		if !sleepContext(ctx, 3*time.Second) {
+			return fmt.Errorf("Interrupted while waiting for the ECS Exec agent of task %s", taskID(task))
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type execOptions struct {
+	cluster   string
+	task      string
+	service   string
+	container string
+	command   string
+}
+
+var execOpts execOptions
+
+// execTask finds the task informed, or the first RUNNING task of the service
+func execTask(cluster, task, service string) (*ecs.Task, error) {
+	if task != "" {
+		tasks, err := describeTasks(cluster, []*string{aws.String(task)})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tasks) == 0 {
+			return nil, errors.New("Task " + task + " not found on " + cluster)
+		}
+		return tasks[0], nil
+	}
+
+	tasks, err := serviceTasks(cluster, service, ecs.DesiredStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tasks {
+		if aws.StringValue(t.LastStatus) == ecs.DesiredStatusRunning {
+			fmt.Fprintf(os.Stderr, "Task %s of %s chosen\n", taskID(aws.StringValue(t.TaskArn)), service)
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("Service %s has no RUNNING task", service)
+}
+
+// execContainer is the container informed, or the only one of the task, checking its ECS Exec agent is running
+func execContainer(t *ecs.Task, name string) (string, error) {
+	var names []string
+	var container *ecs.Container
+	for _, c := range t.Containers {
+		names = append(names, aws.StringValue(c.Name))
+		if name == "" || aws.StringValue(c.Name) == name {
+			container = c
+		}
+	}
+
+	if name == "" && len(t.Containers) > 1 {
+		return "", fmt.Errorf("Task %s has more than one container, inform one with --container: %s", taskID(aws.StringValue(t.TaskArn)), strings.Join(names, ", "))
+	}
+
+	if container == nil {
+		return "", fmt.Errorf("Task %s has no container %s, only %s", taskID(aws.StringValue(t.TaskArn)), name, strings.Join(names, ", "))
+	}
+
+	for _, agent := range container.ManagedAgents {
+		if aws.StringValue(agent.Name) != ecs.ManagedAgentNameExecuteCommandAgent {
+			continue
+		}
+
+		if status := aws.StringValue(agent.LastStatus); status != "RUNNING" {
+			return "", fmt.Errorf("ECS Exec agent of container %s is %s: %s", aws.StringValue(container.Name), status, aws.StringValue(agent.Reason))
+		}
+	}
+	return aws.StringValue(container.Name), nil
+}
+
+func execRun(cmd *cobra.Command, args []string) {
+	opts := &execOpts
+
+	if (opts.task == "") == (opts.service == "") {
+		must(errors.New("Inform either --task or --service"))
+	}
+
+	must(checkECSExecPrerequisites())
+
+	t, err := execTask(opts.cluster, opts.task, opts.service)
+	must(err)
+
+	if aws.StringValue(t.LastStatus) != ecs.DesiredStatusRunning {
+		must(fmt.Errorf("Task %s is %s, ECS Exec needs it RUNNING", taskID(aws.StringValue(t.TaskArn)), aws.StringValue(t.LastStatus)))
+	}
+
+	if !aws.BoolValue(t.EnableExecuteCommand) {
+		hint := "its service with 'ecsctl services update SERVICE -c " + opts.cluster + " --enable-execute-command'"
+		if group := aws.StringValue(t.Group); strings.HasPrefix(group, "service:") {
+			hint = "its service with 'ecsctl services update " + strings.TrimPrefix(group, "service:") + " -c " + opts.cluster + " --enable-execute-command'"
+		}
+		must(fmt.Errorf("Task %s does not have ECS Exec enabled. Enable it on %s, new tasks will have it", taskID(aws.StringValue(t.TaskArn)), hint))
+	}
+
+	container, err := execContainer(t, opts.container)
+	must(err)
+
+	session := ecsExecCommand(opts.cluster, aws.StringValue(t.TaskArn), container, opts.command)
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	err = session.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exit(exitErr.ExitCode())
+	}
+	must(err)
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Open an interactive shell, or run a command, in a running container with ECS Exec",
+	Args:  cobra.NoArgs,
+	Run:   execRun,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	flags := execCmd.Flags()
+
+	flags.StringVarP(&execOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVarP(&execOpts.task, "task", "t", "", execTaskSpec)
+	flags.StringVarP(&execOpts.service, "service", "s", "", execServiceSpec)
+	flags.StringVar(&execOpts.container, "container", "", execContainerSpec)
+	flags.StringVar(&execOpts.command, "command", "/bin/sh", execCommandSpec)
+
+	execCmd.MarkFlagRequired("cluster")
+}
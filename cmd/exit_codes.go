@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// Exit codes of the failures of AWS rather than of the containers, whose exit codes are kept within 1-125,
+// so pipelines can tell which ones are worth retrying
+const (
+	exitPlacementFailure = 160
+	exitImagePullFailure = 161
+	exitSpotInterruption = 162
+	exitRollback         = 163
+	exitInfrastructure   = 164
+)
+
+// maxContainerExitCode is the highest container exit code passed as it is, the ones above (signals) become it
+const maxContainerExitCode = 125
+
+var (
+	imagePullPattern        = regexp.MustCompile(`(?i)CannotPullContainerError|pull image|pulling image|image manifest|ImagePull`)
+	spotInterruptionPattern = regexp.MustCompile(`(?i)spot interruption|SpotInterruption`)
+	placementPattern        = regexp.MustCompile(`(?i)unable to place a task|RESOURCE:|no container instance|Capacity is unavailable`)
+	circuitBreakerPattern   = regexp.MustCompile(`(?i)circuit breaker|rolled back|rollback`)
+)
+
+// taskOutcome is the classification of a stopped task and the exit code it maps to
+type taskOutcome struct {
+	Class    string `json:"class"`
+	ExitCode int    `json:"exitCode"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (o taskOutcome) String() string {
+	if o.Reason == "" {
+		return fmt.Sprintf("%s (exit code %d)", o.Class, o.ExitCode)
+	}
+	return fmt.Sprintf("%s (exit code %d): %s", o.Class, o.ExitCode, o.Reason)
+}
+
+// containerExitCode keeps the exit code of a container out of the codes of the infrastructure failures
+func containerExitCode(code int) int {
+	if code > maxContainerExitCode || code < 0 {
+		return maxContainerExitCode
+	}
+	return code
+}
+
+// classifyTask tells from the StopCode and the stopped reasons whether a stopped task failed on its container
+// or on AWS: a spot interruption, an image pull error or a task failed to start otherwise
+func classifyTask(t *ecs.Task, td *ecs.TaskDefinition, container string) taskOutcome {
+	reasons := []string{aws.StringValue(t.StoppedReason)}
+	for _, c := range t.Containers {
+		reasons = append(reasons, aws.StringValue(c.Reason))
+	}
+	reason := strings.TrimSpace(strings.Join(reasons, " "))
+
+	stopCode := aws.StringValue(t.StopCode)
+	switch {
+	case stopCode == ecs.TaskStopCodeSpotInterruption || spotInterruptionPattern.MatchString(reason):
+		return taskOutcome{"spot-interruption", exitSpotInterruption, aws.StringValue(t.StoppedReason)}
+	case imagePullPattern.MatchString(reason):
+		return taskOutcome{"image-pull", exitImagePullFailure, reason}
+	case stopCode == ecs.TaskStopCodeTaskFailedToStart || stopCode == ecs.TaskStopCodeTerminationNotice:
+		return taskOutcome{"infrastructure", exitInfrastructure, reason}
+	}
+
+	code := taskExitCode(t, td, container)
+	if code == 0 {
+		return taskOutcome{Class: "success"}
+	}
+
+	outcome := taskOutcome{"container", containerExitCode(code), aws.StringValue(t.StoppedReason)}
+	if code != outcome.ExitCode {
+		outcome.Reason = fmt.Sprintf("exited with %d, %s", code, outcome.Reason)
+	}
+	return outcome
+}
+
+// exitTask prints the classification of a failed task and exits with its exit code
+func exitTask(t *ecs.Task, td *ecs.TaskDefinition, container string) {
+	outcome := classifyTask(t, td, container)
+	if outcome.ExitCode != 0 {
+		fmt.Fprintf(os.Stderr, "task %s failed: %s\n", taskID(aws.StringValue(t.TaskArn)), outcome)
+	}
+	exit(outcome.ExitCode)
+}
+
+// deploymentsExitCode maps the failed deployments to the exit code of the first failure of AWS,
+// a rollback of the circuit breaker or tasks that could not be placed, 1 otherwise
+func deploymentsExitCode(failed []*serviceWaitStatus) int {
+	for _, s := range failed {
+		switch {
+		case circuitBreakerPattern.MatchString(s.reason):
+			return exitRollback
+		case placementPattern.MatchString(s.reason):
+			return exitPlacementFailure
+		case imagePullPattern.MatchString(s.reason):
+			return exitImagePullFailure
+		}
+	}
+	return 1
+}
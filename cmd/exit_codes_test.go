@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+func stoppedTask(stopCode, stoppedReason string, containers ...*ecs.Container) *ecs.Task {
+	t := &ecs.Task{
+		LastStatus:    aws.String(ecs.DesiredStatusStopped),
+		StoppedReason: aws.String(stoppedReason),
+		Containers:    containers,
+	}
+	if stopCode != "" {
+		t.StopCode = aws.String(stopCode)
+	}
+	return t
+}
+
+func exitedContainer(name string, code int64) *ecs.Container {
+	return &ecs.Container{Name: aws.String(name), ExitCode: aws.Int64(code)}
+}
+
+func TestClassifyTask(t *testing.T) {
+	td := &ecs.TaskDefinition{
+		ContainerDefinitions: []*ecs.ContainerDefinition{
+			{Name: aws.String("app")},
+			{Name: aws.String("sidecar"), Essential: aws.Bool(true)},
+			{Name: aws.String("optional"), Essential: aws.Bool(false)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		task     *ecs.Task
+		class    string
+		exitCode int
+	}{
+		{
+			name:  "exited zero",
+			task:  stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", exitedContainer("app", 0), exitedContainer("sidecar", 0)),
+			class: "success",
+		},
+		{
+			name:     "container exit code",
+			task:     stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", exitedContainer("app", 3), exitedContainer("sidecar", 0)),
+			class:    "container",
+			exitCode: 3,
+		},
+		{
+			name:     "signal kept within the container codes",
+			task:     stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", exitedContainer("app", 137), exitedContainer("sidecar", 0)),
+			class:    "container",
+			exitCode: maxContainerExitCode,
+		},
+		{
+			name:     "essential sidecar crashed",
+			task:     stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", exitedContainer("app", 0), exitedContainer("sidecar", 2)),
+			class:    "container",
+			exitCode: 2,
+		},
+		{
+			name:  "optional container crash ignored",
+			task:  stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", exitedContainer("app", 0), exitedContainer("sidecar", 0), exitedContainer("optional", 1)),
+			class: "success",
+		},
+		{
+			name:     "container never exited",
+			task:     stoppedTask(ecs.TaskStopCodeEssentialContainerExited, "Essential container in task exited", &ecs.Container{Name: aws.String("app")}),
+			class:    "container",
+			exitCode: 1,
+		},
+		{
+			name:     "spot interruption stop code",
+			task:     stoppedTask(ecs.TaskStopCodeSpotInterruption, "Your Spot Task was interrupted."),
+			class:    "spot-interruption",
+			exitCode: exitSpotInterruption,
+		},
+		{
+			name:     "spot interruption reason",
+			task:     stoppedTask("", "Spot interruption notice received"),
+			class:    "spot-interruption",
+			exitCode: exitSpotInterruption,
+		},
+		{
+			name: "image pull on the container reason",
+			task: stoppedTask(ecs.TaskStopCodeTaskFailedToStart, "Task failed to start", &ecs.Container{
+				Name:   aws.String("app"),
+				Reason: aws.String("CannotPullContainerError: pull image manifest has been retried 5 time(s)"),
+			}),
+			class:    "image-pull",
+			exitCode: exitImagePullFailure,
+		},
+		{
+			name:     "failed to start",
+			task:     stoppedTask(ecs.TaskStopCodeTaskFailedToStart, "ResourceInitializationError: unable to pull secrets or registry auth"),
+			class:    "infrastructure",
+			exitCode: exitInfrastructure,
+		},
+		{
+			name:     "termination notice",
+			task:     stoppedTask(ecs.TaskStopCodeTerminationNotice, "Host EC2 (instance i-0abc) stopped/terminated"),
+			class:    "infrastructure",
+			exitCode: exitInfrastructure,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outcome := classifyTask(test.task, td, "app")
+			if outcome.Class != test.class || outcome.ExitCode != test.exitCode {
+				t.Errorf("got %s (exit code %d), want %s (exit code %d)", outcome.Class, outcome.ExitCode, test.class, test.exitCode)
+			}
+		})
+	}
+}
+
+func TestDeploymentsExitCode(t *testing.T) {
+	tests := []struct {
+		reason   string
+		exitCode int
+	}{
+		{"deployment failed: circuit breaker triggered, rolled back", exitRollback},
+		{"(service api) was unable to place a task because no container instance met all of its requirements", exitPlacementFailure},
+		{"CannotPullContainerError: image not found", exitImagePullFailure},
+		{"timed out", 1},
+	}
+
+	for _, test := range tests {
+		if code := deploymentsExitCode([]*serviceWaitStatus{{reason: test.reason}}); code != test.exitCode {
+			t.Errorf("%q: got exit code %d, want %d", test.reason, code, test.exitCode)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	typistPkg "github.com/gumieri/typist"
+
+	"github.com/gumieri/ecsctl/ecsx"
+)
+
+// fakeError is answered as an AWS error, such as ThrottlingException
+type fakeError struct {
+	code    string
+	message string
+}
+
+func (e *fakeError) Error() string {
+	return e.code + ": " + e.message
+}
+
+// fakeHandler answers an API call by its operation name, such as ListClusters.
+// decode fills the input of the SDK with the request, the output is any SDK output.
+type fakeHandler func(operation string, decode func(input interface{})) (output interface{}, err error)
+
+// fakeAWS points the clients of the commands to a server answering with the handler, for the duration of the test.
+// It returns the count of the calls of an operation so far.
+func fakeAWS(t *testing.T, handle fakeHandler) (called func(operation string) int) {
+	calls := struct {
+		sync.Mutex
+		byOperation map[string]int
+	}{byOperation: make(map[string]int)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get("X-Amz-Target")
+		operation := target[strings.LastIndex(target, ".")+1:]
+
+		calls.Lock()
+		calls.byOperation[operation]++
+		calls.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		decode := func(input interface{}) {
+			if err := jsonutil.UnmarshalJSON(input, bytes.NewReader(body)); err != nil {
+				t.Errorf("%s: %s", operation, err.Error())
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+		output, err := handle(operation, decode)
+		if ferr, ok := err.(*fakeError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": ferr.code, "message": ferr.message})
+			return
+		}
+		if err != nil {
+			t.Errorf("%s: %s", operation, err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		content, err := jsonutil.BuildJSON(output)
+		if err != nil {
+			t.Errorf("%s: %s", operation, err.Error())
+		}
+		w.Write(content)
+	}))
+
+	awsSession := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  aws.Int(0),
+	}))
+
+	previousECS, previousECSX, previousECR, previousEC2, previousTypist := ecsI, ecsxI, ecrI, ec2I, typist
+	ecsI = ecs.New(awsSession)
+	ecsxI = ecsx.New(ecsI)
+	ecrI = ecr.New(awsSession)
+	ec2I = ec2.New(awsSession)
+	typist = &typistPkg.Typist{Quiet: quiet, In: os.Stdin, Out: io.Discard}
+
+	t.Cleanup(func() {
+		server.Close()
+		ecsI, ecsxI, ecrI, ec2I, typist = previousECS, previousECSX, previousECR, previousEC2, previousTypist
+	})
+
+	return func(operation string) int {
+		calls.Lock()
+		defer calls.Unlock()
+		return calls.byOperation[operation]
+	}
+}
+
+// captureStdout returns what the function printed to the standard output
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	captured := make(chan string)
+	go func() {
+		content, _ := io.ReadAll(r)
+		captured <- string(content)
+	}()
+
+	fn()
+	w.Close()
+	return <-captured
+}
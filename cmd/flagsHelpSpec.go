@@ -2,123 +2,497 @@ package cmd
 
 var requiredSpec = "REQUIRED - "
 
-var revision string
-var revisionSpec = `AWS ECS cluster`
+var revisionSpec = `Task Definition revision (default is the latest)
+Not allowed when the revision is already informed as family:revision or ARN`
 
-var follow bool
 var followSpec = `keep process logging from CloudWatch Logs`
 
-var exit bool
-var exitSpec = `Send signal to process exit when dettached. Use only with --follow (-f) option`
+var exitSpec = `Stop the task when interrupted, still following it until it is stopped to show its last logs and exit code
+(interrupt again to exit right away). Use only with --follow (-f) or --wait (-w) options`
 
-var image string
 var imageSpec = `AWS ECR image`
 
-var yes bool
 var yesSpec = `Answer yes`
 
-var force bool
 var forceSpec = `Force the command despite the errors`
 
 var quiet bool
-var quietSpec = `Omit messages to the standard output`
+var quietSpec = `Print only the identifiers of the listed, created or changed resources, one per line`
+
+var debug bool
+var debugSpec = `Print debugging information to the standard error`
 
-var editorCommand string
 var editorCommandSpec = `Override default text editor`
 
-var containerName string
 var containerNameSpec = `Container name from Task Definition`
 
-var repository string
 var repositorySpec = `AWS ECR repository name`
 
-var tag string
 var tagSpec = `AWS ECR image tag`
 
 var cfgFile string
 var cfgFileSpec = `config file (default is $HOME/.ecsctl.yaml)`
 
 var profile string
-var profileSpec = `AWS Profile`
+var profileSpec = `AWS Profile (default is AWS_PROFILE, then profile of the config file)`
 
 var region string
-var regionSpec = `AWS Region`
+var regionSpec = `AWS Region (default is AWS_REGION or AWS_DEFAULT_REGION, then region of the config file, then the one of the profile)`
 
-var cluster string
 var clusterSpec = `AWS ECS cluster`
 
-var toCluster string
 var toClusterSpec = `AWS ECS cluster target where the copy will be created`
 
-var spotPrice string
 var spotPriceSpec = `Maximum price to pay for the spot instances (default is On-Demand price)`
 
-var spotFleetRole string
 var spotFleetRoleSpec = `IAM fleet role grants the Spot fleet permission launch and terminate instances on your behalf`
 
-var instanceProfile string
 var instanceProfileSpec = `An instance profile is a container for an IAM role and enables you to pass role information to Amazon EC2 Instance when the instance starts`
 
-var targetCapacity int64
 var targetCapacitySpec = `The capacity amout defined for the cluster`
 
-var allocationStrategy string
 var allocationStrategySpec = `The strategy for requesting instances across different Availability Zones.
 Valid values:
 'lowestPrice': Automatically select the cheapest Availability Zone and instance type (default)
 'diversified': Balance Spot instances across selected Availability Zones and instance types`
 
-var subnet string
 var subnetSpec = `The Subnet (ID or tag 'Name') to launch the instance
 E.g. subnet-123abcd`
 
-var subnets []string
 var subnetsSpec = `The Subnet (ID or tag 'Name') to launch the instances. Can be passed multiple times
 E.g. --subnet subnet-123abcd -n subnet-456efgh -n lorem-ipsum`
 
-var kernelID string
 var kernelIDSpec = `The ID of the Kernel`
 
-var monitoring bool
 var monitoringSpec = `Enables monitoring for the instances`
 
-var key string
 var keySpec = `Key name to access the instances`
 
-var sigtermTimeout string
 var sigtermTimeoutSpec = `Time duration to wait from when a task is stopped before its containers are forcefully killed if they do not exit normally on their own(default is 30s)
 Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", and "h"`
 
-var ebs bool
 var ebsSpec = `Use EBS optimized`
 
-var securityGroups []string
 var securityGroupsSpec = `Security Group (ID, name, or tag 'Name') for the instances. Can be passed multiple times
 E.g. --security-group sg-123abcd -g sg-456efgh -g lorem-ipsum`
 
-var instanceType string
 var instanceTypeSpec = `Type of instance to be launched
 E.g. m4.large`
 
-var instanceTypes []string
 var instanceTypesSpec = `Type of instance to be used by the Spot Fleet. Can be passed multiple times
 It's possible to change the units provided (target capacity) by a specific instance type adding a number after a colon (:) (default 1)
 E.g. --instance-type m4.large:2 -i c4.large:2 -i t3.medium`
 
-var credit string
 var creditSpec = `The credit option for CPU usage of a T2 (default 'standard') or T3 (default 'unlimited') instance
 Valid values:
 'standard'
 'unlimited'`
 
-var minimum int64
 var minimumSpec = `The minimum number of instances to launch
 If you specify a minimum that is more instances than Amazon EC2 can launch in the target Availability Zone, Amazon EC2 launches no instances`
 
-var maximum int64
 var maximumSpec = `The maximum number of instances to launch
 If you specify more instances than Amazon EC2 can launch in the target Availability Zone, Amazon EC2 launches the largest possible number of instances above MinCount`
 
-var tags []string
 var tagsSpec = `Tag to Spot Fleet instances as 'key=value'. Can be passed multiple times
 E.g. --tag Name=sample -t Project=sample -t Lorem=Ipsum`
+
+var registeredBySpec = `Show only the revisions registered by a principal containing the informed text
+E.g. --registered-by arn:aws:iam::123456789012:role/ci`
+
+var taskDefinitionSpec = `Task Definition (family or family:revision) used by the service`
+
+var portSpec = `Container port to expose through the load balancer`
+
+var targetGroupSpec = `ARN of an existing Target Group (target type 'ip') to register the service with`
+
+var createTargetGroupSpec = `Create a Target Group and a path rule on the informed --listener to expose the service`
+
+var listenerSpec = `ARN of the Load Balancer listener where the path rule will be created. Use with --create-target-group`
+
+var pathSpec = `Path prefix routed to the service by the listener rule (default is /[service name])`
+
+var desiredCountSpec = `Number of tasks to keep running`
+
+var assignPublicIPSpec = `Assign a public IP address to the tasks ENI`
+
+var previousSpec = `Show the logs of the stopped tasks of the deployment prior to the current one`
+
+var sinceSpec = `Show logs newer than a relative duration or a RFC3339 timestamp
+E.g. --since 10m, --since 2019-01-02T15:04:05Z`
+
+var filterPatternSpec = `CloudWatch Logs filter pattern applied to the events
+E.g. --filter-pattern ERROR, --filter-pattern '{ $.level = "error" }'`
+
+var waitSpec = `Wait until the deployment of the service is completed`
+
+var timeoutSpec = `Maximum time to wait before giving up
+Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", and "h"`
+
+var failFastSpec = `Stop waiting for the remaining services as soon as one of them fails`
+
+var allClustersSpec = `Apply to every cluster of the region instead of a single --cluster`
+
+var activeOnlySpec = `Ignore the services with no desired tasks`
+
+var standaloneTasksSpec = `Include the running tasks not started by a service`
+
+var stopReasonSpec = `Reason registered on the stopped tasks (default is "Stopped by ecsctl")`
+
+var waitStopSpec = `Wait until the tasks are STOPPED, which can take up to the largest stopTimeout of their containers.
+ecsctl gives up 2 minutes past it, or when interrupted, listing the tasks still stopping`
+
+var forceAfterSpec = `Stop waiting after the informed duration instead. ECS has no API to kill the containers before their stopTimeout,
+the tasks keep stopping on their own after ecsctl gives up`
+
+var stdinSpec = `Read the identifiers from the standard input, one per line. Blank lines and lines starting with # are ignored`
+
+var concurrencySpec = `Maximum number of items processed at the same time`
+
+var resourceTagsSpec = `Tag to apply as 'key=value'. Can be passed multiple times
+E.g. --tag Team=payments -t Environment=production`
+
+var canReachSpec = `Check if the service is allowed by the security groups to reach another service of the cluster`
+
+var reachPortSpec = `Port used by --can-reach`
+
+var expiresInSpec = `Minutes the scale-in protection lasts, from 1 up to 2880 (48 hours)`
+
+var releaseSpec = `Remove the scale-in protection of the tasks`
+
+var listProtectedSpec = `List the protected tasks of the cluster with the time remaining`
+
+var freezeReasonSpec = `Why the service is frozen
+E.g. --reason INC-1234`
+
+var rollbackToSpec = `Revision of the family to roll back to, instead of the one being replaced or the previous one`
+
+var overrideFreezeSpec = `Proceed even if the service was frozen with 'ecsctl services freeze'`
+
+var heartbeatSpec = `Print a status line when no log event was printed within the interval (only with --follow)
+E.g. --heartbeat 60s`
+
+var stallTimeoutSpec = `Exit with error when the RUNNING task produces no log events for the duration (only with --follow)
+E.g. --stall-timeout 15m`
+
+var stopOnStallSpec = `Stop the task when --stall-timeout is reached`
+
+var scheduleUpSpec = `When to scale the service up, as a cron expression
+E.g. --up 'cron(0 7 ? * MON-FRI *)'`
+
+var scheduleUpCountSpec = `Desired count of the service when scaled up`
+
+var scheduleDownSpec = `When to scale the service down, as a cron expression
+E.g. --down 'cron(0 20 ? * MON-FRI *)'`
+
+var scheduleDownCountSpec = `Desired count of the service when scaled down`
+
+var scheduleTimezoneSpec = `Timezone of the cron expressions
+E.g. --timezone Europe/Berlin`
+
+var listScheduleSpec = `List the scheduled scaling of the service`
+
+var deleteScheduleSpec = `Delete the scheduled scaling created by ecsctl for the service`
+
+var explainSpec = `When the task can not be placed, print why for each container instance of the cluster`
+
+var restartTaskSpec = `ID or ARN of the task to be replaced`
+
+var waitReplacementSpec = `Wait until the service started a replacement task and it is RUNNING and HEALTHY`
+
+var forceRestartSpec = `Restart even if the service is already running less tasks than desired`
+
+var tailServiceSpec = `Service to tail, optionally with its own filter pattern as service=pattern. Can be passed multiple times
+E.g. --service api --service worker='{ $.level = "error" }'`
+
+var sortWindowSpec = `How long events are held to be sorted with the ones of other services, tolerating clock skew`
+
+var validateLaunchTypeSpec = `Launch type to validate against, EC2 or FARGATE (default is chosen from the compatibilities)`
+
+var preflightSpec = `Check if the Task Definition can be placed on the cluster before proceeding`
+
+var logsContainerSpec = `Only read the logs of the container. Can be passed multiple times (default is every awslogs container)`
+
+var logsExcludeContainerSpec = `Do not read the logs of the container. Can be passed multiple times`
+
+var drainBatchSizeSpec = `How many instances are drained at a time, waiting for their tasks to move before the next batch (default is all at once)`
+
+var drainPlanSpec = `Only print the plan: batches, tasks to move and services that may dip below their desired count`
+
+var registrySpec = `ECR registry to log in to (default is the registry of the account)
+E.g. --registry 123456789012.dkr.ecr.us-east-1.amazonaws.com`
+
+var pushSpec = `Image tarball (from docker save) to be pushed to ECR as the deployed image (--image or --tag) before deploying`
+
+var buildContextSpec = `Directory to be built with docker and pushed to ECR as the deployed image (--image or --tag) before deploying`
+
+var olderThanSpec = `Only deployments existing for longer than the duration are considered stuck`
+
+var iKnowThisIsProdSpec = `Skip typing the cluster name when it matches the protected_clusters of the config file. --yes does not skip it`
+
+var untilSpec = `Show logs older than a relative duration or a RFC3339 timestamp (only with --output-dir, default is now)
+E.g. --until 2019-01-03T00:00:00Z`
+
+var outputDirSpec = `Export the logs within --since and --until to the directory, one gzip compressed NDJSON file per stream plus a manifest.json
+Streams already exported are skipped, so an interrupted export can be resumed`
+
+var dashboardNameSpec = `Name of the dashboard (default is [cluster]-[service])`
+
+var createDashboardSpec = `Create, or replace, the dashboard on CloudWatch and print its console URL`
+
+var printDashboardSpec = `Only print the dashboard body as JSON, e.g. to be committed to infrastructure as code`
+
+var sshCommandSpec = `Run the shell command with SSM Run Command and print its output, instead of opening an interactive session
+E.g. --command 'docker ps'`
+
+var revisionTagSpec = `Tag the registered revision as 'key=value', for traceability. Can be passed multiple times
+E.g. --revision-tag git-sha=abc123 --revision-tag pipeline=1234`
+
+var showTagsSpec = `Show the latest revision of each family with its tags`
+
+var familyDetailSpec = `Show the latest revision of each family with when and by whom it was registered`
+
+var maxLogRateSpec = `Print at most the number of log lines per second, dropping and counting the excess (default is no limit)`
+
+var allRegionsSpec = `Scan every region enabled on the account instead of only the current one`
+
+var inventoryCacheSpec = `Save the scan to the file, and reuse it instead of scanning again while it is not older than --cache-max-age`
+
+var inventoryCacheMaxAgeSpec = `How long a scan saved by --cache is reused`
+
+var healthEventsSpec = `Also show the health state changes of the targets of the services on their target groups, with the task of each target (only with --follow)`
+
+var taskDefinitionFileSpec = `Task Definition document, JSON or YAML, as registered or as described
+A local path, - for the standard input, s3://bucket/key or an https:// URL`
+
+var resolveDNSSpec = `Also print the Route 53 alias records pointing at the load balancers, scanning every hosted zone of the account`
+
+var pinRevisionSpec = `Switch a service configured by the bare family to the explicit revision being deployed`
+
+var keepPreviousSpec = `Keep the replaced revision ACTIVE instead of deregistering it, so services rollback can deploy it again`
+
+var ignoreRunningCheckSpec = `Do not check which revisions the running tasks use before deploying`
+
+var replaySpec = `Print the events spaced as they originally happened, with the position on the timeline on the standard error`
+
+var replaySpeedSpec = `How much faster than the original pacing --replay prints the events
+E.g. --speed 10x, --speed 0.5x`
+
+var noDelaySpec = `Print every event at once, without pacing (default)`
+
+var urlPathSpec = `Path requested on every endpoint of the service`
+
+var expectStatusSpec = `HTTP status the endpoints must answer with`
+
+var expectBodyContainsSpec = `Text the response body must contain`
+
+var verifyExecSpec = `Run the command informed after -- in a running task with ECS Exec instead of requesting the endpoints
+E.g. --exec -- /app/bin/smoke`
+
+var execContainerSpec = `Container to run the command in (default is the only container of the task)`
+
+var deployVerifySpec = `Check the service over HTTP once the deployment is completed, as 'services verify' does (only with --wait)`
+
+var verifyTimeoutSpec = `Maximum time to retry the --verify check before failing`
+
+var healthySpec = `Also wait until the tasks are HEALTHY, which needs a container health check on the Task Definition`
+
+var runContainerSpec = `Container the --command and --env overrides apply to (default is the first container). Only its logs are followed, instead of every container`
+
+var runCommandSpec = `Command overriding the one of the container, one argument per value or comma separated
+E.g. --command bin/rails,db:migrate`
+
+var runEnvSpec = `Environment variable set on the container as 'KEY=VALUE'. Can be passed multiple times
+E.g. --env RAILS_ENV=production --env DRY_RUN=1`
+
+var runLaunchTypeSpec = `Launch type of the task: EC2, FARGATE or EXTERNAL (default is the capacity provider strategy of the cluster)`
+
+var runSubnetsSpec = `The Subnet (ID or tag 'Name') of the task ENI, for Fargate or the awsvpc network mode. Can be passed multiple times
+Defaults to default_subnets on the config file`
+
+var runSecurityGroupsSpec = `Security Group (ID, name, or tag 'Name') of the task ENI. Can be passed multiple times
+Defaults to default_security_groups on the config file`
+
+var toStrategySpec = `Capacity provider strategy the service is moved to in place, with a forced deployment
+E.g. --to-strategy FARGATE_SPOT:weight=3,FARGATE:weight=1,base=1`
+
+var toLaunchTypeSpec = `Launch type the service is moved to, EC2 or FARGATE. A parallel service is created behind the same load balancers,
+then the original is scaled down and deleted. Run it again to resume an interrupted move`
+
+var moveSuffixSpec = `Suffix of the name of the parallel service created by --to-launch-type`
+
+var attachStdinSpec = `Pipe the standard input to the command: the task is run sleeping and the command (--command or the one of the container)
+is run in it with ECS Exec, then the task is stopped. Needs the AWS CLI and its Session Manager plugin`
+
+var outdatedAMISpec = `Only list the instances not on the latest ECS-optimized AMI of their family and architecture, with the age of their AMI
+The latest AMIs are read from the public SSM parameters /aws/service/ecs/optimized-ami/...`
+
+var namesOnlySpec = `Print the service names instead of their ARNs`
+
+var serviceEventsCountSpec = `How many of the most recent service events are shown`
+
+var fromTaskSpec = `ID or ARN of the task, running or stopped, the service is created from`
+
+var registerOverridesSpec = `Register the overrides the task was run with (command, environment, resources) as a new revision used by the service`
+
+var dropOverridesSpec = `Ignore the overrides the task was run with, the service runs the Task Definition as registered`
+
+var createTargetGroupArnSpec = `ARN of the Target Group the service is registered with. Use with --container-port`
+
+var containerPortSpec = `Container port registered on --target-group`
+
+var deployTaskDefinitionSpec = `Registered Task Definition (family or family:revision) to deploy, instead of registering a new revision with --image or --tag
+Without --task-definition, --image nor --tag a new deployment of the current revision is forced`
+
+var deployMarkerSpec = `When the deploy happened, as a duration ago (E.g. 10m) or a RFC3339 timestamp (default: creation of the primary deployment)`
+
+var errorPatternSpec = `CloudWatch Logs filter pattern of the error events`
+
+var errorWindowSpec = `Window after the deploy in which the error events are counted`
+
+var baselineWindowSpec = `Window before the deploy in which the error events are counted as the baseline`
+
+var errorThresholdSpec = `Fail when the error rate after the deploy is more than this times the baseline rate`
+
+var deployWatchErrorsSpec = `After the deploy, compare the rate of error logs with the one before it and fail when it got worse, as services watch-errors does`
+
+var logsServiceSpec = `Service whose running tasks logs are shown`
+
+var logsTaskSpec = `Task (ID or ARN) whose logs are shown`
+
+var requireLogsSpec = `Fail when the logs can not be read for lack of permissions, instead of following only the status of the task`
+
+var rawSpec = `Print the log messages verbatim, without formatting the JSON ones`
+
+var rawStringSpec = `Print the strings of JSON log messages without quoting nor escaping them`
+
+var hideStreamNameSpec = `Do not prefix the log messages with their stream`
+
+var hideDateSpec = `Do not prefix the log messages with their date`
+
+var invertSpec = `Use dark keys on JSON log messages, for light terminal backgrounds`
+
+var grepSpec = `Only print the log messages matching the regexp, checked on the raw message (applied after --filter-pattern)`
+
+var jsonlSpec = `Print the log events as JSON Lines, one object per event with its timestamp, stream, container and message (parsed when JSON), for jq`
+
+var grepVSpec = `Do not print the log messages matching the regexp, checked on the raw message`
+
+var gpusSpec = `GPUs reserved for the container, overriding the resourceRequirements of the Task Definition. The cluster must have GPU instances with as many available`
+
+var registerFamilySpec = `Family to register the revision on, instead of the one of the document`
+
+var registerImageSpec = `Replace the image of a container before registering, as CONTAINER=IMAGE (repeatable)
+E.g. --image app=123456789012.dkr.ecr.us-east-1.amazonaws.com/app:v42`
+
+var familyStatusSpec = `Status of the families listed: ACTIVE, INACTIVE (every revision deregistered) or ALL`
+
+var familyPrefixSpec = `Only list the families starting with the prefix, the same as the argument`
+
+var maxListedSpec = `Stop after listing this many (default 0, no limit)`
+
+var revisionDetailSpec = `Show the status, registration date and principal of each revision, describing them one by one`
+
+var capacityTaskDefinitionSpec = `Task Definition (family or family:revision) to be deployed, instead of the current one of the service`
+
+var capacityCheckSpec = `Before deploying, check the container instances have room for the extra tasks of the rollout (maximumPercent), as services capacity-check does`
+
+var tasksServiceSpec = `Only list the tasks of the service`
+
+var taskStatusSpec = `Desired status of the tasks listed
+Valid values:
+'RUNNING' (default)
+'STOPPED'`
+
+var logGroupSpec = `Log group of the containers, instead of the awslogs-group of the Task Definition`
+
+var logStreamPrefixSpec = `Log stream prefix of the containers, instead of the awslogs-stream-prefix of the Task Definition`
+
+var pollIntervalSpec = `Interval between the polls of the logs and the status of the task while following it`
+
+var keepTaskDefSpec = `Do not deregister the Task Definition revision of the service`
+
+var decommissionPlanSpec = `Only list the steps and the resources affected, without acting`
+
+var execTaskSpec = `Task to open the shell in`
+
+var execServiceSpec = `Open the shell in the first RUNNING task of the service, instead of informing --task`
+
+var execCommandSpec = `Command to run interactively in the container`
+
+var enableExecuteCommandSpec = `Enable ECS Exec on the tasks of the service (--enable-execute-command=false disables it)`
+
+var noRedeploySpec = `Do not replace the running tasks, only the tasks started afterwards get the update`
+
+var whereSpec = `Only show JSON messages whose field matches, repeatable (every condition must match). Applied after --filter-pattern
+Operators: = and != compare strings, > and < numbers, ~ a regexp. Nested fields are separated by dots
+E.g. --where level=error --where request.id=abc123 --where duration>500`
+
+var keepUnparsedSpec = `Also show the messages that are not JSON, which --where excludes`
+
+var scaleWaitSpec = `Wait until the services run the desired count with no task pending, printing their new events meanwhile`
+
+var keepWarmSpec = `Keep the task running for the duration, e.g. 30m, to run the command again with 'task-definitions rerun' without a new task.
+The task is run sleeping and the command (after --, --command or the one of the container) is run in it with ECS Exec.
+Needs the AWS CLI and its Session Manager plugin. The task is billed for the whole duration, stop it earlier with 'tasks stop'`
+
+var rerunTaskSpec = `Task run with --keep-warm to run the command in`
+
+var runWaitSpec = `Wait until the task stops, printing its status changes, and exit with the exit code of the container.
+Unlike --follow, it works with any log driver. --follow implies it`
+
+var runTimeoutSpec = `Stop the task and exit non-zero if it did not finish in time, with --wait or --follow (default 0, no timeout)`
+
+var waitContainerSpec = `Container whose exit code is returned (default is the first container)`
+
+var tasksWaitTimeoutSpec = `Exit non-zero if the task did not stop in time, leaving it running (default 0, no timeout)`
+
+var stopOnTimeoutSpec = `Also stop the task when --timeout is reached`
+
+var waitPollIntervalSpec = `Interval between the polls of the status of the task`
+
+var classifyOnlySpec = `Only print whether the stopped task failed on its container or on AWS, and the exit code it maps to`
+
+var describeRecursiveSpec = `Also summarize the resources the service references: roles and their policies, log groups, target groups,
+security groups, Cloud Map services and auto scaling. A lookup denied is shown as such instead of failing`
+
+var diffServiceSpec = `Compare the revision the service runs with the latest revision of its family, instead of informing the revisions`
+
+var diffContextSpec = `Lines of context around each change`
+
+var auditSinceSpec = `Only list the changes newer than a relative duration or a RFC3339 timestamp
+E.g. --since 7d, --since 2019-01-02T15:04:05Z`
+
+var auditClusterSpec = `Only list the changes to the cluster (default is the default cluster, if any)`
+
+var auditAllClustersSpec = `List the changes to every cluster, even with a default cluster`
+
+var scheduleSpec = `When to run the task, as an EventBridge schedule expression (UTC)
+E.g. --schedule "rate(5 minutes)", --schedule "cron(0 3 * * ? *)"`
+
+var scheduleTaskDefinitionSpec = `Task Definition to run: a family (its latest revision when created), family:revision or an ARN`
+
+var scheduleLatestSpec = `Target the family instead of a revision, so each run picks up the latest ACTIVE revision`
+
+var scheduleRoleSpec = `IAM role (ARN or name) EventBridge assumes to run the task, allowed to ecs:RunTask and to pass the task roles`
+
+var scheduleCountSpec = `Number of tasks run on each schedule`
+
+var scheduleDescriptionSpec = `Description of the rule (default names the Task Definition and the cluster)`
+
+var eventsFollowSpec = `Keep polling the services, printing their new events until interrupted`
+
+var eventsSinceSpec = `Only show events newer than a relative duration or a RFC3339 timestamp
+E.g. --since 1h, --since 2019-01-02T15:04:05Z`
+
+var eventsPollIntervalSpec = `Interval between the polls of the services with --follow`
+
+var drainWaitSpec = `Wait until no task is left on the instances of the last batch too, e.g. before terminating them`
+
+var drainTimeoutSpec = `Maximum time to wait for the tasks to move out of a batch, failing after it`
+
+var runCountSpec = `Number of tasks to run, from 1 to 10`
+
+var runGroupSpec = `Task group of the tasks, e.g. to spread them apart with a placement constraint (default is family:FAMILY)`
+
+var runStartedBySpec = `Identify who or what started the tasks, as ListTasks can filter them by it (default is ecsctl)`
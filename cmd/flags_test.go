@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func walkCommands(cmd *cobra.Command, visit func(*cobra.Command)) {
+	visit(cmd)
+	for _, child := range cmd.Commands() {
+		walkCommands(child, visit)
+	}
+}
+
+// TestFlagsOwnedByCommand fails when two commands bind their flags to the same variable, which
+// would make them share state. The aliases share the flag itself with the canonical command, and a
+// command may bind two names to one option, such as --plan and --dry-run.
+func TestFlagsOwnedByCommand(t *testing.T) {
+	type binding struct {
+		flag *pflag.Flag
+		path string
+	}
+	bound := make(map[uintptr]binding)
+
+	walkCommands(rootCmd, func(cmd *cobra.Command) {
+		cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			value := reflect.ValueOf(f.Value)
+			if value.Kind() != reflect.Ptr {
+				return
+			}
+
+			previous, ok := bound[value.Pointer()]
+			if !ok {
+				bound[value.Pointer()] = binding{f, cmd.CommandPath()}
+				return
+			}
+
+			if previous.flag != f && previous.path != cmd.CommandPath() {
+				t.Errorf("--%s of %s shares its variable with --%s of %s", f.Name, cmd.CommandPath(), previous.flag.Name, previous.path)
+			}
+		})
+	})
+}
+
+func TestFlagsOfTheSameName(t *testing.T) {
+	parseFlags(t, taskDefinitionsRunCmd, []string{"--cluster", "staging", "--container", "worker", "--tag", "team=a"})
+	parseFlags(t, servicesDeployCmd, []string{"-c", "production", "--container", "web", "-t", "v2"})
+
+	if taskDefinitionsRunOpts.cluster != "staging" || taskDefinitionsRunOpts.container != "worker" {
+		t.Errorf("run got --cluster %s --container %s", taskDefinitionsRunOpts.cluster, taskDefinitionsRunOpts.container)
+	}
+	if len(taskDefinitionsRunOpts.tags) != 1 || taskDefinitionsRunOpts.tags[0] != "team=a" {
+		t.Errorf("run got --tag %v", taskDefinitionsRunOpts.tags)
+	}
+	if servicesDeployOpts.cluster != "production" || servicesDeployOpts.containerName != "web" || servicesDeployOpts.tag != "v2" {
+		t.Errorf("deploy got --cluster %s --container %s --tag %s", servicesDeployOpts.cluster, servicesDeployOpts.containerName, servicesDeployOpts.tag)
+	}
+}
+
+func TestPluralNetworkFlags(t *testing.T) {
+	tests := []struct {
+		cmd            *cobra.Command
+		subnets        *[]string
+		securityGroups *[]string
+	}{
+		{taskDefinitionsRunCmd, &taskDefinitionsRunOpts.subnets, &taskDefinitionsRunOpts.securityGroups},
+		{servicesQuickstartCmd, &servicesQuickstartOpts.subnets, &servicesQuickstartOpts.securityGroups},
+		{servicesMoveCmd, &servicesMoveOpts.subnets, &servicesMoveOpts.securityGroups},
+		{scheduledTasksCreateCmd, &scheduledTasksCreateOpts.subnets, &scheduledTasksCreateOpts.securityGroups},
+		{clustersAddSpotFleetCmd, &clustersAddSpotFleetOpts.subnets, &clustersAddSpotFleetOpts.securityGroups},
+	}
+
+	for _, test := range tests {
+		parseFlags(t, test.cmd, []string{"--subnets", "subnet-a,subnet-b", "--security-group", "sg-a", "--security-groups", "sg-b"})
+
+		if want := []string{"subnet-a", "subnet-b"}; !reflect.DeepEqual(*test.subnets, want) {
+			t.Errorf("%s got --subnet %v, want %v", test.cmd.CommandPath(), *test.subnets, want)
+		}
+		if want := []string{"sg-a", "sg-b"}; !reflect.DeepEqual(*test.securityGroups, want) {
+			t.Errorf("%s got --security-group %v, want %v", test.cmd.CommandPath(), *test.securityGroups, want)
+		}
+	}
+}
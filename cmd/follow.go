@@ -0,0 +1,701 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+)
+
+type followOptions struct {
+	exit          bool
+	heartbeat     time.Duration
+	stallTimeout  time.Duration
+	stopOnStall   bool
+	since         time.Time
+	filterPattern string
+	maxLogRate    int
+	container     string
+	requireLogs   bool
+	containers    containerFilter
+	pollInterval  time.Duration
+	timeout       time.Duration
+	stopOnTimeout bool
+	logLocation   logLocation
+	output        outputConfiguration
+}
+
+// seenEvents skips the events already handled. Polls start again from the last seen timestamp,
+// so the events sharing it come again and are told apart by their ID.
+type seenEvents struct {
+	lastSeenTime *int64
+	seenEventIDs map[string]bool
+}
+
+// add marks the event as seen, telling if it was not seen before
+func (s *seenEvents) add(event *cloudwatchlogs.FilteredLogEvent) bool {
+	if s.lastSeenTime == nil || *event.Timestamp > *s.lastSeenTime {
+		s.lastSeenTime = event.Timestamp
+		s.seenEventIDs = make(map[string]bool, 0)
+	}
+
+	if s.seenEventIDs[*event.EventId] {
+		return false
+	}
+
+	s.seenEventIDs[*event.EventId] = true
+	return true
+}
+
+// logRateLimiter caps the printed lines per second, counting the dropped ones to summarize them periodically
+type logRateLimiter struct {
+	max          int
+	second       time.Time
+	printed      int
+	suppressed   int64
+	total        int64
+	summarizedAt time.Time
+}
+
+const logRateSummaryInterval = 10 * time.Second
+
+// allow tells if one more line can be printed on the current second, with no limit when max is 0
+func (l *logRateLimiter) allow() bool {
+	if l.max <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(l.second) >= time.Second {
+		l.second = now
+		l.printed = 0
+	}
+
+	if l.printed < l.max {
+		l.printed++
+		return true
+	}
+
+	l.suppressed++
+	l.total++
+	return false
+}
+
+// summarize prints how many lines were dropped since the last summary, at most once per interval
+func (l *logRateLimiter) summarize() {
+	if l.summarizedAt.IsZero() {
+		l.summarizedAt = time.Now()
+	}
+
+	if l.suppressed == 0 || time.Since(l.summarizedAt) < logRateSummaryInterval {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "[ecsctl] suppressed %s lines in the last %s (use --filter-pattern to narrow)\n",
+		thousands(l.suppressed), time.Since(l.summarizedAt).Round(time.Second))
+	l.suppressed = 0
+	l.summarizedAt = time.Now()
+}
+
+// finish prints the total of dropped lines
+func (l *logRateLimiter) finish() {
+	if l.total > 0 {
+		fmt.Fprintf(os.Stderr, "[ecsctl] suppressed %s lines in total by --max-log-rate %d\n", thousands(l.total), l.max)
+	}
+}
+
+// thousands formats the number with comma separators, e.g. 12,340
+func thousands(n int64) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// Until the task is RUNNING, or for logStreamScanDelay, only the expected stream is looked up. The streams of the
+// prefix are then scanned at most every logStreamScanInterval, as DescribeLogStreams has a low rate limit per account.
+const (
+	logStreamScanDelay    = 30 * time.Second
+	logStreamScanInterval = 15 * time.Second
+)
+
+// findLogStream checks if the expected stream exists in the log group.
+// When it does not and scan is set, a stream under the prefix naming the container and ending with the task ID
+// is looked up instead, since the awslogs driver does not build the name the same way on every platform (e.g. Windows).
+// An empty name is returned while no stream was created yet.
+func findLogStream(logGroup, logPrefix, expected, container, taskID string, scan bool) (name string, err error) {
+	exact, err := cwlI.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName:        aws.String(logGroup),
+		LogStreamNamePrefix: aws.String(expected),
+	})
+	if err != nil {
+		return
+	}
+
+	for _, stream := range exact.LogStreams {
+		if aws.StringValue(stream.LogStreamName) == expected {
+			name = expected
+			return
+		}
+	}
+
+	if !scan {
+		return
+	}
+
+	input := &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(logGroup),
+	}
+
+	if logPrefix != "" {
+		input.LogStreamNamePrefix = aws.String(logPrefix)
+	}
+
+	err = cwlI.DescribeLogStreamsPages(input, func(page *cloudwatchlogs.DescribeLogStreamsOutput, lastPage bool) bool {
+		for _, stream := range page.LogStreams {
+			if n := aws.StringValue(stream.LogStreamName); strings.HasSuffix(n, taskID) && strings.Contains(n, container) {
+				name = aws.StringValue(stream.LogStreamName)
+				return false
+			}
+		}
+		return !lastPage
+	})
+
+	return
+}
+
+// logStreamGrace is how long a RUNNING task goes without its log stream before the existing ones are listed
+const logStreamGrace = 60 * time.Second
+
+// reportMissingStream lists the streams of the group under the prefix, so a group or a prefix
+// not matching the ones the awslogs driver used can be spotted
+func reportMissingStream(logGroup, logPrefix, expected string) {
+	input := &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(logGroup),
+		Limit:        aws.Int64(20),
+	}
+
+	// Only the streams of the whole group can be sorted, the most recent ones are the likely candidates
+	if logPrefix != "" {
+		input.LogStreamNamePrefix = aws.String(logPrefix)
+	} else {
+		input.OrderBy = aws.String(cloudwatchlogs.OrderByLastEventTime)
+		input.Descending = aws.Bool(true)
+	}
+
+	fmt.Fprintf(os.Stderr, "warning: log stream %s not found in %s after %s running. --log-group and --log-stream-prefix override the ones of the Task Definition\n",
+		expected, logGroup, logStreamGrace)
+
+	output, err := cwlI.DescribeLogStreams(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to list the log streams: %s\n", err.Error())
+		return
+	}
+
+	if len(output.LogStreams) == 0 {
+		fmt.Fprintf(os.Stderr, "no log streams in %s under the prefix '%s'\n", logGroup, logPrefix)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "log streams in %s under the prefix '%s':\n", logGroup, logPrefix)
+	for _, stream := range output.LogStreams {
+		fmt.Fprintf(os.Stderr, "  %s\n", aws.StringValue(stream.LogStreamName))
+	}
+}
+
+// logGroupArn builds the ARN of the log group in the region and account of the task
+func logGroupArn(taskArn, logGroup string) string {
+	parsed, err := arn.Parse(taskArn)
+	if err != nil {
+		return logGroup
+	}
+
+	return arn.ARN{
+		Partition: parsed.Partition,
+		Service:   "logs",
+		Region:    parsed.Region,
+		AccountID: parsed.AccountID,
+		Resource:  "log-group:" + logGroup,
+	}.String()
+}
+
+// printTaskStarted writes the task ARN and its log streams to the standard error as soon as it is started,
+// so the task can be attached again if the follow is interrupted
+func printTaskStarted(t *ecs.Task, td *ecs.TaskDefinition, location logLocation) {
+	fmt.Fprintf(os.Stderr, "task %s\n", aws.StringValue(t.TaskArn))
+
+	for _, stream := range location.resolve(taskLogStreams(td, taskID(aws.StringValue(t.TaskArn)))) {
+		fmt.Fprintf(os.Stderr, "log stream %s %s\n", stream.group, stream.name)
+	}
+}
+
+// printTaskStopped writes why the task stopped and the exit code of each container to the standard error
+func printTaskStopped(t *ecs.Task) {
+	fmt.Fprintf(os.Stderr, "task %s stopped: %s %s\n",
+		taskID(aws.StringValue(t.TaskArn)), aws.StringValue(t.StopCode), aws.StringValue(t.StoppedReason))
+
+	for _, c := range t.Containers {
+		exitCode := "-"
+		if c.ExitCode != nil {
+			exitCode = fmt.Sprint(aws.Int64Value(c.ExitCode))
+		}
+
+		line := fmt.Sprintf("  %s exit code %s", aws.StringValue(c.Name), exitCode)
+		if c.Reason != nil {
+			line += ": " + aws.StringValue(c.Reason)
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// taskExitCode is the exit code of the container once the task stopped, unless another essential
+// container exited non-zero, whose code is returned instead so a crashed sidecar is not hidden.
+// A container that never exited (e.g. its image could not be pulled) is a failure.
+func taskExitCode(t *ecs.Task, td *ecs.TaskDefinition, container string) int {
+	essential := make(map[string]bool)
+	for _, cd := range td.ContainerDefinitions {
+		// containers are essential unless declared otherwise
+		essential[aws.StringValue(cd.Name)] = cd.Essential == nil || aws.BoolValue(cd.Essential)
+	}
+
+	exitCode := 1
+	for _, c := range t.Containers {
+		if aws.StringValue(c.Name) == container && c.ExitCode != nil {
+			exitCode = int(aws.Int64Value(c.ExitCode))
+		}
+	}
+
+	if exitCode != 0 {
+		return exitCode
+	}
+
+	for _, c := range t.Containers {
+		if !essential[aws.StringValue(c.Name)] || aws.StringValue(c.Name) == container {
+			continue
+		}
+
+		if c.ExitCode == nil {
+			return 1
+		}
+
+		if code := int(aws.Int64Value(c.ExitCode)); code != 0 {
+			return code
+		}
+	}
+	return 0
+}
+
+// followedStreams are the streams of the followed containers using the awslogs driver,
+// or of every followed container when --log-group tells where their logs are
+func followedStreams(td *ecs.TaskDefinition, id string, containers containerFilter, location logLocation) (streams []logStream) {
+	for _, cd := range td.ContainerDefinitions {
+		name := aws.StringValue(cd.Name)
+		if !containers.allows(name) {
+			continue
+		}
+
+		var options map[string]*string
+		if cd.LogConfiguration != nil && aws.StringValue(cd.LogConfiguration.LogDriver) == "awslogs" {
+			options = cd.LogConfiguration.Options
+		} else if location.group == "" {
+			continue
+		}
+
+		prefix := aws.StringValue(options["awslogs-stream-prefix"])
+		streams = append(streams, logStream{
+			group:     aws.StringValue(options["awslogs-group"]),
+			prefix:    prefix,
+			name:      prefix + "/" + name + "/" + id,
+			container: name,
+			taskID:    id,
+		})
+	}
+
+	return location.resolve(streams)
+}
+
+// followedGroup polls the streams of a log group, FilterLogEvents reads many streams but of a single group
+type followedGroup struct {
+	group     string
+	pending   []logStream
+	found     []logStream
+	input     cloudwatchlogs.FilterLogEventsInput
+	seen      seenEvents
+	backoff   time.Duration
+	retryAt   time.Time
+	scannedAt time.Time
+}
+
+// reportTaskStatus writes the status of the task to the standard error when it changed from the previous one,
+// the stop is reported by printTaskStopped
+func reportTaskStatus(t *ecs.Task, previous string) string {
+	status := aws.StringValue(t.LastStatus)
+	if status != previous && status != ecs.DesiredStatusStopped {
+		fmt.Fprintf(os.Stderr, "task %s %s\n", taskID(aws.StringValue(t.TaskArn)), status)
+	}
+	return status
+}
+
+// checkTimeout exits non-zero once the task is waited for longer than the timeout, 0 for no timeout.
+// The task is only stopped first with stop, otherwise it is left running.
+func checkTimeout(cluster string, task *ecs.Task, timeout time.Duration, stop bool, since time.Time) {
+	if timeout == 0 || time.Since(since) < timeout {
+		return
+	}
+
+	if !stop {
+		fmt.Fprintf(os.Stderr, "task %s not finished after %s, left running\n", taskID(aws.StringValue(task.TaskArn)), timeout)
+		exit(1)
+	}
+
+	_, err := ecsI.StopTask(&ecs.StopTaskInput{
+		Cluster: aws.String(cluster),
+		Task:    task.TaskArn,
+		Reason:  aws.String("Timed out: not finished after " + timeout.String()),
+	})
+	must(err)
+
+	fmt.Fprintf(os.Stderr, "task %s stopped, not finished after %s\n", taskID(aws.StringValue(task.TaskArn)), timeout)
+	exit(1)
+}
+
+// stopInterruptedTask stops the task on the interrupt of a wait with --exit. The wait goes on until the task is STOPPED,
+// so the logs of its shutdown and its exit code are not lost. The second interrupt exits right away.
+func stopInterruptedTask(cluster string, task *ecs.Task) {
+	id := taskID(aws.StringValue(task.TaskArn))
+
+	_, err := ecsI.StopTask(&ecs.StopTaskInput{
+		Cluster: aws.String(cluster),
+		Task:    task.TaskArn,
+		Reason:  aws.String("Interrupted: stopped by ecsctl --exit"),
+	})
+	if err != nil {
+		must(fmt.Errorf("Unable to stop task %s: %s", id, err.Error()))
+	}
+
+	fmt.Fprintf(os.Stderr, "stopping task %s, waiting for it to stop\n", id)
+}
+
+// detachTask ends the wait interrupted without --exit, the task keeps running and can be attached again
+func detachTask(cluster string, task *ecs.Task) {
+	id := taskID(aws.StringValue(task.TaskArn))
+
+	fmt.Fprintf(os.Stderr, "detached from task %s, follow it again with: ecsctl task-definitions attach %s -c %s\n", id, id, cluster)
+	exit(130)
+}
+
+// waitTask polls the status of the task until it stops, whatever the log driver of its containers,
+// and exits with the exit code of the container
+func waitTask(cluster string, task *ecs.Task, td *ecs.TaskDefinition, opts followOptions) {
+	ctx := interruptContext()
+	since := time.Now()
+
+	cd, err := containerDefinition(td, opts.container)
+	must(err)
+
+	status := ""
+	stopping := false
+	for {
+		tasks, err := describeTasks(cluster, []*string{task.TaskArn})
+		must(err)
+
+		status = reportTaskStatus(tasks[0], status)
+		if status == ecs.DesiredStatusStopped {
+			printTaskStopped(tasks[0])
+			exitTask(tasks[0], td, aws.StringValue(cd.Name))
+		}
+
+		if stopping {
+			time.Sleep(opts.pollInterval)
+			continue
+		}
+
+		checkTimeout(cluster, task, opts.timeout, opts.stopOnTimeout, since)
+
+		if !sleepContext(ctx, opts.pollInterval) {
+			if !opts.exit {
+				break
+			}
+
+			stopInterruptedTask(cluster, task)
+			stopping = true
+		}
+	}
+
+	detachTask(cluster, task)
+}
+
+// followTask follows the logs and the status of a task until it stops, exiting with the exit code of its container.
+// It is shared by run --follow and attach, so following a task again behaves as if it was never interrupted.
+func followTask(cluster string, task *ecs.Task, td *ecs.TaskDefinition, opts followOptions) {
+	ctx := interruptContext()
+
+	id := taskID(aws.StringValue(task.TaskArn))
+
+	cd, err := containerDefinition(td, opts.container)
+	must(err)
+
+	cName := cd.Name
+
+	streams := followedStreams(td, id, opts.containers, opts.logLocation)
+	if len(streams) == 0 {
+		fmt.Fprintln(os.Stderr, "warning: no followed container logs with the awslogs driver, following only the status of the task")
+	}
+
+	// Events are labeled by container when more than one is followed
+	labels := make(map[string]string)
+	width := 0
+	for _, stream := range streams {
+		if len(stream.container) > width {
+			width = len(stream.container)
+		}
+	}
+
+	var groups []*followedGroup
+	byGroup := make(map[string]*followedGroup)
+	for i, stream := range streams {
+		if len(streams) > 1 {
+			labels[stream.container] = color.New(serviceLabelColors[i%len(serviceLabelColors)]).Sprintf("%-*s", width, stream.container)
+		}
+
+		g := byGroup[stream.group]
+		if g == nil {
+			g = &followedGroup{group: stream.group}
+			g.input.LogGroupName = aws.String(stream.group)
+
+			if !opts.since.IsZero() {
+				g.input.SetStartTime(aws.TimeUnixMilli(opts.since))
+			}
+
+			if opts.filterPattern != "" {
+				g.input.SetFilterPattern(opts.filterPattern)
+			}
+
+			byGroup[stream.group] = g
+			groups = append(groups, g)
+		}
+
+		g.pending = append(g.pending, stream)
+	}
+
+	// The container of the event is found by its stream, as named once found
+	containerOf := make(map[string]string)
+
+	limiter := &logRateLimiter{max: opts.maxLogRate}
+
+	// Without permission on the logs the outcome of the task still matters more, only its status keeps being followed
+	logsDenied := len(streams) == 0
+	denyLogs := func(action, logGroup string) {
+		message := fmt.Sprintf("Access denied reading the logs, %s is needed on %s", action, logGroupArn(aws.StringValue(task.TaskArn), logGroup))
+		if opts.requireLogs {
+			must(errors.New(message))
+		}
+
+		fmt.Fprintf(os.Stderr, "warning: %s. Following only the status of the task\n", message)
+		logsDenied = true
+	}
+
+	// lastEventAt only starts counting once the task is RUNNING, pulling images is not a stall
+	var lastEventAt, lastHeartbeatAt time.Time
+	missingReported := make(map[string]bool)
+	lastPoll := "OK"
+	dim := color.New(color.Faint).SprintFunc()
+
+	handlePage := func(g *followedGroup) func(*cloudwatchlogs.FilterLogEventsOutput, bool) bool {
+		return func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+			for _, event := range page.Events {
+				// Dropped and grepped out events are still marked as seen, the same as the printed ones
+				if g.seen.add(event) {
+					if opts.output.shows(event) && limiter.allow() {
+						printLabeledEvent(&opts.output, labels[containerOf[aws.StringValue(event.LogStreamName)]], event)
+					}
+					lastEventAt = time.Now()
+				}
+			}
+			limiter.summarize()
+			return !lastPage
+		}
+	}
+
+	// lookUp finds the streams of the group not created yet, the awslogs driver creates them as the containers start
+	lookUp := func(g *followedGroup, scan bool) {
+		if scan {
+			g.scannedAt = time.Now()
+		}
+
+		var pending []logStream
+		for _, stream := range g.pending {
+			name, err := findLogStream(g.group, stream.prefix, stream.name, stream.container, id, scan)
+
+			switch code := awsErrorCode(err); {
+			case code == "AccessDeniedException":
+				denyLogs("logs:DescribeLogStreams", g.group)
+				return
+			case code == cloudwatchlogs.ErrCodeResourceNotFoundException:
+				// Unlike a stream not created yet, a missing group never shows up
+				must(fmt.Errorf("Log group %s not found, --log-group overrides the one of the Task Definition", g.group))
+			case err != nil:
+				debugf("unable to look up the log stream: %s", err.Error())
+			}
+
+			if name == "" {
+				pending = append(pending, stream)
+				continue
+			}
+
+			debugf("following log stream %s (expected %s)", name, stream.name)
+			containerOf[name] = stream.container
+			stream.name = name
+			g.found = append(g.found, stream)
+			g.input.LogStreamNames = append(g.input.LogStreamNames, aws.String(name))
+		}
+		g.pending = pending
+	}
+
+	// Only consecutive unexpected errors count against the retry limit, throttling and 5xx are just backed off.
+	// The task status keeps being polled meanwhile, so its end is never missed.
+	retryCount := 0
+	retryLimit := 50
+	var throttledNoticeAt time.Time
+	since := time.Now()
+	status := ""
+	stopping := false
+	for {
+		for _, g := range groups {
+			if logsDenied || time.Now().Before(g.retryAt) {
+				continue
+			}
+
+			if len(g.pending) > 0 {
+				scan := (status == ecs.DesiredStatusRunning || time.Since(since) >= logStreamScanDelay) &&
+					time.Since(g.scannedAt) >= logStreamScanInterval
+				lookUp(g, scan)
+			}
+
+			if logsDenied || len(g.input.LogStreamNames) == 0 {
+				continue
+			}
+
+			err := cwlI.FilterLogEventsPages(&g.input, handlePage(g))
+			lastPoll = "OK"
+
+			switch {
+			case err == nil:
+				g.backoff = 0
+				retryCount = 0
+			case isThrottledOrUnavailable(err):
+				lastPoll = "throttled"
+				g.backoff = nextBackoff(g.backoff)
+				g.retryAt = time.Now().Add(jitter(g.backoff))
+
+				if time.Since(throttledNoticeAt) >= 30*time.Second {
+					fmt.Fprintf(os.Stderr, "CloudWatch throttled, retrying in %s...\n", g.backoff)
+					throttledNoticeAt = time.Now()
+				}
+			case awsErrorCode(err) == "AccessDeniedException":
+				denyLogs("logs:FilterLogEvents", g.group)
+			case awsErrorCode(err) == cloudwatchlogs.ErrCodeResourceNotFoundException:
+				// Same as streams not created yet, they are looked up again
+				debugf("log stream not found: %s", err.Error())
+				g.pending = append(g.pending, g.found...)
+				g.found = nil
+				g.input.LogStreamNames = nil
+			default:
+				lastPoll = "failed"
+				retryCount = retryCount + 1
+
+				if retryCount >= retryLimit {
+					fmt.Fprintln(os.Stderr, err.Error())
+					exit(1)
+				}
+			}
+
+			if g.seen.lastSeenTime != nil {
+				g.input.SetStartTime(*g.seen.lastSeenTime)
+			}
+		}
+
+		tasksStatus, err := describeTasks(cluster, []*string{aws.String(id)})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			exit(1)
+		}
+
+		status = reportTaskStatus(tasksStatus[0], status)
+		if status == "STOPPED" {
+			limiter.finish()
+			printTaskStopped(tasksStatus[0])
+			exitTask(tasksStatus[0], td, aws.StringValue(cName))
+		}
+
+		if !stopping {
+			checkTimeout(cluster, task, opts.timeout, opts.stopOnTimeout, since)
+		}
+
+		if status == ecs.DesiredStatusRunning && !stopping {
+			if lastEventAt.IsZero() {
+				lastEventAt = time.Now()
+			}
+
+			silence := time.Since(lastEventAt)
+
+			if !logsDenied && time.Since(aws.TimeValue(tasksStatus[0].StartedAt)) >= logStreamGrace {
+				for _, g := range groups {
+					for _, stream := range g.pending {
+						if !missingReported[stream.container] {
+							reportMissingStream(g.group, stream.prefix, stream.name)
+							missingReported[stream.container] = true
+						}
+					}
+				}
+			}
+
+			if opts.heartbeat > 0 && silence >= opts.heartbeat && time.Since(lastHeartbeatAt) >= opts.heartbeat {
+				running := time.Since(aws.TimeValue(tasksStatus[0].StartedAt)).Round(time.Minute)
+				fmt.Fprintln(os.Stderr, dim(fmt.Sprintf("[ecsctl] task %s for %s, no new logs for %s, last poll %s", status, running, silence.Round(time.Minute), lastPoll)))
+				lastHeartbeatAt = time.Now()
+			}
+
+			if opts.stallTimeout > 0 && !logsDenied && silence >= opts.stallTimeout {
+				fmt.Fprintf(os.Stderr, "task %s produced no log events for %s\n", id, silence.Round(time.Second))
+
+				if opts.stopOnStall {
+					_, err := ecsI.StopTask(&ecs.StopTaskInput{
+						Cluster: aws.String(cluster),
+						Task:    task.TaskArn,
+						Reason:  aws.String("Stalled: no log events for " + silence.Round(time.Second).String()),
+					})
+					must(err)
+				}
+
+				exit(1)
+			}
+		}
+
+		// Once stopping, the logs of the shutdown keep being followed until the task is STOPPED
+		if stopping {
+			time.Sleep(opts.pollInterval)
+			continue
+		}
+
+		if !sleepContext(ctx, opts.pollInterval) {
+			if !opts.exit {
+				break
+			}
+
+			stopInterruptedTask(cluster, task)
+			stopping = true
+		}
+	}
+
+	limiter.finish()
+	detachTask(cluster, task)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// getAlias mirrors a list or describe command under the get verb.
+// The Run and the flags are the ones of the canonical command, so both never diverge.
+// It must be called on the init of the canonical command, after its flags are defined.
+func getAlias(canonical *cobra.Command, use string, aliases ...string) *cobra.Command {
+	return mirrorCommand(getCmd, canonical, use, aliases...)
+}
+
+// mirrorCommand adds to the parent a command sharing the Run and the flags of the canonical one
+func mirrorCommand(parent, canonical *cobra.Command, use string, aliases ...string) *cobra.Command {
+	alias := &cobra.Command{
+		Use:         use,
+		Short:       canonical.Short,
+		Long:        canonical.Short + "\n\nAlias of '" + canonical.CommandPath() + "'",
+		Aliases:     aliases,
+		Args:        canonical.Args,
+		Run:         canonical.Run,
+		Annotations: canonical.Annotations,
+	}
+
+	alias.Flags().AddFlagSet(canonical.Flags())
+
+	parent.AddCommand(alias)
+	return alias
+}
+
+func getRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get [resource]",
+	Short: "Aliases of the list and describe commands, by resource",
+	Run:   getRun,
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+}
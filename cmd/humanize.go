@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+var absoluteTimes bool
+var absoluteTimesSpec = `Show the exact timestamps on tables and texts instead of relative times (e.g. 3m ago). JSON is always exact`
+
+// humanizeDuration rounds down to the largest unit: 59s, 1m, 23h, 2d
+func humanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
+
+// humanizeTime is the time relative to now for people to read, the RFC3339 timestamp with --absolute-times.
+// Times slightly in the future come from clock skew and are just now.
+func humanizeTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+
+	if absoluteTimes {
+		return t.Format(time.RFC3339)
+	}
+
+	elapsed := time.Since(*t)
+	switch {
+	case elapsed < -time.Minute:
+		return "in " + humanizeDuration(elapsed)
+	case elapsed < time.Second:
+		return "just now"
+	}
+	return humanizeDuration(elapsed) + " ago"
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&absoluteTimes, "absolute-times", false, absoluteTimesSpec)
+}
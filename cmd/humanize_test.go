@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		d     time.Duration
+		human string
+	}{
+		{0, "0s"},
+		{59*time.Second + 999*time.Millisecond, "59s"},
+		{time.Minute, "1m"},
+		{59*time.Minute + 59*time.Second, "59m"},
+		{time.Hour, "1h"},
+		{23*time.Hour + 59*time.Minute, "23h"},
+		{24 * time.Hour, "1d"},
+		{400 * 24 * time.Hour, "400d"},
+		{-90 * time.Second, "1m"},
+	}
+
+	for _, test := range tests {
+		if human := humanizeDuration(test.d); human != test.human {
+			t.Errorf("%s: got %s, want %s", test.d, human, test.human)
+		}
+	}
+}
+
+func TestHumanizeTime(t *testing.T) {
+	defer func(absolute bool) { absoluteTimes = absolute }(absoluteTimes)
+	absoluteTimes = false
+
+	// The offsets keep clear of the boundaries, as the time goes on while the test runs
+	tests := []struct {
+		offset time.Duration
+		human  string
+	}{
+		{-59*time.Second - 500*time.Millisecond, "59s ago"},
+		{-60*time.Second - 500*time.Millisecond, "1m ago"},
+		{-3 * time.Hour, "3h ago"},
+		{-500 * time.Millisecond, "just now"},
+		{30 * time.Second, "just now"},
+		{2*time.Minute + 30*time.Second, "in 2m"},
+		{50 * time.Hour, "in 2d"},
+	}
+
+	for _, test := range tests {
+		at := time.Now().Add(test.offset)
+		if human := humanizeTime(&at); human != test.human {
+			t.Errorf("%s: got %s, want %s", test.offset, human, test.human)
+		}
+	}
+
+	if human := humanizeTime(nil); human != "-" {
+		t.Errorf("nil: got %s, want -", human)
+	}
+	if human := humanizeTime(&time.Time{}); human != "-" {
+		t.Errorf("zero: got %s, want -", human)
+	}
+}
+
+func TestHumanizeTimeAbsolute(t *testing.T) {
+	defer func(absolute bool) { absoluteTimes = absolute }(absoluteTimes)
+	absoluteTimes = true
+
+	at := time.Date(2026, 10, 15, 12, 30, 0, 0, time.UTC)
+	if human := humanizeTime(&at); human != "2026-10-15T12:30:00Z" {
+		t.Errorf("got %s, want the RFC3339 timestamp", human)
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// maxInputSize limits what is read from files, the standard input and remote locations
+const maxInputSize = 10 << 20
+
+const (
+	inputJSON = "json"
+	inputYAML = "yaml"
+)
+
+// inputFormat chooses between JSON and YAML by the content type, the extension, and at last by sniffing the content
+func inputFormat(location, contentType string, content []byte) string {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return inputJSON
+	case strings.Contains(contentType, "yaml"):
+		return inputYAML
+	}
+
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".json":
+		return inputJSON
+	case ".yaml", ".yml":
+		return inputYAML
+	}
+
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return inputJSON
+	}
+	return inputYAML
+}
+
+func readLimited(location string, r io.Reader) (content []byte, err error) {
+	content, err = io.ReadAll(io.LimitReader(r, maxInputSize+1))
+	if err == nil && len(content) > maxInputSize {
+		err = fmt.Errorf("%s is larger than the limit of %d MiB", location, maxInputSize>>20)
+	}
+	return
+}
+
+func readS3Input(location string) (content []byte, contentType string, err error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return
+	}
+
+	result, err := s3I.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+	})
+	if err != nil {
+		switch awsErrorCode(err) {
+		case "AccessDenied", "Forbidden":
+			err = fmt.Errorf("Access denied reading %s, s3:GetObject is needed on it", location)
+		case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket, "NotFound":
+			err = fmt.Errorf("%s not found", location)
+		}
+		return
+	}
+	defer result.Body.Close()
+
+	if aws.Int64Value(result.ContentLength) > maxInputSize {
+		err = fmt.Errorf("%s is larger than the limit of %d MiB", location, maxInputSize>>20)
+		return
+	}
+
+	content, err = readLimited(location, result.Body)
+	contentType = aws.StringValue(result.ContentType)
+	return
+}
+
+func readHTTPSInput(location string) (content []byte, contentType string, err error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	response, err := client.Get(location)
+	if err != nil {
+		if strings.Contains(err.Error(), "x509") || strings.Contains(err.Error(), "tls") {
+			err = fmt.Errorf("TLS error fetching %s: %s", location, err.Error())
+		}
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Fetching %s: %s", location, response.Status)
+		return
+	}
+
+	if response.ContentLength > maxInputSize {
+		err = fmt.Errorf("%s is larger than the limit of %d MiB", location, maxInputSize>>20)
+		return
+	}
+
+	content, err = readLimited(location, response.Body)
+	contentType = response.Header.Get("Content-Type")
+	return
+}
+
+// readInput reads a local file, the standard input (-), an S3 object (s3://bucket/key) or an https:// URL
+func readInput(location string) (content []byte, format string, err error) {
+	var contentType string
+
+	switch {
+	case location == "-":
+		content, err = readLimited("standard input", os.Stdin)
+	case strings.HasPrefix(location, "s3://"):
+		content, contentType, err = readS3Input(location)
+	case strings.HasPrefix(location, "https://"):
+		content, contentType, err = readHTTPSInput(location)
+	case strings.HasPrefix(location, "http://"):
+		err = errors.New("Only https:// URLs are accepted, " + location + " is not encrypted")
+	default:
+		var file *os.File
+		file, err = os.Open(location)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		content, err = readLimited(location, file)
+	}
+
+	if err != nil {
+		return
+	}
+
+	format = inputFormat(location, contentType, content)
+	return
+}
+
+// yamlToJSONValue converts the maps decoded from YAML, keyed by interface{}, to maps JSON can encode
+func yamlToJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = yamlToJSONValue(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlToJSONValue(item)
+		}
+	}
+	return value
+}
+
+// decodeInput reads the location and decodes it, as JSON or YAML, into v.
+// YAML is converted to JSON first, so v is decoded the same way for both formats.
+func decodeInput(location string, v interface{}) (err error) {
+	content, format, err := readInput(location)
+	if err != nil {
+		return
+	}
+
+	return decodeInputContent(location, content, format, v)
+}
+
+// decodeInputContent decodes what was read by readInput, for when it has to be decoded more than once
+func decodeInputContent(location string, content []byte, format string, v interface{}) (err error) {
+	if format == inputYAML {
+		var decoded interface{}
+		if err = yaml.Unmarshal(content, &decoded); err != nil {
+			return fmt.Errorf("Unable to parse %s as YAML: %s", location, err.Error())
+		}
+
+		if content, err = json.Marshal(yamlToJSONValue(decoded)); err != nil {
+			return
+		}
+	}
+
+	if err = json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("Unable to parse %s: %s", location, err.Error())
+	}
+	return
+}
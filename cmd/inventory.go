@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
+	"github.com/spf13/cobra"
+)
+
+type inventoryOptions struct {
+	allRegions  bool
+	concurrency int
+	cache       string
+	cacheMaxAge time.Duration
+}
+
+var inventoryOpts inventoryOptions
+
+// inventoryRow is a service, or a region or cluster that could not be read when Error is set
+type inventoryRow struct {
+	Region     string   `json:"region"`
+	Cluster    string   `json:"cluster,omitempty"`
+	Service    string   `json:"service,omitempty"`
+	LaunchType string   `json:"launchType,omitempty"`
+	Desired    int64    `json:"desired"`
+	Running    int64    `json:"running"`
+	VCPU       float64  `json:"vcpu"`
+	MemoryMiB  int64    `json:"memoryMiB"`
+	Images     []string `json:"images,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// inventoryError names the permission gaps plainly, they are expected on large accounts
+func inventoryError(err error) string {
+	code := awsErrorCode(err)
+	if strings.HasPrefix(code, "AccessDenied") || code == "UnrecognizedClientException" || code == "UnauthorizedOperation" {
+		return "access denied"
+	}
+	return err.Error()
+}
+
+// serviceLaunchType tells FARGATE apart from EC2, also when the service uses a capacity provider strategy
+func serviceLaunchType(s *ecs.Service) string {
+	if launchType := aws.StringValue(s.LaunchType); launchType != "" {
+		return launchType
+	}
+
+	for _, item := range s.CapacityProviderStrategy {
+		if strings.HasPrefix(aws.StringValue(item.CapacityProvider), "FARGATE") {
+			return ecs.LaunchTypeFargate
+		}
+	}
+	return ecs.LaunchTypeEc2
+}
+
+// taskReservation is the vCPU and memory reserved by a task of the Task Definition,
+// summing the containers when it has no task level values
+func taskReservation(td *ecs.TaskDefinition) (vcpu float64, memory int64) {
+	cpu, _ := strconv.ParseFloat(aws.StringValue(td.Cpu), 64)
+	memory, _ = strconv.ParseInt(aws.StringValue(td.Memory), 10, 64)
+
+	for _, cd := range td.ContainerDefinitions {
+		if td.Cpu == nil {
+			cpu += float64(aws.Int64Value(cd.Cpu))
+		}
+
+		if td.Memory == nil {
+			if cd.Memory != nil {
+				memory += aws.Int64Value(cd.Memory)
+			} else {
+				memory += aws.Int64Value(cd.MemoryReservation)
+			}
+		}
+	}
+
+	return cpu / 1024, memory
+}
+
+// inventoryScanCluster reads the services of a cluster with the client of its region
+func inventoryScanCluster(client *ecsx.Client, api *ecs.ECS, region, cluster string) (rows []inventoryRow, err error) {
+	clusterName := cluster[strings.LastIndex(cluster, "/")+1:]
+
+	serviceArns, err := client.ListAllServices(cluster)
+	if err != nil {
+		return
+	}
+
+	services, err := client.DescribeAllServices(cluster, serviceArns)
+	if err != nil {
+		return
+	}
+
+	tds := make(map[string]*ecs.TaskDefinition)
+	for _, s := range services {
+		arn := aws.StringValue(s.TaskDefinition)
+
+		td, ok := tds[arn]
+		if !ok {
+			result, err := api.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{TaskDefinition: s.TaskDefinition})
+			if err != nil {
+				return nil, err
+			}
+			td = result.TaskDefinition
+			tds[arn] = td
+		}
+
+		vcpu, memory := taskReservation(td)
+		running := aws.Int64Value(s.RunningCount)
+
+		row := inventoryRow{
+			Region:     region,
+			Cluster:    clusterName,
+			Service:    aws.StringValue(s.ServiceName),
+			LaunchType: serviceLaunchType(s),
+			Desired:    aws.Int64Value(s.DesiredCount),
+			Running:    running,
+			VCPU:       vcpu * float64(running),
+			MemoryMiB:  memory * running,
+		}
+
+		for _, cd := range td.ContainerDefinitions {
+			row.Images = append(row.Images, aws.StringValue(cd.Image))
+		}
+
+		rows = append(rows, row)
+	}
+	return
+}
+
+// inventoryScan reads every cluster of the regions, at most concurrency clusters at the same time.
+// Regions and clusters that can not be read become rows with the error instead of failing the scan.
+func inventoryScan(regions []string, concurrency int) (rows []inventoryRow) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	add := func(found ...inventoryRow) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		rows = append(rows, found...)
+	}
+
+	for _, region := range regions {
+		api := ecs.New(awsSession, aws.NewConfig().WithRegion(region))
+		client := ecsx.New(api)
+
+		clusterArns, err := client.ListAllClusters()
+		if err != nil {
+			add(inventoryRow{Region: region, Error: inventoryError(err)})
+			continue
+		}
+
+		for _, clusterArn := range clusterArns {
+			wg.Add(1)
+			semaphore <- struct{}{}
+
+			go func(region, cluster string) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				found, err := inventoryScanCluster(client, api, region, cluster)
+				if err != nil {
+					add(inventoryRow{Region: region, Cluster: cluster[strings.LastIndex(cluster, "/")+1:], Error: inventoryError(err)})
+					return
+				}
+
+				add(found...)
+				debugf("%s %s: %d services", region, cluster, len(found))
+			}(region, aws.StringValue(clusterArn))
+		}
+	}
+
+	wg.Wait()
+
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		return a.Region+"/"+a.Cluster+"/"+a.Service < b.Region+"/"+b.Cluster+"/"+b.Service
+	})
+	return
+}
+
+func inventoryRegions(all bool) (regions []string, err error) {
+	if !all {
+		return []string{aws.StringValue(awsSession.Config.Region)}, nil
+	}
+
+	result, err := ec2I.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		return
+	}
+
+	for _, r := range result.Regions {
+		regions = append(regions, aws.StringValue(r.RegionName))
+	}
+	sort.Strings(regions)
+	return
+}
+
+// readInventoryCache returns the rows of a scan saved within the max age, none otherwise
+func readInventoryCache(file string, maxAge time.Duration) (rows []inventoryRow, ok bool) {
+	info, err := os.Stat(file)
+	if err != nil || time.Since(info.ModTime()) > maxAge {
+		return
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	if err := json.Unmarshal(content, &rows); err != nil {
+		return nil, false
+	}
+
+	fmt.Fprintf(os.Stderr, "using the scan cached on %s at %s\n", file, info.ModTime().Format(time.RFC3339))
+	return rows, true
+}
+
+type inventorySummary struct {
+	region   string
+	clusters map[string]bool
+	services int
+	running  int64
+	fargate  int64
+	ec2      int64
+	vcpu     float64
+	memory   int64
+	images   map[string]bool
+	errors   int
+}
+
+func summarizeInventory(rows []inventoryRow) (summaries []*inventorySummary, total *inventorySummary) {
+	newSummary := func(region string) *inventorySummary {
+		return &inventorySummary{region: region, clusters: make(map[string]bool), images: make(map[string]bool)}
+	}
+
+	total = newSummary("TOTAL")
+	byRegion := make(map[string]*inventorySummary)
+
+	for _, row := range rows {
+		s, ok := byRegion[row.Region]
+		if !ok {
+			s = newSummary(row.Region)
+			byRegion[row.Region] = s
+			summaries = append(summaries, s)
+		}
+
+		for _, summary := range []*inventorySummary{s, total} {
+			if row.Error != "" {
+				summary.errors++
+				continue
+			}
+
+			summary.clusters[row.Region+"/"+row.Cluster] = true
+			summary.services++
+			summary.running += row.Running
+			summary.vcpu += row.VCPU
+			summary.memory += row.MemoryMiB
+
+			if row.LaunchType == ecs.LaunchTypeFargate {
+				summary.fargate += row.Running
+			} else {
+				summary.ec2 += row.Running
+			}
+
+			for _, image := range row.Images {
+				summary.images[image] = true
+			}
+		}
+	}
+	return
+}
+
+func printInventorySummary(rows []inventoryRow) {
+	summaries, total := summarizeInventory(rows)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REGION\tCLUSTERS\tSERVICES\tRUNNING TASKS\tFARGATE\tEC2\tVCPU\tMEMORY (GiB)\tIMAGES\tNOT READ")
+	for _, s := range append(summaries, total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.2f\t%.1f\t%d\t%d\n",
+			s.region, len(s.clusters), s.services, s.running, s.fargate, s.ec2, s.vcpu, float64(s.memory)/1024, len(s.images), s.errors)
+	}
+	w.Flush()
+
+	for _, row := range rows {
+		if row.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s %s: %s\n", row.Region, row.Cluster, row.Error)
+		}
+	}
+}
+
+func inventoryRun(cmd *cobra.Command, args []string) {
+	opts := &inventoryOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json", "csv"))
+
+	rows, cached := readInventoryCache(opts.cache, opts.cacheMaxAge)
+	if !cached {
+		regions, err := inventoryRegions(opts.allRegions)
+		must(err)
+
+		rows = inventoryScan(regions, opts.concurrency)
+
+		if opts.cache != "" {
+			content, err := json.Marshal(rows)
+			must(err)
+			must(os.WriteFile(opts.cache, content, 0644))
+		}
+	}
+
+	switch outputFormat {
+	case "json":
+		if rows == nil {
+			rows = []inventoryRow{}
+		}
+
+		must(printVersionedJSON(rows))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"region", "cluster", "service", "launch_type", "desired", "running", "vcpu", "memory_mib", "images", "error"})
+		for _, row := range rows {
+			w.Write([]string{
+				row.Region, row.Cluster, row.Service, row.LaunchType,
+				strconv.FormatInt(row.Desired, 10), strconv.FormatInt(row.Running, 10),
+				strconv.FormatFloat(row.VCPU, 'f', 2, 64), strconv.FormatInt(row.MemoryMiB, 10),
+				strings.Join(row.Images, " "), row.Error,
+			})
+		}
+		w.Flush()
+		must(w.Error())
+	case "text", "table":
+		if quiet {
+			for _, row := range rows {
+				if row.Error == "" {
+					printID(row.Region + "/" + row.Cluster + "/" + row.Service)
+				}
+			}
+			break
+		}
+
+		printInventorySummary(rows)
+	}
+}
+
+var inventoryCmd = &cobra.Command{
+	Use:         "inventory",
+	Short:       "Report the ECS footprint of the account: clusters, services, tasks, reserved vCPU and memory, and images",
+	Args:        cobra.NoArgs,
+	Run:         inventoryRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+
+	registerOutputSchema(inventoryCmd, "inventory", []inventoryRow{})
+
+	flags := inventoryCmd.Flags()
+
+	flags.BoolVar(&inventoryOpts.allRegions, "all-regions", false, allRegionsSpec)
+	flags.IntVar(&inventoryOpts.concurrency, "concurrency", 5, concurrencySpec)
+	flags.StringVar(&inventoryOpts.cache, "cache", "", inventoryCacheSpec)
+	flags.DurationVar(&inventoryOpts.cacheMaxAge, "cache-max-age", time.Hour, inventoryCacheMaxAgeSpec)
+}
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// awsErrorCode returns the code of an AWS API error, empty for other errors
+func awsErrorCode(err error) string {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return ""
+}
+
+// isThrottledOrUnavailable tells if the request may succeed by only retrying it later
+func isThrottledOrUnavailable(err error) bool {
+	if rerr, ok := err.(awserr.RequestFailure); ok && rerr.StatusCode() >= 500 {
+		return true
+	}
+
+	switch awsErrorCode(err) {
+	case "ThrottlingException", "Throttling", "RequestLimitExceeded", "TooManyRequestsException",
+		cloudwatchlogs.ErrCodeServiceUnavailableException:
+		return true
+	}
+	return false
+}
+
+// nextBackoff doubles the wait between retries, from 2 seconds up to a minute
+func nextBackoff(current time.Duration) time.Duration {
+	if current == 0 {
+		return 2 * time.Second
+	}
+
+	if current*2 > time.Minute {
+		return time.Minute
+	}
+	return current * 2
+}
+
+// jitter spreads the wait over its second half, so clients backing off together do not retry together
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+type logStream struct {
+	group     string
+	prefix    string
+	name      string
+	container string
+	taskID    string
+}
+
+// logLocation overrides the awslogs-group and awslogs-stream-prefix read from the Task Definition,
+// for templates whose options are resolved after the registration and do not match the registered values
+type logLocation struct {
+	group        string
+	streamPrefix string
+}
+
+func addLogLocationFlags(flags *pflag.FlagSet, l *logLocation) {
+	flags.StringVar(&l.group, "log-group", "", logGroupSpec)
+	flags.StringVar(&l.streamPrefix, "log-stream-prefix", "", logStreamPrefixSpec)
+}
+
+// resolve applies the overrides to the streams, naming them again as the awslogs driver does
+func (l logLocation) resolve(streams []logStream) []logStream {
+	for i := range streams {
+		if l.group != "" {
+			streams[i].group = l.group
+		}
+
+		if l.streamPrefix != "" {
+			streams[i].prefix = l.streamPrefix
+			streams[i].name = l.streamPrefix + "/" + streams[i].container + "/" + streams[i].taskID
+		}
+
+		debugf("log group %s, stream %s", streams[i].group, streams[i].name)
+	}
+	return streams
+}
+
+// taskID returns the ID of a task from its ARN (or the ID itself)
+func taskID(taskArn string) string {
+	return taskArn[strings.LastIndex(taskArn, "/")+1:]
+}
+
+// taskLogStreams computes the stream of every container of the task using the awslogs driver
+func taskLogStreams(td *ecs.TaskDefinition, id string) (streams []logStream) {
+	for _, cd := range td.ContainerDefinitions {
+		if cd.LogConfiguration == nil || aws.StringValue(cd.LogConfiguration.LogDriver) != "awslogs" {
+			continue
+		}
+
+		logPrefix := aws.StringValue(cd.LogConfiguration.Options["awslogs-stream-prefix"])
+
+		streams = append(streams, logStream{
+			group:     aws.StringValue(cd.LogConfiguration.Options["awslogs-group"]),
+			prefix:    logPrefix,
+			name:      logPrefix + "/" + aws.StringValue(cd.Name) + "/" + id,
+			container: aws.StringValue(cd.Name),
+			taskID:    id,
+		})
+	}
+	return
+}
+
+// containerFilter restricts the containers whose logs are read, every awslogs container when empty
+type containerFilter struct {
+	include []string
+	exclude []string
+}
+
+func (f containerFilter) allows(container string) bool {
+	for _, name := range f.exclude {
+		if name == container {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, name := range f.include {
+		if name == container {
+			return true
+		}
+	}
+	return false
+}
+
+func (f containerFilter) apply(streams []logStream) (filtered []logStream) {
+	for _, stream := range streams {
+		if f.allows(stream.container) {
+			filtered = append(filtered, stream)
+		}
+	}
+	return
+}
+
+// validate checks that every informed container exists on at least one of the Task Definitions
+func (f containerFilter) validate(tds []*ecs.TaskDefinition) error {
+	valid := make(map[string]bool)
+	var names []string
+	for _, td := range tds {
+		for _, cd := range td.ContainerDefinitions {
+			if name := aws.StringValue(cd.Name); !valid[name] {
+				valid[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, name := range append(f.include, f.exclude...) {
+		if !valid[name] {
+			return fmt.Errorf("Container %s not found, valid containers are: %s", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// parseSince accepts a duration relative to now (E.g. 10m, 3h, 7d) or a RFC3339 timestamp
+func parseSince(since string) (t time.Time, err error) {
+	if days, err := strconv.Atoi(strings.TrimSuffix(since, "d")); err == nil && strings.HasSuffix(since, "d") {
+		return time.Now().AddDate(0, 0, -days), nil
+	}
+
+	d, err := time.ParseDuration(since)
+	if err == nil {
+		t = time.Now().Add(-d)
+		return
+	}
+
+	t, err = time.Parse(time.RFC3339, since)
+	return
+}
+
+// fetchLogEvents gets, without following, the events of the streams sorted by their timestamp
+func fetchLogEvents(streams []logStream, startTime time.Time, filterPattern string) (events []*cloudwatchlogs.FilteredLogEvent, err error) {
+	byGroup := make(map[string][]*string)
+	for _, stream := range streams {
+		byGroup[stream.group] = append(byGroup[stream.group], aws.String(stream.name))
+	}
+
+	for group, names := range byGroup {
+		// FilterLogEvents accepts up to 100 stream names per request
+		for start := 0; start < len(names); start += 100 {
+			end := start + 100
+			if end > len(names) {
+				end = len(names)
+			}
+
+			input := &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName:   aws.String(group),
+				LogStreamNames: names[start:end],
+			}
+
+			if !startTime.IsZero() {
+				input.StartTime = aws.Int64(aws.TimeUnixMilli(startTime))
+			}
+
+			if filterPattern != "" {
+				input.FilterPattern = aws.String(filterPattern)
+			}
+
+			err = cwlI.FilterLogEventsPages(input, func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+				events = append(events, page.Events...)
+				return !lastPage
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.Int64Value(events[i].Timestamp) < aws.Int64Value(events[j].Timestamp)
+	})
+
+	return
+}
+
+type logsOptions struct {
+	cluster       string
+	service       string
+	task          string
+	follow        bool
+	since         string
+	filterPattern string
+	output        outputConfiguration
+}
+
+var logsOpts logsOptions
+
+// logsTasks describes the informed task, or the running tasks of the informed service
+func logsTasks(cluster, service, task string) (tasks []*ecs.Task, err error) {
+	if service != "" {
+		return serviceTasks(cluster, service, ecs.DesiredStatusRunning)
+	}
+
+	tasks, err = describeTasks(cluster, []*string{aws.String(task)})
+	if err == nil && len(tasks) == 0 {
+		err = errors.New("Task informed not found")
+	}
+	return
+}
+
+// tasksLogStreams computes the awslogs streams of every container of the tasks
+func tasksLogStreams(tasks []*ecs.Task) (streams []logStream, err error) {
+	for _, t := range tasks {
+		td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, taskLogStreams(td, taskID(aws.StringValue(t.TaskArn)))...)
+	}
+	return
+}
+
+// followLogs prints the events of the streams interleaved by timestamp, polling until interrupted when following.
+// The tasks are looked up again every 30s, so the ones started by deployments and scaling are followed too,
+// and following a single task ends once it is stopped.
+func followLogs(cluster string, opts *logsOptions, startTime time.Time) {
+	ctx := interruptContext()
+	seen := &seenEvents{}
+
+	var streams []logStream
+	var refreshedAt, retryAt time.Time
+	var backoff time.Duration
+	stopped := false
+	for {
+		if time.Since(refreshedAt) >= 30*time.Second {
+			tasks, err := logsTasks(cluster, opts.service, opts.task)
+			must(err)
+
+			if len(tasks) == 0 && !opts.follow {
+				must(errors.New(opts.service + " has no running task"))
+			}
+
+			streams, err = tasksLogStreams(tasks)
+			must(err)
+			refreshedAt = time.Now()
+
+			if len(streams) == 0 && !opts.follow {
+				must(errors.New("No container of the tasks logs with the awslogs driver"))
+			}
+
+			stopped = opts.task != "" && aws.StringValue(tasks[0].LastStatus) == ecs.DesiredStatusStopped
+		}
+
+		if len(streams) > 0 && time.Now().After(retryAt) {
+			if seen.lastSeenTime != nil {
+				startTime = aws.MillisecondsTimeValue(seen.lastSeenTime)
+			}
+
+			events, err := fetchLogEvents(streams, startTime, opts.filterPattern)
+
+			switch {
+			case err == nil:
+				backoff = 0
+			case isThrottledOrUnavailable(err) && opts.follow:
+				backoff = nextBackoff(backoff)
+				retryAt = time.Now().Add(backoff)
+				fmt.Fprintf(os.Stderr, "CloudWatch throttled, retrying in %s...\n", backoff)
+			default:
+				must(err)
+			}
+
+			for _, event := range events {
+				if seen.add(event) {
+					printEvent(&opts.output, event)
+				}
+			}
+		}
+
+		if !opts.follow || stopped || !sleepContext(ctx, 2*time.Second) {
+			return
+		}
+	}
+}
+
+func logsRun(cmd *cobra.Command, args []string) {
+	opts := &logsOpts
+
+	if opts.service == "" && opts.task == "" {
+		cmd.Help()
+		return
+	}
+
+	if opts.service != "" && opts.task != "" {
+		must(errors.New("Inform either --service or --task"))
+	}
+
+	if opts.cluster == "" {
+		must(errors.New("--cluster is required"))
+	}
+
+	var startTime time.Time
+	if opts.since != "" {
+		var err error
+		startTime, err = parseSince(opts.since)
+		must(err)
+	}
+
+	followLogs(opts.cluster, opts, startTime)
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [command]",
+	Short: "Show the CloudWatch logs of a service or a task",
+	Args:  cobra.NoArgs,
+	Run:   logsRun,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	flags := logsCmd.Flags()
+
+	flags.StringVarP(&logsOpts.cluster, "cluster", "c", "", clusterSpec)
+	flags.StringVarP(&logsOpts.service, "service", "s", "", logsServiceSpec)
+	flags.StringVarP(&logsOpts.task, "task", "t", "", logsTaskSpec)
+	flags.BoolVarP(&logsOpts.follow, "follow", "f", false, followSpec)
+	flags.StringVar(&logsOpts.since, "since", "", sinceSpec)
+	flags.StringVar(&logsOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	addLogOutputFlags(flags, &logsOpts.output)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+// logPattern is a --grep flag, its regexp is compiled, and rejected, as the flags are parsed
+type logPattern struct {
+	expression string
+	pattern    *regexp.Regexp
+}
+
+func (p *logPattern) Set(expression string) (err error) {
+	if p.pattern, err = regexp.Compile(expression); err != nil {
+		return fmt.Errorf("'%s' is not a valid regexp: %s", expression, err.Error())
+	}
+
+	p.expression = expression
+	return nil
+}
+
+func (p *logPattern) String() string {
+	return p.expression
+}
+
+func (p *logPattern) Type() string {
+	return "regexp"
+}
+
+// shows tells if the event is printed with --grep and --grep-v. They match the raw message, before
+// the JSON ones are formatted, as CloudWatch filter patterns can not match inside JSON string fields.
+func (c *outputConfiguration) shows(event *cloudwatchlogs.FilteredLogEvent) bool {
+	message := aws.StringValue(event.Message)
+
+	if c.Grep.pattern != nil && !c.Grep.pattern.MatchString(message) {
+		return false
+	}
+	return c.GrepV.pattern == nil || !c.GrepV.pattern.MatchString(message)
+}
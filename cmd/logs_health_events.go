@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/fatih/color"
+)
+
+// targetHealthWatch polls the health of the targets of a service, turning the state transitions into log events
+type targetHealthWatch struct {
+	cluster      string
+	service      string
+	targetGroups []*string
+	states       map[string]string
+	tasksByKey   map[string]string
+	started      bool
+}
+
+func newTargetHealthWatch(cluster string, s *ecs.Service) *targetHealthWatch {
+	w := &targetHealthWatch{
+		cluster:    cluster,
+		service:    aws.StringValue(s.ServiceName),
+		states:     make(map[string]string),
+		tasksByKey: make(map[string]string),
+	}
+
+	for _, lb := range s.LoadBalancers {
+		if lb.TargetGroupArn != nil {
+			w.targetGroups = append(w.targetGroups, lb.TargetGroupArn)
+		}
+	}
+	return w
+}
+
+// targetKeys relates the load balancer targets to the tasks, by the key of the target: the private IP with awsvpc,
+// the EC2 instance and host port with bridge
+func targetKeys(cluster string, tasks []*ecs.Task) (keys map[string]string, err error) {
+	var instanceArns []*string
+	for _, t := range tasks {
+		if t.ContainerInstanceArn != nil {
+			instanceArns = append(instanceArns, t.ContainerInstanceArn)
+		}
+	}
+
+	ec2IDs := make(map[string]string)
+	if len(instanceArns) > 0 {
+		instances, err := ecsxI.DescribeAllContainerInstances(cluster, instanceArns)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ci := range instances {
+			ec2IDs[aws.StringValue(ci.ContainerInstanceArn)] = aws.StringValue(ci.Ec2InstanceId)
+		}
+	}
+
+	keys = make(map[string]string)
+	for _, t := range tasks {
+		id := taskID(aws.StringValue(t.TaskArn))
+
+		for _, attachment := range t.Attachments {
+			for _, detail := range attachment.Details {
+				if aws.StringValue(detail.Name) == "privateIPv4Address" {
+					keys[aws.StringValue(detail.Value)] = id
+				}
+			}
+		}
+
+		for _, c := range t.Containers {
+			for _, binding := range c.NetworkBindings {
+				key := fmt.Sprintf("%s:%d", ec2IDs[aws.StringValue(t.ContainerInstanceArn)], aws.Int64Value(binding.HostPort))
+				keys[key] = id
+			}
+		}
+	}
+	return
+}
+
+// targetTask is the ID of the task of the target among the keys, empty when it is none of them
+func targetTask(keys map[string]string, target *elbv2.TargetDescription) string {
+	if id, ok := keys[fmt.Sprintf("%s:%d", aws.StringValue(target.Id), aws.Int64Value(target.Port))]; ok {
+		return id
+	}
+	return keys[aws.StringValue(target.Id)]
+}
+
+// mapTasks relates the targets to the running tasks of the service
+func (w *targetHealthWatch) mapTasks() error {
+	tasks, err := serviceTasks(w.cluster, w.service, ecs.DesiredStatusRunning)
+	if err != nil {
+		return err
+	}
+
+	keys, err := targetKeys(w.cluster, tasks)
+	if err != nil {
+		return err
+	}
+
+	for key, id := range keys {
+		w.tasksByKey[key] = id
+	}
+	return nil
+}
+
+// taskOf finds the task of the target, looking the tasks up again when the target is new
+func (w *targetHealthWatch) taskOf(target *elbv2.TargetDescription) string {
+	for attempt := 0; attempt < 2; attempt++ {
+		if id := targetTask(w.tasksByKey, target); id != "" {
+			return id
+		}
+
+		if attempt == 0 {
+			if err := w.mapTasks(); err != nil {
+				debugf("unable to map the targets to tasks: %s", err.Error())
+			}
+		}
+	}
+	return "unknown task"
+}
+
+// poll returns an event for every target whose state changed since the last poll.
+// The first poll only records the states, unless the target is not healthy.
+func (w *targetHealthWatch) poll() (events []*cloudwatchlogs.FilteredLogEvent, err error) {
+	current := make(map[string]bool)
+
+	for _, tg := range w.targetGroups {
+		result, err := elbv2I.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: tg})
+		if err != nil {
+			return nil, err
+		}
+
+		tgName := aws.StringValue(tg)
+		tgName = tgName[strings.Index(tgName, "targetgroup/")+len("targetgroup/"):]
+		tgName = tgName[:strings.Index(tgName, "/")]
+
+		for _, description := range result.TargetHealthDescriptions {
+			key := fmt.Sprintf("%s %s:%d", tgName, aws.StringValue(description.Target.Id), aws.Int64Value(description.Target.Port))
+			current[key] = true
+
+			state := aws.StringValue(description.TargetHealth.State)
+			previous, known := w.states[key]
+			w.states[key] = state
+
+			if previous == state || (!w.started && state == elbv2.TargetHealthStateEnumHealthy) {
+				continue
+			}
+
+			if !known {
+				previous = "new"
+			}
+
+			message := fmt.Sprintf("target %s (task %s) %s → %s", key, w.taskOf(description.Target), previous, state)
+			if reason := aws.StringValue(description.TargetHealth.Reason); reason != "" {
+				message += ": " + reason
+			}
+			if detail := aws.StringValue(description.TargetHealth.Description); detail != "" {
+				message += " (" + detail + ")"
+			}
+
+			events = append(events, healthEvent(tgName, state, message))
+		}
+	}
+
+	for key, state := range w.states {
+		if !current[key] {
+			delete(w.states, key)
+			events = append(events, healthEvent(strings.SplitN(key, " ", 2)[0], "deregistered", "target "+key+" "+state+" → deregistered"))
+		}
+	}
+
+	w.started = true
+	return
+}
+
+// healthEvent is a synthetic log event, colored by the state to stand out from the application logs
+func healthEvent(targetGroup, state, message string) *cloudwatchlogs.FilteredLogEvent {
+	c := color.New(color.FgYellow, color.Bold)
+	switch state {
+	case elbv2.TargetHealthStateEnumHealthy:
+		c = color.New(color.FgGreen, color.Bold)
+	case elbv2.TargetHealthStateEnumUnhealthy:
+		c = color.New(color.FgRed, color.Bold)
+	}
+
+	return &cloudwatchlogs.FilteredLogEvent{
+		Timestamp:     aws.Int64(aws.TimeUnixMilli(time.Now())),
+		LogStreamName: aws.String("health/" + targetGroup),
+		Message:       aws.String(c.Sprint("[health] " + message)),
+	}
+}
+
+// follow polls the target health until the context is canceled
+func (w *targetHealthWatch) follow(ctx context.Context, label string, events chan<- serviceLogEvent) {
+	var backoff time.Duration
+	for {
+		fetched, err := w.poll()
+
+		switch {
+		case err == nil:
+			backoff = 0
+		case isThrottledOrUnavailable(err):
+			backoff = nextBackoff(backoff)
+			fmt.Fprintf(os.Stderr, "%s: target health throttled, retrying in %s...\n", w.service, backoff)
+		default:
+			fmt.Fprintf(os.Stderr, "%s: %s\n", w.service, err.Error())
+		}
+
+		for _, event := range fetched {
+			events <- serviceLogEvent{label, event, time.Now()}
+		}
+
+		if !sleepContext(ctx, 5*time.Second+backoff) {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/spf13/cobra"
+)
+
+// logEventLine is a log event as printed with --jsonl
+type logEventLine struct {
+	Timestamp string      `json:"timestamp"`
+	Stream    string      `json:"stream"`
+	Container string      `json:"container"`
+	Message   interface{} `json:"message"`
+}
+
+// jsonlOutput tells if the command prints its log events as JSON Lines
+func jsonlOutput(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("jsonl")
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// streamContainer is the container of a stream named by the awslogs driver, as prefix/container/task-id
+func streamContainer(stream string) string {
+	parts := strings.Split(stream, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// formatEventJSONL is the event as a single line of JSON, its message parsed when it is a JSON object
+func formatEventJSONL(event *cloudwatchlogs.FilteredLogEvent) string {
+	line := logEventLine{
+		Timestamp: aws.MillisecondsTimeValue(event.Timestamp).Format(time.RFC3339Nano),
+		Stream:    aws.StringValue(event.LogStreamName),
+		Container: streamContainer(aws.StringValue(event.LogStreamName)),
+		Message:   aws.StringValue(event.Message),
+	}
+
+	parsed := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(aws.StringValue(event.Message)), &parsed); err == nil {
+		line.Message = parsed
+	}
+
+	encoded, _ := json.Marshal(line)
+	return string(encoded)
+}
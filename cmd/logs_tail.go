@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type logsTailOptions struct {
+	cluster       string
+	services      []string
+	follow        bool
+	since         string
+	filterPattern string
+	where         logFieldFilter
+	window        time.Duration
+	containers    containerFilter
+	healthEvents  bool
+	output        outputConfiguration
+}
+
+var logsTailOpts logsTailOptions
+
+var serviceLabelColors = []color.Attribute{color.FgCyan, color.FgMagenta, color.FgYellow, color.FgGreen, color.FgBlue}
+
+type serviceLogEvent struct {
+	label      string
+	event      *cloudwatchlogs.FilteredLogEvent
+	receivedAt time.Time
+}
+
+// serviceLogTail follows the logs of the running tasks of one service
+type serviceLogTail struct {
+	cluster       string
+	service       string
+	label         string
+	filterPattern string
+	where         *logFieldFilter
+	containers    containerFilter
+	streams       []logStream
+	tds           []*ecs.TaskDefinition
+	lastSeen      int64
+	seen          map[string]bool
+	refreshedAt   time.Time
+	retryAt       time.Time
+	backoff       time.Duration
+}
+
+// refreshStreams looks up the streams of the running tasks, new tasks are started by deployments and scaling
+func (t *serviceLogTail) refreshStreams() (err error) {
+	tasks, err := serviceTasks(t.cluster, t.service, ecs.DesiredStatusRunning)
+	if err != nil {
+		return
+	}
+
+	var streams []logStream
+	var tds []*ecs.TaskDefinition
+	for _, task := range tasks {
+		td, err := describeTaskDefinition(aws.StringValue(task.TaskDefinitionArn))
+		if err != nil {
+			return err
+		}
+
+		tds = append(tds, td)
+		streams = append(streams, taskLogStreams(td, taskID(aws.StringValue(task.TaskArn)))...)
+	}
+
+	t.streams = t.containers.apply(streams)
+	t.tds = tds
+	t.refreshedAt = time.Now()
+	return
+}
+
+// poll fetches the events not seen yet
+func (t *serviceLogTail) poll() (events []*cloudwatchlogs.FilteredLogEvent, err error) {
+	if len(t.streams) == 0 {
+		return
+	}
+
+	fetched, err := fetchLogEvents(t.streams, aws.MillisecondsTimeValue(&t.lastSeen), t.filterPattern)
+	if err != nil {
+		return
+	}
+
+	for _, event := range fetched {
+		if ts := aws.Int64Value(event.Timestamp); ts > t.lastSeen {
+			t.lastSeen = ts
+			t.seen = make(map[string]bool)
+		}
+
+		if t.seen[aws.StringValue(event.EventId)] {
+			continue
+		}
+
+		t.seen[aws.StringValue(event.EventId)] = true
+		events = append(events, event)
+	}
+
+	events = t.where.apply(events)
+	return
+}
+
+// follow polls the service until the context is canceled, backing off when throttled
+func (t *serviceLogTail) follow(ctx context.Context, events chan<- serviceLogEvent) {
+	for {
+		if time.Since(t.refreshedAt) >= 30*time.Second {
+			if err := t.refreshStreams(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", t.service, err.Error())
+			}
+		}
+
+		if time.Now().After(t.retryAt) {
+			fetched, err := t.poll()
+
+			switch {
+			case err == nil:
+				t.backoff = 0
+			case isThrottledOrUnavailable(err):
+				t.backoff = nextBackoff(t.backoff)
+				t.retryAt = time.Now().Add(t.backoff)
+				fmt.Fprintf(os.Stderr, "%s: CloudWatch throttled, retrying in %s...\n", t.service, t.backoff)
+			default:
+				fmt.Fprintf(os.Stderr, "%s: %s\n", t.service, err.Error())
+			}
+
+			for _, event := range fetched {
+				events <- serviceLogEvent{t.label, event, time.Now()}
+			}
+		}
+
+		if !sleepContext(ctx, 2*time.Second) {
+			return
+		}
+	}
+}
+
+// parseServiceFilters splits the --service values, optionally with their own filter pattern as service=pattern
+func parseServiceFilters(values []string, defaultPattern string) (services, patterns []string, err error) {
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if kv[0] == "" {
+			err = errors.New("Invalid --service '" + value + "'")
+			return
+		}
+
+		pattern := defaultPattern
+		if len(kv) == 2 {
+			pattern = kv[1]
+		}
+
+		services = append(services, kv[0])
+		patterns = append(patterns, pattern)
+	}
+	return
+}
+
+func printServiceLogEvents(output *outputConfiguration, buffered []serviceLogEvent) {
+	sort.SliceStable(buffered, func(i, j int) bool {
+		return aws.Int64Value(buffered[i].event.Timestamp) < aws.Int64Value(buffered[j].event.Timestamp)
+	})
+
+	for _, e := range buffered {
+		printLabeledEvent(output, e.label, e.event)
+	}
+}
+
+func logsTailRun(cmd *cobra.Command, args []string) {
+	opts := &logsTailOpts
+
+	services, patterns, err := parseServiceFilters(opts.services, opts.filterPattern)
+	must(err)
+
+	if len(services) == 0 {
+		must(errors.New("Inform at least one --service"))
+	}
+
+	if opts.healthEvents && !opts.follow {
+		must(errors.New("--health-events requires --follow"))
+	}
+
+	// Following starts from the last minute, otherwise everything is shown
+	var startTime time.Time
+	if opts.follow {
+		startTime = time.Now().Add(-time.Minute)
+	}
+
+	if opts.since != "" {
+		startTime, err = parseSince(opts.since)
+		must(err)
+	}
+
+	width := 0
+	for _, service := range services {
+		if len(service) > width {
+			width = len(service)
+		}
+	}
+
+	var lastSeen int64
+	if !startTime.IsZero() {
+		lastSeen = aws.TimeUnixMilli(startTime)
+	}
+
+	var tails []*serviceLogTail
+	var watches []*targetHealthWatch
+	for i, service := range services {
+		s, err := describeService(opts.cluster, service)
+		must(err)
+
+		if opts.healthEvents {
+			watch := newTargetHealthWatch(opts.cluster, s)
+			if len(watch.targetGroups) == 0 {
+				fmt.Fprintf(os.Stderr, "warning: %s has no target group, there are no health events to show\n", service)
+			}
+			watches = append(watches, watch)
+		}
+
+		label := color.New(serviceLabelColors[i%len(serviceLabelColors)]).Sprintf("%-*s", width, service)
+		tail := &serviceLogTail{
+			cluster:       opts.cluster,
+			service:       service,
+			label:         label,
+			filterPattern: patterns[i],
+			where:         &opts.where,
+			containers:    opts.containers,
+			lastSeen:      lastSeen,
+			seen:          make(map[string]bool),
+		}
+		must(tail.refreshStreams())
+
+		tails = append(tails, tail)
+	}
+
+	var tds []*ecs.TaskDefinition
+	for _, tail := range tails {
+		tds = append(tds, tail.tds...)
+	}
+	must(opts.containers.validate(tds))
+
+	if !opts.follow {
+		var buffered []serviceLogEvent
+		for _, tail := range tails {
+			fetched, err := tail.poll()
+			must(err)
+
+			for _, event := range fetched {
+				buffered = append(buffered, serviceLogEvent{tail.label, event, time.Now()})
+			}
+		}
+
+		printServiceLogEvents(&opts.output, buffered)
+		return
+	}
+
+	ctx := interruptContext()
+	events := make(chan serviceLogEvent, 100)
+	for _, tail := range tails {
+		go tail.follow(ctx, events)
+	}
+
+	for i, watch := range watches {
+		if len(watch.targetGroups) > 0 {
+			go watch.follow(ctx, tails[i].label, events)
+		}
+	}
+
+	// Producers' clocks and the polls of each service are not in sync, so the events
+	// are held for the window and sorted among the ones received meanwhile, instead of strictly
+	var mutex sync.Mutex
+	var buffered []serviceLogEvent
+	go func() {
+		for e := range events {
+			mutex.Lock()
+			buffered = append(buffered, e)
+			mutex.Unlock()
+		}
+	}()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().Add(-opts.window)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			// The events still held for the sort window are printed rather than lost
+			mutex.Lock()
+			printServiceLogEvents(&opts.output, buffered)
+			mutex.Unlock()
+			return
+		}
+
+		mutex.Lock()
+		var ready, waiting []serviceLogEvent
+		for _, e := range buffered {
+			if e.receivedAt.Before(cutoff) {
+				ready = append(ready, e)
+			} else {
+				waiting = append(waiting, e)
+			}
+		}
+		buffered = waiting
+		mutex.Unlock()
+
+		printServiceLogEvents(&opts.output, ready)
+	}
+}
+
+var logsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Tail the logs of multiple services merged in chronological order",
+	Args:  cobra.NoArgs,
+	Run:   logsTailRun,
+}
+
+func init() {
+	logsCmd.AddCommand(logsTailCmd)
+
+	flags := logsTailCmd.Flags()
+
+	flags.StringVarP(&logsTailOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringArrayVarP(&logsTailOpts.services, "service", "s", []string{}, requiredSpec+tailServiceSpec)
+	flags.BoolVarP(&logsTailOpts.follow, "follow", "f", false, followSpec)
+	flags.StringVar(&logsTailOpts.since, "since", "", sinceSpec)
+	flags.StringVar(&logsTailOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	addLogFieldFilterFlags(flags, &logsTailOpts.where)
+	flags.StringArrayVar(&logsTailOpts.containers.include, "container", []string{}, logsContainerSpec)
+	flags.StringArrayVar(&logsTailOpts.containers.exclude, "exclude-container", []string{}, logsExcludeContainerSpec)
+	flags.DurationVar(&logsTailOpts.window, "sort-window", 3*time.Second, sortWindowSpec)
+	flags.BoolVar(&logsTailOpts.healthEvents, "health-events", false, healthEventsSpec)
+	addLogOutputFlags(flags, &logsTailOpts.output)
+
+	logsTailCmd.MarkFlagRequired("cluster")
+	logsTailCmd.MarkFlagRequired("service")
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/spf13/pflag"
+)
+
+var whereExpressionPattern = regexp.MustCompile(`^([A-Za-z0-9_.@$-]+)(!=|=|>|<|~)(.*)$`)
+
+const whereExample = `e.g. --where level=error, --where status!=200, --where duration>500, --where path~^/api/`
+
+// whereCondition compares a field of JSON log messages, nested fields are separated by dots
+type whereCondition struct {
+	path    []string
+	op      string
+	value   string
+	number  float64
+	pattern *regexp.Regexp
+}
+
+// parseWhereCondition parses FIELD OP VALUE, checking numeric comparisons have a number and ~ a valid regexp
+func parseWhereCondition(expression string) (c whereCondition, err error) {
+	match := whereExpressionPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return c, fmt.Errorf("'%s' is not FIELD=VALUE, FIELD!=VALUE, FIELD>NUMBER, FIELD<NUMBER or FIELD~REGEXP, %s", expression, whereExample)
+	}
+
+	c = whereCondition{path: strings.Split(match[1], "."), op: match[2], value: match[3]}
+
+	switch c.op {
+	case ">", "<":
+		if c.number, err = strconv.ParseFloat(c.value, 64); err != nil {
+			return c, fmt.Errorf("'%s' compares with %s, which is not a number, %s", expression, c.op, whereExample)
+		}
+	case "~":
+		if c.pattern, err = regexp.Compile(c.value); err != nil {
+			return c, fmt.Errorf("'%s' has an invalid regexp: %s", expression, err.Error())
+		}
+	}
+	return c, nil
+}
+
+// fieldValue looks up the field of the message, found false when it is missing
+func (c whereCondition) fieldValue(message map[string]interface{}) (value interface{}, found bool) {
+	var current interface{} = message
+	for _, key := range c.path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func whereString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "null"
+	}
+
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// matches tells if the message satisfies the condition. A missing field only satisfies !=.
+func (c whereCondition) matches(message map[string]interface{}) bool {
+	value, found := c.fieldValue(message)
+	if !found {
+		return c.op == "!="
+	}
+
+	switch c.op {
+	case "=":
+		return whereString(value) == c.value
+	case "!=":
+		return whereString(value) != c.value
+	case "~":
+		return c.pattern.MatchString(whereString(value))
+	}
+
+	number, err := strconv.ParseFloat(whereString(value), 64)
+	if err != nil {
+		return false
+	}
+
+	if c.op == ">" {
+		return number > c.number
+	}
+	return number < c.number
+}
+
+// logFieldFilter is the --where flag, its conditions are parsed, and rejected, as the flags are
+type logFieldFilter struct {
+	expressions  []string
+	conditions   []whereCondition
+	keepUnparsed bool
+}
+
+func (f *logFieldFilter) Set(expression string) error {
+	c, err := parseWhereCondition(expression)
+	if err != nil {
+		return err
+	}
+
+	f.expressions = append(f.expressions, expression)
+	f.conditions = append(f.conditions, c)
+	return nil
+}
+
+func (f *logFieldFilter) String() string {
+	return "[" + strings.Join(f.expressions, ",") + "]"
+}
+
+func (f *logFieldFilter) Type() string {
+	return "stringArray"
+}
+
+// addLogFieldFilterFlags registers --where and --keep-unparsed
+func addLogFieldFilterFlags(flags *pflag.FlagSet, f *logFieldFilter) {
+	flags.Var(f, "where", whereSpec)
+	flags.BoolVar(&f.keepUnparsed, "keep-unparsed", false, keepUnparsedSpec)
+}
+
+// allows tells if the event satisfies every condition. Messages that are not JSON objects
+// can not be compared, they are only kept with --keep-unparsed.
+func (f *logFieldFilter) allows(event *cloudwatchlogs.FilteredLogEvent) bool {
+	if len(f.conditions) == 0 {
+		return true
+	}
+
+	message := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(aws.StringValue(event.Message)), &message); err != nil {
+		return f.keepUnparsed
+	}
+
+	for _, c := range f.conditions {
+		if !c.matches(message) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply keeps only the events allowed, after the filter pattern was applied by CloudWatch
+func (f *logFieldFilter) apply(events []*cloudwatchlogs.FilteredLogEvent) (filtered []*cloudwatchlogs.FilteredLogEvent) {
+	for _, event := range events {
+		if f.allows(event) {
+			filtered = append(filtered, event)
+		}
+	}
+	return
+}
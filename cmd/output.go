@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TylerBrock/colorjson"
+	"github.com/fatih/color"
+)
+
+// outputFormat is the persistent --output, checked by each command against the formats it supports.
+// The commands printing a table print it for both text and table.
+var outputFormat string
+var outputFormatSpec = `Output format of the commands supporting it
+Valid values:
+'text' (default)
+'table'
+'json'
+'csv' (inventory and task-definitions images only)`
+
+var noColor bool
+var noColorSpec = `Do not colorize the output`
+
+var expandJSON bool
+var expandJSONSpec = `Indent the JSON outputs`
+
+// checkOutputFormat fails when the format is not one supported by the command
+func checkOutputFormat(format string, valid ...string) error {
+	for _, v := range valid {
+		if format == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid output format %s, valid formats are: %s", format, strings.Join(valid, ", "))
+}
+
+// printJSON prints the value, with its schemaVersion, with the colors of the colorjson formatter honoring --no-color and --expand
+func printJSON(v interface{}) (err error) {
+	versioned, err := versionedOutput(v)
+	if err != nil {
+		return
+	}
+
+	content, err := json.Marshal(versioned)
+	if err != nil {
+		return
+	}
+
+	output := outputConfiguration{}
+	formatted, err := colorizeJSON(content, output.Formatter())
+	if err != nil {
+		return
+	}
+
+	fmt.Println(formatted)
+	return
+}
+
+// colorizeJSON formats the document token by token, as colorjson only walks generic values:
+// through them the fields would be sorted and the numbers beyond float64 precision rounded
+func colorizeJSON(content []byte, f *colorjson.Formatter) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	paint := func(c *color.Color, s string) string {
+		if c == nil {
+			return s
+		}
+		return c.Sprint(s)
+	}
+
+	// members counts the keys and values written on each open object or array
+	var b strings.Builder
+	var objects []bool
+	var members []int
+	newline := func() {
+		if f.Indent > 0 {
+			b.WriteString("\n" + strings.Repeat(" ", f.Indent*len(objects)))
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			empty := members[len(members)-1] == 0
+			objects, members = objects[:len(objects)-1], members[:len(members)-1]
+			if !empty {
+				newline()
+			}
+			b.WriteString(delim.String())
+			continue
+		}
+
+		key := false
+		if depth := len(objects); depth > 0 {
+			key = objects[depth-1] && members[depth-1]%2 == 0
+			if key || !objects[depth-1] {
+				if members[depth-1] > 0 {
+					b.WriteString(",")
+				}
+				newline()
+			}
+			members[depth-1]++
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			b.WriteString(value.String())
+			objects = append(objects, value == '{')
+			members = append(members, 0)
+		case string:
+			quoted, err := quoteJSON(value)
+			if err != nil {
+				return "", err
+			}
+
+			if key {
+				b.WriteString(paint(f.KeyColor, quoted) + ":")
+				if f.Indent > 0 {
+					b.WriteString(" ")
+				}
+			} else {
+				b.WriteString(paint(f.StringColor, quoted))
+			}
+		case json.Number:
+			b.WriteString(paint(f.NumberColor, value.String()))
+		case bool:
+			b.WriteString(paint(f.BoolColor, strconv.FormatBool(value)))
+		case nil:
+			b.WriteString(paint(f.NullColor, "null"))
+		}
+	}
+}
+
+// quoteJSON quotes the string without escaping the HTML characters, as json.Marshal does
+func quoteJSON(s string) (string, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// newTable is the tab writer every table output is aligned with
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", outputFormatSpec)
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, noColorSpec)
+	rootCmd.PersistentFlags().BoolVar(&expandJSON, "expand", false, expandJSONSpec)
+}
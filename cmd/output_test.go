@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/TylerBrock/colorjson"
+	"github.com/fatih/color"
+)
+
+func TestColorizeJSON(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	content := `{"zone":"eu-west-1a","query":"a<b && \"c\"","count":9007199254740993,"ratio":0.25,"tags":{"b":"2","a":"1"},"items":[1,"two",true,null,{}],"empty":[]}`
+
+	tests := []struct {
+		indent int
+		want   string
+	}{
+		{0, content},
+		{2, `{
+  "zone": "eu-west-1a",
+  "query": "a<b && \"c\"",
+  "count": 9007199254740993,
+  "ratio": 0.25,
+  "tags": {
+    "b": "2",
+    "a": "1"
+  },
+  "items": [
+    1,
+    "two",
+    true,
+    null,
+    {}
+  ],
+  "empty": []
+}`},
+	}
+
+	for _, test := range tests {
+		f := colorjson.NewFormatter()
+		f.Indent = test.indent
+
+		got, err := colorizeJSON([]byte(content), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != test.want {
+			t.Errorf("indent %d got\n%s\nwant\n%s", test.indent, got, test.want)
+		}
+	}
+}
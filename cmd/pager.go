@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// pagedOutput annotates the commands whose human-readable output goes through the pager
+var pagedOutput = map[string]string{"pager": "true"}
+
+var noPager bool
+var noPagerSpec = `Do not pipe long outputs through the pager`
+
+var pager *exec.Cmd
+var pagerStdout *os.File
+
+// pagerCommand is ECSCTL_PAGER, else PAGER, else less -FRX, which only pages when the output exceeds the terminal height.
+// An empty ECSCTL_PAGER or cat disables it.
+func pagerCommand() string {
+	if command, ok := os.LookupEnv("ECSCTL_PAGER"); ok {
+		return command
+	}
+
+	if command := os.Getenv("PAGER"); command != "" {
+		return command
+	}
+	return "less -FRX"
+}
+
+// isTerminal tells if the file is a terminal and not a pipe or a regular file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// machineOutput tells if the command was asked for an output other than table or text, which is never paged
+func machineOutput(cmd *cobra.Command) bool {
+	if jsonlOutput(cmd) {
+		return true
+	}
+
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil {
+		return false
+	}
+
+	switch flag.Value.String() {
+	case "", "table", "text":
+		return false
+	}
+	return true
+}
+
+// startPager replaces the standard output by the pager, as git does,
+// for the annotated commands printing to a terminal for humans
+func startPager(cmd *cobra.Command) {
+	if noPager || quiet || cmd.Annotations["pager"] != "true" || machineOutput(cmd) || !isTerminal(os.Stdout) {
+		return
+	}
+
+	command := pagerCommand()
+	if command == "" || command == "cat" {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		debugf("pager disabled: %s", err.Error())
+		return
+	}
+
+	pager = exec.Command("sh", "-c", command)
+	pager.Stdin = r
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+
+	if err := pager.Start(); err != nil {
+		debugf("pager disabled: %s", err.Error())
+		r.Close()
+		w.Close()
+		pager = nil
+		return
+	}
+	r.Close()
+
+	pagerStdout = os.Stdout
+	os.Stdout = w
+	color.Output = w
+	typist.Out = w
+}
+
+// stopPager ends the output and waits for the user to leave the pager
+func stopPager() {
+	if pager == nil {
+		return
+	}
+
+	os.Stdout.Close()
+	pager.Wait()
+
+	os.Stdout = pagerStdout
+	pager = nil
+}
+
+// must ends the invocation when there is an error, as typist.Must did, but through exit so the API summary
+// is printed too, and leaving the pager first: exiting while it runs leaves it orphaned
+func must(err error) {
+	if err == nil {
+		return
+	}
+
+	stopPager()
+	fmt.Fprintln(os.Stderr, err)
+	exit(1)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, noPagerSpec)
+}
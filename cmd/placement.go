@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// taskRequirements is what a container instance must offer to place a task of the Task Definition
+type taskRequirements struct {
+	cpu         int64
+	memory      int64
+	gpus        int64
+	ports       []string
+	attributes  []*ecs.Attribute
+	constraints []*ecs.TaskDefinitionPlacementConstraint
+}
+
+func requirementsOf(td *ecs.TaskDefinition) (r taskRequirements) {
+	r.cpu, _ = strconv.ParseInt(aws.StringValue(td.Cpu), 10, 64)
+	r.memory, _ = strconv.ParseInt(aws.StringValue(td.Memory), 10, 64)
+
+	var cpu, memory int64
+	for _, cd := range td.ContainerDefinitions {
+		cpu += aws.Int64Value(cd.Cpu)
+		if cd.Memory != nil {
+			memory += aws.Int64Value(cd.Memory)
+		} else {
+			memory += aws.Int64Value(cd.MemoryReservation)
+		}
+
+		r.gpus += containerGPUs(cd)
+
+		for _, pm := range cd.PortMappings {
+			port := aws.Int64Value(pm.HostPort)
+			if aws.StringValue(td.NetworkMode) == ecs.NetworkModeHost {
+				port = aws.Int64Value(pm.ContainerPort)
+			}
+
+			// Port 0 is a dynamic host port, any free one is taken
+			if port > 0 {
+				r.ports = append(r.ports, strconv.FormatInt(port, 10))
+			}
+		}
+	}
+
+	if r.cpu == 0 {
+		r.cpu = cpu
+	}
+
+	if r.memory == 0 {
+		r.memory = memory
+	}
+
+	r.attributes = td.RequiresAttributes
+	r.constraints = td.PlacementConstraints
+	return
+}
+
+// containerGPUs is the number of GPUs reserved by the resourceRequirements of the container
+func containerGPUs(cd *ecs.ContainerDefinition) int64 {
+	for _, rr := range cd.ResourceRequirements {
+		if aws.StringValue(rr.Type) == ecs.ResourceTypeGpu {
+			gpus, _ := strconv.ParseInt(aws.StringValue(rr.Value), 10, 64)
+			return gpus
+		}
+	}
+	return 0
+}
+
+// withContainerGPUs returns a copy of the Task Definition with the GPUs of the container replaced,
+// as a container override of resourceRequirements does, so it is checked as it will be placed
+func withContainerGPUs(td *ecs.TaskDefinition, container string, gpus int64) *ecs.TaskDefinition {
+	copied := *td
+	copied.ContainerDefinitions = nil
+
+	for _, cd := range td.ContainerDefinitions {
+		if aws.StringValue(cd.Name) == container {
+			replaced := *cd
+			replaced.ResourceRequirements = []*ecs.ResourceRequirement{{
+				Type:  aws.String(ecs.ResourceTypeGpu),
+				Value: aws.String(strconv.FormatInt(gpus, 10)),
+			}}
+
+			for _, rr := range cd.ResourceRequirements {
+				if aws.StringValue(rr.Type) != ecs.ResourceTypeGpu {
+					replaced.ResourceRequirements = append(replaced.ResourceRequirements, rr)
+				}
+			}
+			cd = &replaced
+		}
+
+		copied.ContainerDefinitions = append(copied.ContainerDefinitions, cd)
+	}
+
+	return &copied
+}
+
+// gpuCount is the number of GPUs of the resources, which ECS lists by their IDs
+func gpuCount(resources []*ecs.Resource) int64 {
+	for _, resource := range resources {
+		if aws.StringValue(resource.Name) == "GPU" {
+			return int64(len(resource.StringSetValue))
+		}
+	}
+	return 0
+}
+
+// gpuDriverAttribute is advertised by the container instances able to run tasks with GPUs
+const gpuDriverAttribute = "ecs.capability.gpu-driver-version"
+
+var placementConstraintPattern = regexp.MustCompile(`^attribute:(\S+)\s*(==|!=)\s*(\S+)$`)
+
+func instanceAttribute(ci *ecs.ContainerInstance, name string) (value string, found bool) {
+	for _, attribute := range ci.Attributes {
+		if aws.StringValue(attribute.Name) == name {
+			return aws.StringValue(attribute.Value), true
+		}
+	}
+	return
+}
+
+// placementProblems lists why the task can not be placed on the container instance, none when it can
+func placementProblems(ci *ecs.ContainerInstance, r taskRequirements) (problems []string) {
+	if aws.StringValue(ci.Status) != ecs.ContainerInstanceStatusActive {
+		problems = append(problems, "status is "+aws.StringValue(ci.Status))
+	}
+
+	if !aws.BoolValue(ci.AgentConnected) {
+		problems = append(problems, "agent disconnected")
+	}
+
+	usedPorts := make(map[string]bool)
+	for _, resource := range ci.RemainingResources {
+		switch aws.StringValue(resource.Name) {
+		case "CPU":
+			if has := aws.Int64Value(resource.IntegerValue); has < r.cpu {
+				problems = append(problems, fmt.Sprintf("insufficient cpu (needs %d, has %d)", r.cpu, has))
+			}
+		case "MEMORY":
+			if has := aws.Int64Value(resource.IntegerValue); has < r.memory {
+				problems = append(problems, fmt.Sprintf("insufficient memory (needs %d, has %d)", r.memory, has))
+			}
+		case "PORTS":
+			// The remaining PORTS are the ones already reserved on the instance
+			for _, port := range resource.StringSetValue {
+				usedPorts[aws.StringValue(port)] = true
+			}
+		}
+	}
+
+	if r.gpus > 0 {
+		if _, found := instanceAttribute(ci, gpuDriverAttribute); !found {
+			problems = append(problems, "no GPU driver (missing attribute "+gpuDriverAttribute+")")
+		} else if has := gpuCount(ci.RemainingResources); has < r.gpus {
+			problems = append(problems, fmt.Sprintf("insufficient gpus (needs %d, has %d)", r.gpus, has))
+		}
+	}
+
+	for _, port := range r.ports {
+		if usedPorts[port] {
+			problems = append(problems, fmt.Sprintf("host port %s already in use", port))
+		}
+	}
+
+	for _, attribute := range r.attributes {
+		name := aws.StringValue(attribute.Name)
+		value, found := instanceAttribute(ci, name)
+
+		switch {
+		case !found && attribute.Value == nil:
+			problems = append(problems, "missing attribute "+name)
+		case attribute.Value != nil && value != aws.StringValue(attribute.Value):
+			problems = append(problems, fmt.Sprintf("missing attribute %s=%s", name, aws.StringValue(attribute.Value)))
+		}
+	}
+
+	for _, constraint := range r.constraints {
+		if aws.StringValue(constraint.Type) != ecs.PlacementConstraintTypeMemberOf {
+			continue
+		}
+
+		expression := aws.StringValue(constraint.Expression)
+		match := placementConstraintPattern.FindStringSubmatch(expression)
+		if match == nil {
+			problems = append(problems, fmt.Sprintf("constraint '%s' not evaluated by ecsctl", expression))
+			continue
+		}
+
+		value, _ := instanceAttribute(ci, match[1])
+		if (value == match[3]) != (match[2] == "==") {
+			problems = append(problems, fmt.Sprintf("constraint '%s' not met (%s is '%s')", expression, match[1], value))
+		}
+	}
+
+	return
+}
+
+// explainPlacement compares the requirements of the Task Definition with every container instance of the cluster,
+// one line per reason an instance can not take the task
+func explainPlacement(cluster string, td *ecs.TaskDefinition) (lines []string, err error) {
+	instances, err := describeContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	if len(instances) == 0 {
+		lines = append(lines, cluster+": no container instances")
+		return
+	}
+
+	r := requirementsOf(td)
+	for _, ci := range instances {
+		id := aws.StringValue(ci.Ec2InstanceId)
+		problems := placementProblems(ci, r)
+
+		if registered := gpuCount(ci.RegisteredResources); r.gpus > 0 || registered > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d of %d gpus available", id, gpuCount(ci.RemainingResources), registered))
+		}
+
+		if len(problems) == 0 {
+			lines = append(lines, id+": meets all requirements")
+			continue
+		}
+
+		for _, problem := range problems {
+			lines = append(lines, id+": "+problem)
+		}
+	}
+
+	return
+}
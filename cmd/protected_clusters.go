@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func validateProtectedClusters(value interface{}) error {
+	for _, pattern := range value.([]interface{}) {
+		p, ok := pattern.(string)
+		if !ok {
+			return fmt.Errorf("protected_clusters must have only patterns, got %s", configValueKind(pattern))
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("'%s' is not a valid protected_clusters pattern", p)
+		}
+	}
+	return nil
+}
+
+// protectedClusterPattern returns the first pattern of protected_clusters matching the cluster name or ARN
+func protectedClusterPattern(cluster string) (pattern string, protected bool) {
+	name := cluster[strings.LastIndex(cluster, "/")+1:]
+
+	for _, p := range viper.GetStringSlice("protected_clusters") {
+		if matched, _ := path.Match(p, name); matched {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// confirmProtectedCluster requires the cluster name to be typed on the terminal, even when the standard input is in use
+var confirmProtectedCluster = func(name string) bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(os.Stderr, "Type the cluster name (%s) to proceed: ", name)
+
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	return strings.TrimSpace(answer) == name
+}
+
+// checkProtectedCluster refuses the action against a cluster matching protected_clusters,
+// unless its name is typed or --i-know-this-is-prod is informed
+func checkProtectedCluster(cluster, action string, acknowledged bool) error {
+	pattern, protected := protectedClusterPattern(cluster)
+	if !protected || acknowledged {
+		return nil
+	}
+
+	name := cluster[strings.LastIndex(cluster, "/")+1:]
+	fmt.Fprintf(os.Stderr, "Cluster %s matches the protected_clusters pattern '%s' and is about to %s\n", name, pattern, action)
+
+	if confirmProtectedCluster(name) {
+		return nil
+	}
+
+	return fmt.Errorf("Refusing to %s on cluster %s: it matches the protected_clusters pattern '%s'\nType the cluster name when asked or use --i-know-this-is-prod", action, name, pattern)
+}
+
+// clusterGuards holds the protection of each destructive command by the command, so it can be checked on its own
+var clusterGuards = make(map[*cobra.Command]func(args []string) error)
+
+// protectClusters is the pre-execution hook of destructive commands.
+// clustersOf returns the clusters the execution affects, none when it is not destructive (e.g. not scaling to 0).
+func protectClusters(cmd *cobra.Command, action string, clustersOf func(args []string) []string) {
+	var acknowledged bool
+	cmd.Flags().BoolVar(&acknowledged, "i-know-this-is-prod", false, iKnowThisIsProdSpec)
+
+	guard := func(args []string) error {
+		for _, cluster := range clustersOf(args) {
+			if err := checkProtectedCluster(cluster, action, acknowledged); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	clusterGuards[cmd] = guard
+
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		must(guard(args))
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// parseFlags parses the flags on the package command, setting them back to their defaults at the end of the test
+func parseFlags(t *testing.T, cmd *cobra.Command, args []string) {
+	t.Cleanup(func() {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Changed {
+				return
+			}
+
+			if slice, ok := f.Value.(pflag.SliceValue); ok {
+				slice.Replace(nil)
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	})
+
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProtectedClusters(t *testing.T) {
+	defer func(patterns interface{}, confirm func(string) bool) {
+		viper.Set("protected_clusters", patterns)
+		confirmProtectedCluster = confirm
+	}(viper.Get("protected_clusters"), confirmProtectedCluster)
+
+	viper.Set("protected_clusters", []string{"prod-*"})
+
+	var asked []string
+	confirmProtectedCluster = func(name string) bool {
+		asked = append(asked, name)
+		return false
+	}
+
+	tests := []struct {
+		name    string
+		cmd     *cobra.Command
+		args    []string
+		refused bool
+	}{
+		{"clusters delete --yes", clustersDeleteCmd, []string{"prod-eu", "--yes"}, true},
+		{"clusters delete --force", clustersDeleteCmd, []string{"arn:aws:ecs:eu-west-1:123456789012:cluster/prod-eu", "--force", "-y"}, true},
+		{"clusters delete unprotected", clustersDeleteCmd, []string{"staging", "--yes"}, false},
+		{"clusters delete acknowledged", clustersDeleteCmd, []string{"prod-eu", "--yes", "--i-know-this-is-prod"}, false},
+		{"container-instances drain --yes", containerInstancesDrainCmd, []string{"-c", "prod-eu", "--yes"}, true},
+		{"container-instances drain --plan", containerInstancesDrainCmd, []string{"-c", "prod-eu", "--plan"}, false},
+		{"services decommission --yes", servicesDecommissionCmd, []string{"web", "-c", "prod-eu", "--yes"}, true},
+		{"services scale to 0", servicesScaleCmd, []string{"web", "-c", "prod-eu", "--desired-count", "0"}, true},
+		{"services scale up", servicesScaleCmd, []string{"web", "-c", "prod-eu", "--desired-count", "2"}, false},
+		{"services scale-schedule down to 0", servicesScaleScheduleCmd, []string{"web", "-c", "prod-eu", "--down", "cron(0 20 * * ? *)"}, true},
+		{"services move --to-launch-type", servicesMoveCmd, []string{"web", "-c", "prod-eu", "--to-launch-type", "FARGATE", "--yes"}, true},
+		{"services move --to-strategy", servicesMoveCmd, []string{"web", "-c", "prod-eu", "--to-strategy", "FARGATE_SPOT:weight=1"}, false},
+		{"tasks stop --yes", tasksStopCmd, []string{"a1b2", "-c", "prod-eu", "--yes"}, true},
+		{"tasks wait without stopping", tasksWaitCmd, []string{"a1b2", "-c", "prod-eu", "--timeout", "5m"}, false},
+		{"tasks wait --stop-on-timeout", tasksWaitCmd, []string{"a1b2", "-c", "prod-eu", "--timeout", "5m", "--stop-on-timeout"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			asked = nil
+			parseFlags(t, test.cmd, test.args)
+
+			err := clusterGuards[test.cmd](test.cmd.Flags().Args())
+			if refused := err != nil; refused != test.refused {
+				t.Errorf("got refused %t (%v), want %t", refused, err, test.refused)
+			}
+
+			if test.refused && (len(asked) != 1 || asked[0] != "prod-eu") {
+				t.Errorf("got the names %v asked, want prod-eu", asked)
+			}
+		})
+	}
+}
+
+// TestProtectedCommandsGuarded fails when a command sharing the flags of a protected one, such as an alias, lacks its guard
+func TestProtectedCommandsGuarded(t *testing.T) {
+	walkCommands(rootCmd, func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup("i-know-this-is-prod") != nil && cmd.PreRun == nil {
+			t.Errorf("%s accepts --i-know-this-is-prod but does not check the protected clusters", cmd.CommandPath())
+		}
+	})
+}
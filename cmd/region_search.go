@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/viper"
+)
+
+// searchRegions are probed for a cluster not found in the active region, the known_regions of the config when empty
+var searchRegions []string
+var searchRegionsSpec = `Regions to look for a cluster not found in the active region, suggesting --region (default is known_regions of the config file)`
+
+var noRegionSearch bool
+var noRegionSearchSpec = `Do not look for a cluster not found in the active region on the other regions`
+
+// The search only delays an error, it is kept short
+const (
+	maxSearchRegions    = 8
+	regionSearchTimeout = 3 * time.Second
+)
+
+// regionHints caches the hint of every cluster searched, a command may fail on the same cluster many times
+var regionHints = struct {
+	sync.Mutex
+	byCluster map[string]string
+}{byCluster: make(map[string]string)}
+
+func validateKnownRegions(value interface{}) error {
+	for _, r := range value.([]interface{}) {
+		name, ok := r.(string)
+		if !ok {
+			return fmt.Errorf("known_regions must have only regions, got %s", configValueKind(r))
+		}
+
+		if err := validateRegion(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestCluster is the Cluster of the input of the request, empty for the inputs without one
+func requestCluster(params interface{}) string {
+	return requestField(params, "Cluster")
+}
+
+// requestField is a string parameter of the request by its name, empty when the input has no such field
+func requestField(params interface{}, name string) string {
+	v := reflect.ValueOf(params)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := v.Elem().FieldByName(name)
+	if !field.IsValid() || field.Type() != reflect.TypeOf((*string)(nil)) {
+		return ""
+	}
+	return aws.StringValue(field.Interface().(*string))
+}
+
+// regionsWithCluster probes the regions in parallel, returning the ones with an ACTIVE cluster of the name
+func regionsWithCluster(name string, regions []string) (found []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), regionSearchTimeout)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, r := range regions {
+		wg.Add(1)
+		go func(r string) {
+			defer wg.Done()
+
+			client := ecs.New(awsSession, aws.NewConfig().WithRegion(r).WithMaxRetries(0))
+			described, err := client.DescribeClustersWithContext(ctx, &ecs.DescribeClustersInput{
+				Clusters: []*string{aws.String(name)},
+			})
+			if err != nil {
+				debugf("region search %s: %s", r, err.Error())
+				return
+			}
+
+			for _, c := range described.Clusters {
+				if aws.StringValue(c.Status) == "ACTIVE" {
+					mutex.Lock()
+					found = append(found, r)
+					mutex.Unlock()
+				}
+			}
+		}(r)
+	}
+
+	wg.Wait()
+	sort.Strings(found)
+	return
+}
+
+// clusterRegionHint tells where the cluster may be: the region of its ARN, or the regions searched having it
+func clusterRegionHint(cluster, active string) string {
+	if parsed, err := arn.Parse(cluster); err == nil {
+		if parsed.Region != active {
+			return fmt.Sprintf("the cluster ARN is of %s, use --region %s", parsed.Region, parsed.Region)
+		}
+		return ""
+	}
+
+	regions := searchRegions
+	if len(regions) == 0 {
+		regions = viper.GetStringSlice("known_regions")
+	}
+
+	var others []string
+	for _, r := range regions {
+		if r != active {
+			others = append(others, r)
+		}
+	}
+
+	if len(others) > maxSearchRegions {
+		debugf("region search limited to %s", strings.Join(others[:maxSearchRegions], ", "))
+		others = others[:maxSearchRegions]
+	}
+
+	if len(others) == 0 || noRegionSearch {
+		return ""
+	}
+
+	found := regionsWithCluster(cluster, others)
+	switch len(found) {
+	case 0:
+		return "no cluster " + cluster + " in " + strings.Join(others, ", ") + " either"
+	case 1:
+		return fmt.Sprintf("a cluster %s exists in %s, use --region %s", cluster, found[0], found[0])
+	default:
+		return fmt.Sprintf("a cluster %s exists in %s, use --region to choose one", cluster, strings.Join(found, ", "))
+	}
+}
+
+// hintClusterRegion is a handler of the ECS client adding to ClusterNotFoundException where the cluster was looked for
+// and where it may be, as the default region of the credentials is often not the one of the cluster
+func hintClusterRegion(r *request.Request) {
+	if awsErrorCode(r.Error) != ecs.ErrCodeClusterNotFoundException {
+		return
+	}
+
+	cluster := requestCluster(r.Params)
+	if cluster == "" {
+		return
+	}
+
+	active := aws.StringValue(r.Config.Region)
+
+	regionHints.Lock()
+	hint, searched := regionHints.byCluster[cluster]
+	if !searched {
+		hint = clusterRegionHint(cluster, active)
+		regionHints.byCluster[cluster] = hint
+	}
+	regionHints.Unlock()
+
+	message := fmt.Sprintf("Cluster %s not found in %s", cluster, active)
+	if hint != "" {
+		message += ", " + hint
+	}
+
+	r.Error = awserr.New(ecs.ErrCodeClusterNotFoundException, message, r.Error)
+}
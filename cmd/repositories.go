@@ -1,9 +1,173 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 )
 
+var ecrImagePattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com(\.cn)?/`)
+
+// ecrRegistry returns the registry of an ECR image, failing for images hosted elsewhere
+func ecrRegistry(image string) (registry string, err error) {
+	if !ecrImagePattern.MatchString(image) {
+		err = fmt.Errorf("%s is not an ECR image", image)
+		return
+	}
+
+	registry = image[:strings.Index(image, "/")]
+	return
+}
+
+// ecrLogin authenticates docker on the registry, by running docker login or,
+// when docker is not on the PATH, writing the credentials on the docker config file
+func ecrLogin(registry string) (server string, err error) {
+	result, err := ecrI.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return
+	}
+
+	if len(result.AuthorizationData) == 0 {
+		err = errors.New("No authorization data returned by ECR")
+		return
+	}
+
+	data := result.AuthorizationData[0]
+
+	server = registry
+	if server == "" {
+		server = strings.TrimPrefix(aws.StringValue(data.ProxyEndpoint), "https://")
+	}
+
+	token := aws.StringValue(data.AuthorizationToken)
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return
+	}
+
+	credentials := strings.SplitN(string(decoded), ":", 2)
+	if len(credentials) != 2 {
+		err = errors.New("Unexpected authorization token returned by ECR")
+		return
+	}
+
+	if _, lookErr := exec.LookPath("docker"); lookErr != nil {
+		debugf("docker not found, writing the credentials on the docker config file")
+		err = writeDockerAuth(server, token)
+		return
+	}
+
+	login := exec.Command("docker", "login", "--username", credentials[0], "--password-stdin", server)
+	login.Stdin = strings.NewReader(credentials[1])
+	login.Stdout = os.Stderr
+	login.Stderr = os.Stderr
+	err = login.Run()
+	return
+}
+
+// writeDockerAuth sets the auth of the server on the docker config file, keeping everything else
+func writeDockerAuth(server, auth string) (err error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	file := filepath.Join(dir, "config.json")
+
+	config := make(map[string]interface{})
+	if content, err := os.ReadFile(file); err == nil {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return fmt.Errorf("Unable to parse %s: %s", file, err.Error())
+		}
+	}
+
+	auths, _ := config["auths"].(map[string]interface{})
+	if auths == nil {
+		auths = make(map[string]interface{})
+	}
+	auths[server] = map[string]string{"auth": auth}
+	config["auths"] = auths
+
+	content, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+
+	return os.WriteFile(file, content, 0600)
+}
+
+func runDocker(args ...string) (output []byte, err error) {
+	var stdout bytes.Buffer
+	docker := exec.Command("docker", args...)
+	docker.Stdout = &stdout
+	docker.Stderr = os.Stderr
+
+	debugf("docker %s", strings.Join(args, " "))
+	err = docker.Run()
+	return stdout.Bytes(), err
+}
+
+var dockerLoadedImagePattern = regexp.MustCompile(`Loaded image(?: ID)?: (\S+)`)
+
+// pushImage pushes the image to ECR, built from a directory or loaded from a docker save tarball
+func pushImage(image, tarball, buildContext string) (err error) {
+	registry, err := ecrRegistry(image)
+	if err != nil {
+		return
+	}
+
+	if _, err = exec.LookPath("docker"); err != nil {
+		return errors.New("docker is needed to push images")
+	}
+
+	if _, err = ecrLogin(registry); err != nil {
+		return
+	}
+
+	if buildContext != "" {
+		if _, err = runDocker("build", "--tag", image, buildContext); err != nil {
+			return
+		}
+	} else {
+		output, err := runDocker("load", "--input", tarball)
+		if err != nil {
+			return err
+		}
+
+		match := dockerLoadedImagePattern.FindSubmatch(output)
+		if match == nil {
+			return fmt.Errorf("Unable to find the image loaded from %s", tarball)
+		}
+
+		if _, err = runDocker("tag", string(match[1]), image); err != nil {
+			return err
+		}
+	}
+
+	_, err = runDocker("push", image)
+	return
+}
+
 func repositoriesRun(cmd *cobra.Command, args []string) {
 	cmd.Help()
 }
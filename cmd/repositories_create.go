@@ -1,9 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/spf13/cobra"
@@ -11,14 +8,12 @@ import (
 
 func repositoriesCreateRun(cmd *cobra.Command, repositories []string) {
 	for _, repository := range repositories {
-		_, err := ecrI.CreateRepository(&ecr.CreateRepositoryInput{
+		result, err := ecrI.CreateRepository(&ecr.CreateRepositoryInput{
 			RepositoryName: aws.String(repository),
 		})
+		must(err)
 
-		if err != nil {
-			fmt.Println(err.Error())
-			os.Exit(1)
-		}
+		printAffected(aws.StringValue(result.Repository.RepositoryArn), aws.StringValue(result.Repository.RepositoryUri)+" created")
 	}
 }
 
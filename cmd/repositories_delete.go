@@ -10,7 +10,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type repositoriesDeleteOptions struct {
+	yes   bool
+	force bool
+}
+
+var repositoriesDeleteOpts repositoriesDeleteOptions
+
 func repositoriesDeleteRun(cmd *cobra.Command, repositories []string) {
+	opts := &repositoriesDeleteOpts
+
 	repositoriesDescription, err := ecrI.DescribeRepositories(&ecr.DescribeRepositoriesInput{
 		RepositoryNames: aws.StringSlice(repositories),
 	})
@@ -19,10 +28,10 @@ func repositoriesDeleteRun(cmd *cobra.Command, repositories []string) {
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
 			if aerr.Code() != ecr.ErrCodeRepositoryNotFoundException {
-				typist.Must(err)
+				must(err)
 			}
 		} else {
-			typist.Must(err)
+			must(err)
 		}
 	}
 
@@ -44,11 +53,11 @@ func repositoriesDeleteRun(cmd *cobra.Command, repositories []string) {
 		}
 	}
 
-	if !force && len(missing) > 0 {
-		typist.Must(errors.New("Some repositories were not found:\n\t" + strings.Join(missing, "\n\t")))
+	if !opts.force && len(missing) > 0 {
+		must(errors.New("Some repositories were not found:\n\t" + strings.Join(missing, "\n\t")))
 	}
 
-	if !force && !yes && len(foundRepositories) > 0 {
+	if !opts.force && !opts.yes && len(foundRepositories) > 0 {
 		typist.Println("repositories to be deleted:")
 		for _, repository := range foundRepositories {
 			typist.Println(aws.StringValue(repository.RepositoryArn))
@@ -64,7 +73,7 @@ func repositoriesDeleteRun(cmd *cobra.Command, repositories []string) {
 			RepositoryName: repository.RepositoryName,
 		})
 
-		typist.Must(err)
+		must(err)
 
 		typist.Printf("%s deleted\n", aws.StringValue(repository.RepositoryArn))
 	}
@@ -80,6 +89,6 @@ var repositoriesDeleteCmd = &cobra.Command{
 func init() {
 	repositoriesCmd.AddCommand(repositoriesDeleteCmd)
 	flags := repositoriesDeleteCmd.Flags()
-	flags.BoolVarP(&yes, "yes", "y", false, yesSpec)
-	flags.BoolVarP(&force, "force", "f", false, forceSpec)
+	flags.BoolVarP(&repositoriesDeleteOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVarP(&repositoriesDeleteOpts.force, "force", "f", false, forceSpec)
 }
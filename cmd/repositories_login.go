@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type repositoriesLoginOptions struct {
+	registry string
+}
+
+var repositoriesLoginOpts repositoriesLoginOptions
+
+func repositoriesLoginRun(cmd *cobra.Command, args []string) {
+	opts := &repositoriesLoginOpts
+
+	server, err := ecrLogin(opts.registry)
+	must(err)
+
+	printAffected(server, "logged in to "+server)
+}
+
+var repositoriesLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log docker in to the ECR registry",
+	Args:  cobra.NoArgs,
+	Run:   repositoriesLoginRun,
+}
+
+func init() {
+	repositoriesCmd.AddCommand(repositoriesLoginCmd)
+
+	flags := repositoriesLoginCmd.Flags()
+
+	flags.StringVar(&repositoriesLoginOpts.registry, "registry", "", registrySpec)
+}
@@ -1,17 +1,31 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/fatih/color"
+	"github.com/gumieri/ecsctl/ecsx"
 	typistPkg "github.com/gumieri/typist"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -19,41 +33,151 @@ import (
 )
 
 var ecsI *ecs.ECS
+var ecsxI *ecsx.Client
 var ecrI *ecr.ECR
 var ec2I *ec2.EC2
 var iamI *iam.IAM
+var elbv2I *elbv2.ELBV2
+var stsI *sts.STS
+var s3I *s3.S3
+var r53I *route53.Route53
+var sdI *servicediscovery.ServiceDiscovery
+var ssmI *ssm.SSM
 var cwlI *cloudwatchlogs.CloudWatchLogs
+var cwI *cloudwatch.CloudWatch
+var aasI *applicationautoscaling.ApplicationAutoScaling
+var ebI *eventbridge.EventBridge
 
 var typist *typistPkg.Typist
 
 var awsSession *session.Session
 
+var interrupted, cancelInterrupted = context.WithCancel(context.Background())
+
+// interruptWatched is set once the command watches interruptContext
+var interruptWatched int32
+
+// watchInterrupts is installed by the root command for every command. The first SIGINT or SIGTERM cancels
+// interruptContext, so a long-running command stops at a safe point and prints what was done before the
+// interruption; the second one exits right away. Commands not watching the context exit at the first one.
+// Either way the exit goes through exit, waiting for the pager and printing the API summary.
+func watchInterrupts() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		if atomic.LoadInt32(&interruptWatched) == 0 {
+			exit(130)
+		}
+
+		fmt.Fprintln(os.Stderr, "interrupted, stopping at a safe point (interrupt again to exit now)")
+		cancelInterrupted()
+
+		<-signals
+		exit(130)
+	}()
+}
+
+// interruptContext is canceled by the first interruption, see watchInterrupts
+func interruptContext() context.Context {
+	atomic.StoreInt32(&interruptWatched, 1)
+	return interrupted
+}
+
+// sleepContext sleeps for the duration, returning false when the context is canceled meanwhile
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
 func persistentPreRun(cmd *cobra.Command, args []string) {
-	awsConfig := aws.Config{}
+	// The completions create their own clients, failing silently, as the shell shows anything printed
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return
+	}
 
-	if region != "" {
-		awsConfig.Region = aws.String(region)
+	watchInterrupts()
+
+	jsonl := jsonlOutput(cmd)
+	if quiet || noColor || jsonl {
+		color.NoColor = true
 	}
 
-	if profile != "" {
-		awsConfig.Credentials = credentials.NewSharedCredentials("", profile)
+	typist = &typistPkg.Typist{
+		Quiet: quiet,
+		In:    os.Stdin,
+		Out:   os.Stdout,
 	}
 
-	awsSession = session.New(&awsConfig)
+	// The standard output only has the log events with --jsonl, the rest goes to the standard error
+	if jsonl {
+		typist.Out = os.Stderr
+	}
+
+	var err error
+	awsSession, err = newAWSSession()
+	must(err)
+
+	collectAPICalls()
+	journalMutations()
 
 	ecsI = ecs.New(awsSession)
+	ecsI.Handlers.Complete.PushBack(hintClusterRegion)
+	ecsxI = ecsx.New(ecsI)
 	ecrI = ecr.New(awsSession)
 	ec2I = ec2.New(awsSession)
 	iamI = iam.New(awsSession)
+	elbv2I = elbv2.New(awsSession)
+	stsI = sts.New(awsSession)
+	s3I = s3.New(awsSession)
+	r53I = route53.New(awsSession)
+	sdI = servicediscovery.New(awsSession)
+	ssmI = ssm.New(awsSession)
 	cwlI = cloudwatchlogs.New(awsSession)
+	cwI = cloudwatch.New(awsSession)
+	aasI = applicationautoscaling.New(awsSession)
+	ebI = eventbridge.New(awsSession)
 
-	typist = &typistPkg.Typist{
-		Quiet: quiet,
-		In:    os.Stdin,
-		Out:   os.Stdout,
+	applyDefaultCluster(cmd)
+	resolveClusterFlags(cmd)
+	startPager(cmd)
+
+	if cmd != configValidateCmd {
+		warnUnknownConfigKeys()
 	}
 }
 
+// debugf prints to the standard error only when --debug is set
+func debugf(format string, a ...interface{}) {
+	if !debug {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "[debug] "+format+"\n", a...)
+}
+
+// printID prints the primary identifier of a listed resource, one per line.
+// It is the only output kept with --quiet, so scripts can rely on it.
+func printID(id string) {
+	fmt.Fprintln(os.Stdout, id)
+}
+
+// printAffected prints the message about a created or changed resource,
+// or only its identifier with --quiet
+func printAffected(id, message string) {
+	if quiet {
+		printID(id)
+		return
+	}
+
+	typist.Println(message)
+}
+
 var rootCmd = &cobra.Command{
 	Use:              "ecsctl",
 	Short:            "Collection of extra functions for AWS ECS",
@@ -63,10 +187,19 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	registerClusterCompletion(rootCmd)
+	registerResourceCompletion(rootCmd)
+
+	// The schema does not depend on the arguments nor the required flags, which cobra would validate first
+	if cmd := schemaRequested(os.Args[1:]); cmd != nil {
+		must(printOutputSchema(cmd))
+		return
 	}
+
+	must(rootCmd.Execute())
+
+	stopPager()
+	printAPISummary()
 }
 
 func init() {
@@ -82,6 +215,12 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, quietSpec)
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, debugSpec)
+	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+
+	rootCmd.PersistentFlags().StringSliceVar(&searchRegions, "search-regions", []string{}, searchRegionsSpec)
+	rootCmd.PersistentFlags().BoolVar(&noRegionSearch, "no-region-search", false, noRegionSearchSpec)
 }
 
 func initConfig() {
@@ -89,10 +228,7 @@ func initConfig() {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		home, err := homedir.Dir()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+		must(err)
 
 		viper.AddConfigPath(home)
 		viper.SetConfigName(".ecsctl")
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// TestQuietIdentifiers locks the identifier each command prints with --quiet, as documented on the README
+func TestQuietIdentifiers(t *testing.T) {
+	defer func(q bool) { quiet = q }(quiet)
+	quiet = true
+
+	tests := []struct {
+		name   string
+		run    func()
+		output string
+	}{
+		{
+			name:   "clusters list",
+			run:    func() { clustersListRun(clustersListCmd, nil) },
+			output: "arn:aws:ecs:us-east-1:123456789012:cluster/staging\narn:aws:ecs:us-east-1:123456789012:cluster/production\n",
+		},
+		{
+			name:   "clusters create",
+			run:    func() { clustersCreateRun(clustersCreateCmd, []string{"staging"}) },
+			output: "arn:aws:ecs:us-east-1:123456789012:cluster/staging\n",
+		},
+		{
+			name:   "repositories create",
+			run:    func() { repositoriesCreateRun(repositoriesCreateCmd, []string{"web"}) },
+			output: "arn:aws:ecr:us-east-1:123456789012:repository/web\n",
+		},
+		{
+			name: "task-definitions list",
+			run: func() {
+				defer func(opts taskDefinitionsListOptions) { taskDefinitionsListOpts = opts }(taskDefinitionsListOpts)
+				taskDefinitionsListOpts = taskDefinitionsListOptions{status: "active"}
+				taskDefinitionsListRun(taskDefinitionsListCmd, nil)
+			},
+			output: "web\nworker\n",
+		},
+		{
+			name: "task-definitions revisions",
+			run: func() {
+				defer func(opts taskDefinitionsRevisionsOptions) { taskDefinitionsRevisionsOpts = opts }(taskDefinitionsRevisionsOpts)
+				taskDefinitionsRevisionsOpts = taskDefinitionsRevisionsOptions{registeredBy: "deployer"}
+				taskDefinitionsRevisionsRun(taskDefinitionsRevisionsCmd, []string{"web"})
+			},
+			output: "arn:aws:ecs:us-east-1:123456789012:task-definition/web:2\n",
+		},
+	}
+
+	fakeAWS(t, func(operation string, decode func(interface{})) (interface{}, error) {
+		switch operation {
+		case "ListClusters":
+			input := &ecs.ListClustersInput{}
+			decode(input)
+			if input.NextToken == nil {
+				return &ecs.ListClustersOutput{
+					ClusterArns: aws.StringSlice([]string{"arn:aws:ecs:us-east-1:123456789012:cluster/staging"}),
+					NextToken:   aws.String("page-2"),
+				}, nil
+			}
+			return &ecs.ListClustersOutput{ClusterArns: aws.StringSlice([]string{"arn:aws:ecs:us-east-1:123456789012:cluster/production"})}, nil
+		case "CreateCluster":
+			input := &ecs.CreateClusterInput{}
+			decode(input)
+			return &ecs.CreateClusterOutput{Cluster: &ecs.Cluster{
+				ClusterArn: aws.String("arn:aws:ecs:us-east-1:123456789012:cluster/" + aws.StringValue(input.ClusterName)),
+			}}, nil
+		case "CreateRepository":
+			return &ecr.CreateRepositoryOutput{Repository: &ecr.Repository{
+				RepositoryArn: aws.String("arn:aws:ecr:us-east-1:123456789012:repository/web"),
+				RepositoryUri: aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/web"),
+			}}, nil
+		case "ListTaskDefinitionFamilies":
+			return &ecs.ListTaskDefinitionFamiliesOutput{Families: aws.StringSlice([]string{"web", "worker"})}, nil
+		case "ListTaskDefinitions":
+			return &ecs.ListTaskDefinitionsOutput{TaskDefinitionArns: aws.StringSlice([]string{
+				"arn:aws:ecs:us-east-1:123456789012:task-definition/web:3",
+				"arn:aws:ecs:us-east-1:123456789012:task-definition/web:2",
+				"arn:aws:ecs:us-east-1:123456789012:task-definition/web-worker:1",
+			})}, nil
+		case "DescribeTaskDefinition":
+			input := &ecs.DescribeTaskDefinitionInput{}
+			decode(input)
+			family, revision := splitTaskDefinitionArn(aws.StringValue(input.TaskDefinition))
+			registeredBy := "arn:aws:iam::123456789012:user/someone"
+			if revision == 2 {
+				registeredBy = "arn:aws:iam::123456789012:role/deployer"
+			}
+			return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &ecs.TaskDefinition{
+				TaskDefinitionArn: input.TaskDefinition,
+				Family:            aws.String(family),
+				Revision:          aws.Int64(revision),
+				RegisteredBy:      aws.String(registeredBy),
+			}}, nil
+		}
+		return nil, &fakeError{"InvalidParameterException", operation + " not expected"}
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if output := captureStdout(t, test.run); output != test.output {
+				t.Errorf("got %q, want %q", output, test.output)
+			}
+		})
+	}
+}
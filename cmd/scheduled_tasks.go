@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/spf13/cobra"
+)
+
+// scheduledTaskTargetID is the id of the ECS target of the rules created by ecsctl
+const scheduledTaskTargetID = "ecsctl"
+
+var rateExpressionPattern = regexp.MustCompile(`^rate\((\d+) (minute|minutes|hour|hours|day|days)\)$`)
+
+// validateSchedule checks an EventBridge schedule expression: rate(5 minutes) or cron(0 12 * * ? *).
+// The API rejects rate(1 minutes) and rate(5 minute), so the unit must agree with the value.
+func validateSchedule(expression string) error {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "cron(") {
+		_, err := parseCron(expression)
+		return err
+	}
+
+	match := rateExpressionPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return fmt.Errorf("Invalid schedule '%s', expected rate(value unit) or cron(minutes hours day-of-month month day-of-week year)", expression)
+	}
+
+	value, _ := strconv.Atoi(match[1])
+	plural := strings.HasSuffix(match[2], "s")
+	switch {
+	case value == 0:
+		return fmt.Errorf("Invalid schedule '%s', the rate must be greater than zero", expression)
+	case value == 1 && plural:
+		return fmt.Errorf("Invalid schedule '%s', use rate(1 %s)", expression, strings.TrimSuffix(match[2], "s"))
+	case value > 1 && !plural:
+		return fmt.Errorf("Invalid schedule '%s', use rate(%d %ss)", expression, value, match[2])
+	}
+	return nil
+}
+
+// describeSchedule shows when a cron schedule runs next, rates run relative to the creation of the rule
+func describeSchedule(expression string) string {
+	if !strings.HasPrefix(expression, "cron(") {
+		return "-"
+	}
+	return describeCronSchedule(expression, "UTC")
+}
+
+// ecsTargets lists the targets of the rule running ECS tasks
+func ecsTargets(rule string) (targets []*eventbridge.Target, err error) {
+	input := &eventbridge.ListTargetsByRuleInput{Rule: aws.String(rule)}
+	for {
+		listed, err := ebI.ListTargetsByRule(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range listed.Targets {
+			if t.EcsParameters != nil {
+				targets = append(targets, t)
+			}
+		}
+
+		if listed.NextToken == nil {
+			return targets, nil
+		}
+		input.NextToken = listed.NextToken
+	}
+}
+
+func scheduledTasksRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var scheduledTasksCmd = &cobra.Command{
+	Use:     "scheduled-tasks [command]",
+	Short:   "Commands to manage tasks run on a schedule by EventBridge rules",
+	Aliases: []string{"scheduled-task"},
+	Run:     scheduledTasksRun,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduledTasksCmd)
+}
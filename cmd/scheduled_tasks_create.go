@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/spf13/cobra"
+)
+
+type scheduledTasksCreateOptions struct {
+	cluster        string
+	schedule       string
+	taskDefinition string
+	latest         bool
+	roleArn        string
+	count          int64
+	launchType     string
+	subnets        []string
+	securityGroups []string
+	assignPublicIP bool
+	description    string
+}
+
+var scheduledTasksCreateOpts scheduledTasksCreateOptions
+
+// scheduleRoleArn accepts the ARN of the role or its name
+func scheduleRoleArn(role string) (string, error) {
+	if strings.HasPrefix(role, "arn:") {
+		return role, nil
+	}
+
+	described, err := iamI.GetRole(&iam.GetRoleInput{RoleName: aws.String(role)})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(described.Role.Arn), nil
+}
+
+// eventNetworkConfiguration is the network configuration of the task in the form of the EventBridge API
+func eventNetworkConfiguration(config *ecs.NetworkConfiguration) *eventbridge.NetworkConfiguration {
+	if config == nil {
+		return nil
+	}
+
+	return &eventbridge.NetworkConfiguration{
+		AwsvpcConfiguration: &eventbridge.AwsVpcConfiguration{
+			Subnets:        config.AwsvpcConfiguration.Subnets,
+			SecurityGroups: config.AwsvpcConfiguration.SecurityGroups,
+			AssignPublicIp: config.AwsvpcConfiguration.AssignPublicIp,
+		},
+	}
+}
+
+func scheduledTasksCreateRun(cmd *cobra.Command, args []string) {
+	opts := &scheduledTasksCreateOpts
+	name := args[0]
+
+	must(validateSchedule(opts.schedule))
+
+	reference, err := taskDefinitionReference(opts.taskDefinition, "")
+	must(err)
+
+	if _, revision := splitTaskDefinitionArn(reference); opts.latest && revision > 0 {
+		must(errors.New("--latest targets the family, inform it without a revision"))
+	}
+
+	launchType := strings.ToUpper(opts.launchType)
+	switch launchType {
+	case "", ecs.LaunchTypeEc2, ecs.LaunchTypeFargate, ecs.LaunchTypeExternal:
+	default:
+		must(errors.New("Invalid --launch-type '" + opts.launchType + "', expected EC2, FARGATE or EXTERNAL"))
+	}
+
+	td, err := describeTaskDefinition(reference)
+	must(err)
+
+	// Without the revision, EventBridge runs the latest ACTIVE revision of the family at each run
+	taskDefinitionArn := aws.StringValue(td.TaskDefinitionArn)
+	target := familyRevision(td.Family, td.Revision)
+	if opts.latest {
+		taskDefinitionArn = taskDefinitionArn[:strings.LastIndex(taskDefinitionArn, ":")]
+		target = aws.StringValue(td.Family) + " (latest)"
+	}
+
+	networkConfiguration, err := runNetworkConfiguration(td, launchType, opts.subnets, opts.securityGroups, opts.assignPublicIP)
+	must(err)
+
+	roleArn, err := scheduleRoleArn(opts.roleArn)
+	must(err)
+
+	c, err := describeCluster(opts.cluster)
+	must(err)
+
+	description := opts.description
+	if description == "" {
+		description = fmt.Sprintf("Runs %s on %s, created by ecsctl", target, aws.StringValue(c.ClusterName))
+	}
+
+	var tags []*eventbridge.Tag
+	for _, tag := range resourceTags(nil) {
+		tags = append(tags, &eventbridge.Tag{Key: tag.Key, Value: tag.Value})
+	}
+
+	rule, err := ebI.PutRule(&eventbridge.PutRuleInput{
+		Name:               aws.String(name),
+		ScheduleExpression: aws.String(strings.TrimSpace(opts.schedule)),
+		State:              aws.String(eventbridge.RuleStateEnabled),
+		Description:        aws.String(description),
+		Tags:               tags,
+	})
+	must(err)
+
+	ecsParameters := &eventbridge.EcsParameters{
+		TaskDefinitionArn:    aws.String(taskDefinitionArn),
+		TaskCount:            aws.Int64(opts.count),
+		NetworkConfiguration: eventNetworkConfiguration(networkConfiguration),
+	}
+
+	if launchType != "" {
+		ecsParameters.LaunchType = aws.String(launchType)
+	}
+
+	put, err := ebI.PutTargets(&eventbridge.PutTargetsInput{
+		Rule: aws.String(name),
+		Targets: []*eventbridge.Target{{
+			Id:            aws.String(scheduledTaskTargetID),
+			Arn:           c.ClusterArn,
+			RoleArn:       aws.String(roleArn),
+			EcsParameters: ecsParameters,
+		}},
+	})
+	must(err)
+
+	if len(put.FailedEntries) > 0 {
+		failed := put.FailedEntries[0]
+		must(fmt.Errorf("Rule %s created, but not its target: %s (%s)", name, aws.StringValue(failed.ErrorMessage), aws.StringValue(failed.ErrorCode)))
+	}
+
+	message := fmt.Sprintf("%s scheduled %s, running %s on %s", name, opts.schedule, target, aws.StringValue(c.ClusterName))
+	if next := describeSchedule(opts.schedule); next != "-" {
+		message += ", next at " + next
+	}
+	printAffected(aws.StringValue(rule.RuleArn), message)
+}
+
+var scheduledTasksCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an EventBridge rule running a Task Definition on a schedule",
+	Args:  cobra.ExactArgs(1),
+	Run:   scheduledTasksCreateRun,
+}
+
+func init() {
+	scheduledTasksCmd.AddCommand(scheduledTasksCreateCmd)
+
+	flags := scheduledTasksCreateCmd.Flags()
+
+	flags.StringVarP(&scheduledTasksCreateOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&scheduledTasksCreateOpts.schedule, "schedule", "", requiredSpec+scheduleSpec)
+	flags.StringVarP(&scheduledTasksCreateOpts.taskDefinition, "task-definition", "d", "", requiredSpec+scheduleTaskDefinitionSpec)
+	flags.BoolVar(&scheduledTasksCreateOpts.latest, "latest", false, scheduleLatestSpec)
+	flags.StringVar(&scheduledTasksCreateOpts.roleArn, "role-arn", "", requiredSpec+scheduleRoleSpec)
+	flags.Int64Var(&scheduledTasksCreateOpts.count, "count", 1, scheduleCountSpec)
+	flags.StringVar(&scheduledTasksCreateOpts.launchType, "launch-type", "", runLaunchTypeSpec)
+	flags.StringSliceVarP(&scheduledTasksCreateOpts.subnets, "subnet", "n", []string{}, runSubnetsSpec)
+	flags.StringSliceVarP(&scheduledTasksCreateOpts.securityGroups, "security-group", "g", []string{}, runSecurityGroupsSpec)
+	pluralNetworkFlags(flags)
+	flags.BoolVar(&scheduledTasksCreateOpts.assignPublicIP, "assign-public-ip", false, assignPublicIPSpec)
+	flags.StringVar(&scheduledTasksCreateOpts.description, "description", "", scheduleDescriptionSpec)
+
+	scheduledTasksCreateCmd.MarkFlagRequired("cluster")
+	scheduledTasksCreateCmd.MarkFlagRequired("schedule")
+	scheduledTasksCreateCmd.MarkFlagRequired("task-definition")
+	scheduledTasksCreateCmd.MarkFlagRequired("role-arn")
+}
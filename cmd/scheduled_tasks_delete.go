@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/spf13/cobra"
+)
+
+type scheduledTasksDeleteOptions struct {
+	yes bool
+}
+
+var scheduledTasksDeleteOpts scheduledTasksDeleteOptions
+
+// deleteScheduledTask removes the targets of the rule, as a rule with targets can not be deleted, then the rule.
+// Every removal shifts the pages, so the first one is listed again until there is no target left.
+func deleteScheduledTask(name string) error {
+	for {
+		listed, err := ebI.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: aws.String(name)})
+		if err != nil {
+			return err
+		}
+
+		if len(listed.Targets) == 0 {
+			break
+		}
+
+		var ids []*string
+		for _, t := range listed.Targets {
+			ids = append(ids, t.Id)
+		}
+
+		removed, err := ebI.RemoveTargets(&eventbridge.RemoveTargetsInput{Rule: aws.String(name), Ids: ids})
+		if err != nil {
+			return err
+		}
+
+		if len(removed.FailedEntries) > 0 {
+			failed := removed.FailedEntries[0]
+			return fmt.Errorf("Target %s of %s not removed: %s", aws.StringValue(failed.TargetId), name, aws.StringValue(failed.ErrorMessage))
+		}
+	}
+
+	_, err := ebI.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String(name)})
+	return err
+}
+
+func scheduledTasksDeleteRun(cmd *cobra.Command, names []string) {
+	opts := &scheduledTasksDeleteOpts
+
+	var rules []*eventbridge.DescribeRuleOutput
+	for _, name := range names {
+		rule, err := ebI.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(name)})
+		must(err)
+
+		rules = append(rules, rule)
+	}
+
+	if !opts.yes {
+		typist.Println("scheduled tasks to be deleted:")
+		for _, rule := range rules {
+			typist.Printf("%s\t%s\n", aws.StringValue(rule.Name), aws.StringValue(rule.ScheduleExpression))
+		}
+
+		if !typist.Confirm("Do you really want to delete these scheduled tasks?") {
+			return
+		}
+	}
+
+	for _, rule := range rules {
+		must(deleteScheduledTask(aws.StringValue(rule.Name)))
+
+		printAffected(aws.StringValue(rule.Arn), aws.StringValue(rule.Name)+" deleted")
+	}
+}
+
+var scheduledTasksDeleteCmd = &cobra.Command{
+	Use:   "delete [names...]",
+	Short: "Delete scheduled tasks, their EventBridge rules and targets",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   scheduledTasksDeleteRun,
+}
+
+func init() {
+	scheduledTasksCmd.AddCommand(scheduledTasksDeleteCmd)
+
+	flags := scheduledTasksDeleteCmd.Flags()
+	flags.BoolVarP(&scheduledTasksDeleteOpts.yes, "yes", "y", false, yesSpec)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/spf13/cobra"
+)
+
+type scheduledTasksListOptions struct {
+	cluster string
+}
+
+var scheduledTasksListOpts scheduledTasksListOptions
+
+type scheduledTaskRow struct {
+	Name           string `json:"name"`
+	RuleArn        string `json:"ruleArn"`
+	Schedule       string `json:"schedule"`
+	State          string `json:"state"`
+	TaskDefinition string `json:"taskDefinition"`
+	TaskCount      int64  `json:"taskCount"`
+	LaunchType     string `json:"launchType,omitempty"`
+	RoleArn        string `json:"roleArn"`
+}
+
+// scheduledTasks describes the rules running tasks on the cluster, one row per ECS target
+func scheduledTasks(clusterArn string) (rows []*scheduledTaskRow, err error) {
+	var names []*string
+	input := &eventbridge.ListRuleNamesByTargetInput{TargetArn: aws.String(clusterArn)}
+	for {
+		listed, err := ebI.ListRuleNamesByTarget(input)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, listed.RuleNames...)
+		if listed.NextToken == nil {
+			break
+		}
+		input.NextToken = listed.NextToken
+	}
+
+	for _, name := range names {
+		rule, err := ebI.DescribeRule(&eventbridge.DescribeRuleInput{Name: name})
+		if err != nil {
+			return nil, err
+		}
+
+		// Rules matching events instead of a schedule are not scheduled tasks
+		if rule.ScheduleExpression == nil {
+			continue
+		}
+
+		targets, err := ecsTargets(aws.StringValue(name))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range targets {
+			if aws.StringValue(t.Arn) != clusterArn {
+				continue
+			}
+
+			family, revision := splitTaskDefinitionArn(aws.StringValue(t.EcsParameters.TaskDefinitionArn))
+			taskDefinition := family + " (latest)"
+			if revision > 0 {
+				taskDefinition = fmt.Sprintf("%s:%d", family, revision)
+			}
+
+			rows = append(rows, &scheduledTaskRow{
+				Name:           aws.StringValue(rule.Name),
+				RuleArn:        aws.StringValue(rule.Arn),
+				Schedule:       aws.StringValue(rule.ScheduleExpression),
+				State:          aws.StringValue(rule.State),
+				TaskDefinition: taskDefinition,
+				TaskCount:      aws.Int64Value(t.EcsParameters.TaskCount),
+				LaunchType:     aws.StringValue(t.EcsParameters.LaunchType),
+				RoleArn:        aws.StringValue(t.RoleArn),
+			})
+		}
+	}
+	return
+}
+
+func scheduledTasksListRun(cmd *cobra.Command, args []string) {
+	opts := &scheduledTasksListOpts
+
+	must(checkOutputFormat(outputFormat, "text", "json"))
+
+	c, err := describeCluster(opts.cluster)
+	must(err)
+
+	rows, err := scheduledTasks(aws.StringValue(c.ClusterArn))
+	must(err)
+
+	if rows == nil {
+		rows = []*scheduledTaskRow{}
+	}
+
+	if outputFormat == "json" {
+		must(printJSON(rows))
+		return
+	}
+
+	if quiet {
+		for _, row := range rows {
+			printID(row.Name)
+		}
+		return
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tSTATE\tTASK DEFINITION\tCOUNT\tLAUNCH TYPE\tNEXT RUN")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", row.Name, row.Schedule, row.State, row.TaskDefinition,
+			row.TaskCount, firstSetting(row.LaunchType, "-"), describeSchedule(row.Schedule))
+	}
+	w.Flush()
+}
+
+var scheduledTasksListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the EventBridge rules running tasks on the cluster on a schedule",
+	Args:        cobra.NoArgs,
+	Run:         scheduledTasksListRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	scheduledTasksCmd.AddCommand(scheduledTasksListCmd)
+
+	flags := scheduledTasksListCmd.Flags()
+	flags.StringVarP(&scheduledTasksListOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+
+	scheduledTasksListCmd.MarkFlagRequired("cluster")
+
+	registerOutputSchema(scheduledTasksListCmd, "scheduled-tasks list", []*scheduledTaskRow{})
+}
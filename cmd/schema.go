@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// outputSchemaVersion is carried by every --output json payload. Within a major version the changes
+// are strictly additive: fields are added, never renamed, removed or retyped. Anything else bumps the major.
+const outputSchemaVersion = "1.0"
+
+// printSchema is only declared for the help and the completion, --schema is handled by schemaRequested
+var printSchema bool
+var printSchemaSpec = `Print the JSON Schema of the --output json of the command and exit`
+
+// outputSchemas holds a sample of the JSON output of each command, by the key of its schema annotation
+var outputSchemas = make(map[string]interface{})
+
+// registerOutputSchema annotates the command with the type of its JSON output. It must be called before
+// the command is mirrored by its aliases, which copy the annotations.
+func registerOutputSchema(cmd *cobra.Command, key string, sample interface{}) {
+	annotations := map[string]string{"outputSchema": key}
+	for k, v := range cmd.Annotations {
+		annotations[k] = v
+	}
+
+	cmd.Annotations = annotations
+	outputSchemas[key] = sample
+}
+
+// versionedOutput adds the schemaVersion to a JSON payload, first, without decoding the payload again so its
+// fields keep their order and 64-bit integers their precision. Lists, having no object to carry it, are wrapped in items.
+func versionedOutput(v interface{}) (versioned interface{}, err error) {
+	if kind := reflect.ValueOf(v).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		return map[string]interface{}{"schemaVersion": outputSchemaVersion, "items": v}, nil
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	version := `{"schemaVersion":"` + outputSchemaVersion + `"`
+	switch content = bytes.TrimSpace(content); {
+	case string(content) == "null" || string(content) == "{}":
+		return json.RawMessage(version + "}"), nil
+	case content[0] != '{':
+		return nil, fmt.Errorf("%T is not a JSON object, it can not carry the schemaVersion", v)
+	}
+
+	return json.RawMessage(version + "," + string(content[1:])), nil
+}
+
+// printVersionedJSON prints the payload indented, with its schemaVersion
+func printVersionedJSON(v interface{}) (err error) {
+	versioned, err := versionedOutput(v)
+	if err != nil {
+		return
+	}
+
+	content, err := json.MarshalIndent(versioned, "", "  ")
+	if err != nil {
+		return
+	}
+
+	fmt.Println(string(content))
+	return
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchema describes the type as encoding/json marshals it. Types already being described
+// (recursive ones) are left as any object.
+func jsonSchema(t reflect.Type, describing map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case describing[t]:
+		return map[string]interface{}{"type": "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonSchema(t.Elem(), describing)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchema(t.Elem(), describing)}
+	case reflect.Struct:
+	default:
+		return map[string]interface{}{}
+	}
+
+	describing[t] = true
+	defer delete(describing, t)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag, tagged := field.Tag.Lookup("json")
+
+		// Embedded structs without a name of their own have their fields promoted
+		if field.Anonymous && (!tagged || strings.HasPrefix(tag, ",")) {
+			embedded := jsonSchema(field.Type, describing)
+			if promoted, ok := embedded["properties"].(map[string]interface{}); ok {
+				for name, schema := range promoted {
+					properties[name] = schema
+				}
+				if field.Type.Kind() != reflect.Ptr {
+					required = append(required, embedded["required"].([]string)...)
+				}
+			}
+			continue
+		}
+
+		name, options := field.Name, ""
+		if tagged {
+			if tag == "-" {
+				continue
+			}
+
+			parts := strings.SplitN(tag, ",", 2)
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			if len(parts) == 2 {
+				options = parts[1]
+			}
+		}
+
+		properties[name] = jsonSchema(field.Type, describing)
+
+		// Pointers and omitempty fields may be missing from the payload
+		if field.Type.Kind() != reflect.Ptr && !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
+
+// outputSchema is the JSON Schema of the versioned payload of the sample
+func outputSchema(key string, sample interface{}) map[string]interface{} {
+	version := map[string]interface{}{"type": "string", "const": outputSchemaVersion}
+
+	payload := jsonSchema(reflect.TypeOf(sample), make(map[reflect.Type]bool))
+	if payload["type"] == "array" {
+		payload = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"schemaVersion": version, "items": payload},
+			"required":   []string{"schemaVersion", "items"},
+		}
+	} else {
+		payload["properties"].(map[string]interface{})["schemaVersion"] = version
+		payload["required"] = append(payload["required"].([]string), "schemaVersion")
+	}
+
+	payload["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	payload["title"] = "ecsctl " + key + " --output json"
+	return payload
+}
+
+// schemaRequested finds the command when --schema is among the arguments, nil otherwise
+func schemaRequested(args []string) *cobra.Command {
+	requested := false
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if arg == "--schema" || arg == "--schema=true" {
+			requested = true
+		}
+	}
+
+	if !requested {
+		return nil
+	}
+
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return nil
+	}
+	return cmd
+}
+
+// printOutputSchema prints the schema of the command, failing for the commands without a JSON output
+func printOutputSchema(cmd *cobra.Command) error {
+	key := cmd.Annotations["outputSchema"]
+	if key == "" {
+		return fmt.Errorf("%s has no JSON output", cmd.CommandPath())
+	}
+
+	content, err := json.MarshalIndent(outputSchema(key, outputSchemas[key]), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(content))
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&printSchema, "schema", false, printSchemaSpec)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "write the current output schemas to testdata/schemas")
+
+func TestVersionedOutput(t *testing.T) {
+	type payload struct {
+		Zeta  string `json:"zeta"`
+		Alpha int64  `json:"alpha"`
+	}
+
+	versioned, err := versionedOutput(payload{Zeta: "z", Alpha: 1<<62 + 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := json.Marshal(versioned)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"schemaVersion":"1.0","zeta":"z","alpha":4611686018427387905}`
+	if string(content) != want {
+		t.Errorf("got %s, want %s", content, want)
+	}
+}
+
+func TestVersionedOutputLists(t *testing.T) {
+	for _, v := range []interface{}{[]string{"a"}, []*scheduledTaskRow{}} {
+		versioned, err := versionedOutput(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, _ := json.Marshal(versioned)
+		if !strings.Contains(string(content), `"schemaVersion":"1.0"`) || !strings.Contains(string(content), `"items":[`) {
+			t.Errorf("list not wrapped in items: %s", content)
+		}
+	}
+}
+
+func TestVersionedOutputEmpty(t *testing.T) {
+	var empty *serviceDescription
+	for _, v := range []interface{}{empty, struct{}{}} {
+		versioned, err := versionedOutput(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if content, _ := json.Marshal(versioned); string(content) != `{"schemaVersion":"1.0"}` {
+			t.Errorf("got %s", content)
+		}
+	}
+
+	if _, err := versionedOutput("text"); err == nil {
+		t.Error("a string can not carry the schemaVersion, expected an error")
+	}
+}
+
+// TestOutputSchemas keeps the schemas of the --output json payloads as committed in testdata/schemas,
+// so a change of the payloads is a deliberate one. Run with -update-schemas to accept it.
+func TestOutputSchemas(t *testing.T) {
+	if len(outputSchemas) == 0 {
+		t.Fatal("no output schema registered")
+	}
+
+	for key, sample := range outputSchemas {
+		content, err := json.MarshalIndent(outputSchema(key, sample), "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		content = append(content, '\n')
+
+		path := filepath.Join("testdata", "schemas", strings.ReplaceAll(key, " ", "_")+".json")
+		if *updateSchemas {
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		committed, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s: %s, run the tests with -update-schemas to add it", key, err.Error())
+			continue
+		}
+
+		if string(committed) != string(content) {
+			t.Errorf("the schema of %s changed from %s, run the tests with -update-schemas if it is deliberate:\n%s", key, path, content)
+		}
+	}
+}
+
+// TestOutputSchemasAdditive checks the committed schemas against the current ones: within a major version
+// the fields are only added, never removed nor retyped
+func TestOutputSchemasAdditive(t *testing.T) {
+	for key, sample := range outputSchemas {
+		path := filepath.Join("testdata", "schemas", strings.ReplaceAll(key, " ", "_")+".json")
+		committed, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var previous map[string]interface{}
+		if err := json.Unmarshal(committed, &previous); err != nil {
+			t.Fatalf("%s: %s", path, err.Error())
+		}
+
+		content, _ := json.Marshal(outputSchema(key, sample))
+		var current map[string]interface{}
+		json.Unmarshal(content, &current)
+
+		checkAdditive(t, key, previous, current)
+	}
+}
+
+func checkAdditive(t *testing.T, path string, previous, current map[string]interface{}) {
+	if previous["type"] != current["type"] {
+		t.Errorf("%s retyped from %v to %v", path, previous["type"], current["type"])
+		return
+	}
+
+	if items, ok := previous["items"].(map[string]interface{}); ok {
+		currentItems, _ := current["items"].(map[string]interface{})
+		checkAdditive(t, path+"[]", items, currentItems)
+	}
+
+	properties, _ := previous["properties"].(map[string]interface{})
+	currentProperties, _ := current["properties"].(map[string]interface{})
+	for name, schema := range properties {
+		currentSchema, ok := currentProperties[name].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s.%s removed", path, name)
+			continue
+		}
+		checkAdditive(t, path+"."+name, schema.(map[string]interface{}), currentSchema)
+	}
+}
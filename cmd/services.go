@@ -1,9 +1,125 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
 	"github.com/spf13/cobra"
 )
 
+// describeTasks describes the tasks, failing when any of them could not be described
+func describeTasks(cluster string, taskArns []*string) (tasks []*ecs.Task, err error) {
+	return ecsxI.DescribeAllTasks(cluster, taskArns)
+}
+
+// serviceTasks lists and describes the tasks of a service with the desired status (RUNNING or STOPPED)
+func serviceTasks(cluster, service, desiredStatus string) (tasks []*ecs.Task, err error) {
+	taskArns, err := ecsxI.ListAllTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		ServiceName:   aws.String(service),
+		DesiredStatus: aws.String(desiredStatus),
+	})
+	if err != nil {
+		return
+	}
+
+	return describeTasks(cluster, taskArns)
+}
+
+// isBareFamily tells if a task definition is referenced only by its family, resolved by ECS to the latest revision
+func isBareFamily(taskDefinition string) bool {
+	name := taskDefinition[strings.LastIndex(taskDefinition, "/")+1:]
+	return !strings.Contains(name, ":")
+}
+
+// runningRevisions counts the running tasks of the service by the task definition ARN they were started with
+func runningRevisions(cluster, service string) (revisions map[string]int, err error) {
+	tasks, err := serviceTasks(cluster, service, ecs.DesiredStatusRunning)
+	if err != nil {
+		return
+	}
+
+	revisions = make(map[string]int)
+	for _, t := range tasks {
+		revisions[aws.StringValue(t.TaskDefinitionArn)]++
+	}
+	return
+}
+
+// revisionMismatches describes the running tasks not on the configured task definition of the service
+func revisionMismatches(s *ecs.Service, revisions map[string]int) (mismatches []string) {
+	configured := aws.StringValue(s.TaskDefinition)
+	for arn, count := range revisions {
+		if arn != configured {
+			mismatches = append(mismatches, fmt.Sprintf("%d task(s) running %s, the service is configured with %s", count, arn, configured))
+		}
+	}
+	sort.Strings(mismatches)
+	return
+}
+
+// listServices returns the ARN of every service of the cluster
+func listServices(cluster string) (serviceArns []*string, err error) {
+	return ecsxI.ListAllServices(cluster)
+}
+
+// describeServices describes the services, failing when any of them could not be described
+func describeServices(cluster string, services []*string) (described []*ecs.Service, err error) {
+	return ecsxI.DescribeAllServices(cluster, services)
+}
+
+// describeService describes a single service, failing when it does not exist
+func describeService(cluster, service string) (s *ecs.Service, err error) {
+	described, err := describeServices(cluster, []*string{aws.String(service)})
+	if ecsx.IsMissing(err) || (err == nil && len(described) == 0) {
+		err = errors.New("Service informed not found")
+	}
+	if err != nil {
+		return
+	}
+
+	s = described[0]
+	return
+}
+
+// The freeze is advisory, it is written as tags on the service and only ecsctl enforces it
+const (
+	freezeTagKey   = "ecsctl:frozen"
+	frozenByTagKey = "ecsctl:frozen-by"
+	frozenAtTagKey = "ecsctl:frozen-at"
+)
+
+// checkServiceFreeze fails when the service is frozen, unless the freeze is overridden
+func checkServiceFreeze(cluster, service string, override bool) (err error) {
+	described, err := ecsxI.DescribeAllServices(cluster, []*string{aws.String(service)}, ecs.ServiceFieldTags)
+	if err != nil || len(described) == 0 {
+		return
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range described[0].Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	reason, frozen := tags[freezeTagKey]
+	if !frozen {
+		return
+	}
+
+	message := fmt.Sprintf("Service %s is frozen: %s (by %s at %s)", service, reason, tags[frozenByTagKey], tags[frozenAtTagKey])
+	if override {
+		typist.Printf("warning: %s\n", message)
+		return
+	}
+
+	return errors.New(message + "\nUse --override-freeze to proceed anyway")
+}
+
 func servicesRun(cmd *cobra.Command, args []string) {
 	cmd.Help()
 }
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesCapacityCheckOptions struct {
+	cluster        string
+	taskDefinition string
+}
+
+var servicesCapacityCheckOpts servicesCapacityCheckOptions
+
+// capacityCheck is the room the rollout of a service needs on the container instances and the room they have
+type capacityCheck struct {
+	desired       int64
+	surge         int64
+	stopFirst     int64
+	requirements  taskRequirements
+	instances     int
+	fits          int64
+	perInstance   int64
+	short         int64
+	portConflicts []string
+}
+
+// canProceed tells if the surge fits, or if the rollout can make room by stopping old tasks first
+func (c *capacityCheck) canProceed() bool {
+	return c.fits >= c.surge || c.stopFirst > 0
+}
+
+// tasksFitting is how many tasks fit in the cpu and memory, up to limit. Host ports fit a single task.
+func tasksFitting(cpu, memory int64, r taskRequirements, limit int64) int64 {
+	fits := limit
+	if r.cpu > 0 && cpu/r.cpu < fits {
+		fits = cpu / r.cpu
+	}
+
+	if r.memory > 0 && memory/r.memory < fits {
+		fits = memory / r.memory
+	}
+
+	if len(r.ports) > 0 && fits > 1 {
+		fits = 1
+	}
+	return fits
+}
+
+// checkCapacity computes the tasks started on top of the desired ones during the rollout (maximumPercent)
+// and how many of them the remaining resources of the container instances can take
+func checkCapacity(cluster string, s *ecs.Service, td *ecs.TaskDefinition) (c capacityCheck, err error) {
+	c.desired = aws.Int64Value(s.DesiredCount)
+	c.requirements = requirementsOf(td)
+
+	maximumPercent, minimumHealthyPercent := int64(200), int64(100)
+	if dc := s.DeploymentConfiguration; dc != nil {
+		if dc.MaximumPercent != nil {
+			maximumPercent = aws.Int64Value(dc.MaximumPercent)
+		}
+		if dc.MinimumHealthyPercent != nil {
+			minimumHealthyPercent = aws.Int64Value(dc.MinimumHealthyPercent)
+		}
+	}
+
+	// ECS rounds the maximum down and the minimum up
+	c.surge = c.desired*maximumPercent/100 - c.desired
+	c.stopFirst = c.desired - (c.desired*minimumHealthyPercent+99)/100
+
+	instances, err := describeContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	var largestCPU, largestMemory int64
+	for _, ci := range instances {
+		if aws.StringValue(ci.Status) != ecs.ContainerInstanceStatusActive || !aws.BoolValue(ci.AgentConnected) {
+			continue
+		}
+		c.instances++
+
+		if cpu := registeredResource(ci, "CPU"); cpu > largestCPU {
+			largestCPU = cpu
+		}
+		if memory := registeredResource(ci, "MEMORY"); memory > largestMemory {
+			largestMemory = memory
+		}
+
+		var conflicts []string
+		for _, problem := range placementProblems(ci, c.requirements) {
+			if strings.HasPrefix(problem, "host port") {
+				conflicts = append(conflicts, problem)
+			}
+		}
+
+		if len(conflicts) > 0 {
+			for _, conflict := range conflicts {
+				c.portConflicts = append(c.portConflicts, aws.StringValue(ci.Ec2InstanceId)+": "+conflict)
+			}
+			continue
+		}
+
+		c.fits += tasksFitting(remainingResource(ci, "CPU"), remainingResource(ci, "MEMORY"), c.requirements, c.surge)
+	}
+
+	c.perInstance = tasksFitting(largestCPU, largestMemory, c.requirements, c.surge)
+	if missing := c.surge - c.fits; missing > 0 && c.perInstance > 0 {
+		c.short = (missing + c.perInstance - 1) / c.perInstance
+	}
+	return
+}
+
+func printCapacityCheck(service string, c capacityCheck) {
+	if quiet {
+		return
+	}
+
+	r := c.requirements
+	typist.Printf("%s: %d desired, %d surge tasks of %d cpu and %d memory during the rollout\n", service, c.desired, c.surge, r.cpu, r.memory)
+	room := c.fits
+	if room > c.surge {
+		room = c.surge
+	}
+	typist.Printf("room for %d of them on %d active container instances\n", room, c.instances)
+
+	for _, conflict := range c.portConflicts {
+		typist.Printf("port conflict on %s\n", conflict)
+	}
+
+	switch {
+	case c.fits >= c.surge:
+		typist.Println("the rollout can proceed")
+	case c.stopFirst > 0:
+		typist.Printf("the rollout can proceed by stopping up to %d old tasks first (minimumHealthyPercent), running below the desired count meanwhile\n", c.stopFirst)
+	}
+
+	if c.short > 0 {
+		typist.Printf("%d more instances of the largest size are needed for the whole surge\n", c.short)
+	}
+}
+
+// runCapacityCheck prints the check and fails when the rollout can not proceed
+func runCapacityCheck(cluster string, s *ecs.Service, td *ecs.TaskDefinition) error {
+	if aws.StringValue(s.LaunchType) == ecs.LaunchTypeFargate || aws.StringValue(s.SchedulingStrategy) == ecs.SchedulingStrategyDaemon {
+		fmt.Fprintf(os.Stderr, "warning: %s does not run on a capacity of its own to check (FARGATE or DAEMON)\n", aws.StringValue(s.ServiceName))
+		return nil
+	}
+
+	c, err := checkCapacity(cluster, s, td)
+	if err != nil {
+		return err
+	}
+
+	printCapacityCheck(aws.StringValue(s.ServiceName), c)
+
+	if !c.canProceed() {
+		return fmt.Errorf("The cluster has room for %d of the %d surge tasks of %s, and minimumHealthyPercent does not allow stopping old tasks first", c.fits, c.surge, aws.StringValue(s.ServiceName))
+	}
+	return nil
+}
+
+func servicesCapacityCheckRun(cmd *cobra.Command, args []string) {
+	opts := &servicesCapacityCheckOpts
+
+	s, err := describeService(opts.cluster, args[0])
+	must(err)
+
+	target := aws.StringValue(s.TaskDefinition)
+	if opts.taskDefinition != "" {
+		target = opts.taskDefinition
+	}
+
+	td, err := describeTaskDefinition(target)
+	if err != nil {
+		must(errors.New("Task Definition " + target + " not found"))
+	}
+
+	must(runCapacityCheck(opts.cluster, s, td))
+}
+
+var servicesCapacityCheckCmd = &cobra.Command{
+	Use:   "capacity-check [service]",
+	Short: "Check the container instances have room for the extra tasks of a rollout of the service",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesCapacityCheckRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesCapacityCheckCmd)
+
+	flags := servicesCapacityCheckCmd.Flags()
+
+	flags.StringVarP(&servicesCapacityCheckOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesCapacityCheckOpts.taskDefinition, "task-definition", "", capacityTaskDefinitionSpec)
+
+	servicesCapacityCheckCmd.MarkFlagRequired("cluster")
+}
@@ -2,64 +2,41 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
-	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
-func servicesCopyRun(cmd *cobra.Command, services []string) {
-	targetClustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: []*string{
-			aws.String(toCluster),
-		},
-	})
-
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
-	}
-
-	if len(targetClustersDescription.Clusters) == 0 {
-		fmt.Println(errors.New("Target Cluster informed not found"))
-		os.Exit(1)
-	}
+type servicesCopyOptions struct {
+	cluster   string
+	toCluster string
+}
 
-	targetC := targetClustersDescription.Clusters[0]
+var servicesCopyOpts servicesCopyOptions
 
-	clustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: []*string{
-			aws.String(cluster),
-		},
-	})
+func servicesCopyRun(cmd *cobra.Command, services []string) {
+	opts := &servicesCopyOpts
 
+	targetC, err := describeCluster(opts.toCluster)
 	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+		must(errors.New("Target Cluster informed not found"))
 	}
 
-	if len(clustersDescription.Clusters) == 0 {
-		fmt.Println(errors.New("Source Cluster informed not found"))
-		os.Exit(1)
+	c, err := describeCluster(opts.cluster)
+	if err != nil {
+		must(errors.New("Source Cluster informed not found"))
 	}
 
-	c := clustersDescription.Clusters[0]
-
-	servicesDescription, err := ecsI.DescribeServices(&ecs.DescribeServicesInput{
-		Cluster:  c.ClusterName,
-		Services: aws.StringSlice(services),
-	})
-
-	if len(servicesDescription.Services) < len(services) {
-		fmt.Println(errors.New("One or more services informed was not found"))
-		os.Exit(1)
+	described, err := ecsxI.DescribeAllServices(aws.StringValue(c.ClusterName), aws.StringSlice(services), ecs.ServiceFieldTags)
+	if ecsx.IsMissing(err) {
+		must(errors.New("One or more services informed was not found"))
 	}
+	must(err)
 
-	for _, s := range servicesDescription.Services {
-		ecsI.CreateService(&ecs.CreateServiceInput{
+	for _, s := range described {
+		result, err := ecsI.CreateService(&ecs.CreateServiceInput{
 			Cluster:                       targetC.ClusterName,
 			DeploymentConfiguration:       s.DeploymentConfiguration,
 			DesiredCount:                  s.DesiredCount,
@@ -75,7 +52,11 @@ func servicesCopyRun(cmd *cobra.Command, services []string) {
 			ServiceName:                   s.ServiceName,
 			ServiceRegistries:             s.ServiceRegistries,
 			TaskDefinition:                s.TaskDefinition,
+			Tags:                          resourceTags(s.Tags),
 		})
+		must(err)
+
+		printAffected(aws.StringValue(result.Service.ServiceArn), aws.StringValue(s.ServiceName)+" copied to "+opts.toCluster)
 	}
 }
 
@@ -91,11 +72,9 @@ func init() {
 
 	flags := servicesCopyCmd.Flags()
 
-	flags.StringVarP(&toCluster, "to-cluster", "t", "", requiredSpec+toClusterSpec)
-	flags.StringVarP(&cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVarP(&servicesCopyOpts.toCluster, "to-cluster", "t", "", requiredSpec+toClusterSpec)
+	flags.StringVarP(&servicesCopyOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
 
 	servicesCopyCmd.MarkFlagRequired("cluster")
 	servicesCopyCmd.MarkFlagRequired("to-cluster")
-
-	viper.BindPFlag("cluster", servicesCopyCmd.Flags().Lookup("cluster"))
 }
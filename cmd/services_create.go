@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesCreateOptions struct {
+	cluster          string
+	fromTask         string
+	desired          int64
+	registerOverride bool
+	dropOverrides    bool
+	targetGroup      string
+	containerPort    int64
+	yes              bool
+}
+
+var servicesCreateOpts servicesCreateOptions
+
+// hasOverrides tells if the task was run with overrides, which services do not support
+func hasOverrides(o *ecs.TaskOverride) bool {
+	if o == nil {
+		return false
+	}
+
+	if o.Cpu != nil || o.Memory != nil || o.TaskRoleArn != nil || o.ExecutionRoleArn != nil || o.EphemeralStorage != nil {
+		return true
+	}
+
+	for _, co := range o.ContainerOverrides {
+		if len(co.Command) > 0 || len(co.Environment) > 0 || len(co.EnvironmentFiles) > 0 || co.Cpu != nil ||
+			co.Memory != nil || co.MemoryReservation != nil || len(co.ResourceRequirements) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// applyOverrides bakes the overrides of a task into a copy of its Task Definition, to be registered as a new revision
+func applyOverrides(td *ecs.TaskDefinition, o *ecs.TaskOverride) (input *ecs.RegisterTaskDefinitionInput, err error) {
+	for _, co := range o.ContainerOverrides {
+		if len(co.EnvironmentFiles) > 0 || len(co.ResourceRequirements) > 0 {
+			return nil, fmt.Errorf("environment files and resource requirements overrides of %s can not be carried over", aws.StringValue(co.Name))
+		}
+	}
+
+	input = &ecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions:    td.ContainerDefinitions,
+		Cpu:                     td.Cpu,
+		ExecutionRoleArn:        td.ExecutionRoleArn,
+		Family:                  td.Family,
+		Memory:                  td.Memory,
+		NetworkMode:             td.NetworkMode,
+		PlacementConstraints:    td.PlacementConstraints,
+		RequiresCompatibilities: td.RequiresCompatibilities,
+		TaskRoleArn:             td.TaskRoleArn,
+		Volumes:                 td.Volumes,
+		EphemeralStorage:        td.EphemeralStorage,
+	}
+
+	if o.Cpu != nil {
+		input.Cpu = o.Cpu
+	}
+	if o.Memory != nil {
+		input.Memory = o.Memory
+	}
+	if o.TaskRoleArn != nil {
+		input.TaskRoleArn = o.TaskRoleArn
+	}
+	if o.ExecutionRoleArn != nil {
+		input.ExecutionRoleArn = o.ExecutionRoleArn
+	}
+	if o.EphemeralStorage != nil {
+		input.EphemeralStorage = o.EphemeralStorage
+	}
+
+	for _, co := range o.ContainerOverrides {
+		cd, err := containerDefinition(td, aws.StringValue(co.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(co.Command) > 0 {
+			cd.Command = co.Command
+		}
+		if co.Cpu != nil {
+			cd.Cpu = co.Cpu
+		}
+		if co.Memory != nil {
+			cd.Memory = co.Memory
+		}
+		if co.MemoryReservation != nil {
+			cd.MemoryReservation = co.MemoryReservation
+		}
+
+		// overridden variables replace the ones of the same name
+		for _, kv := range co.Environment {
+			replaced := false
+			for _, existing := range cd.Environment {
+				if aws.StringValue(existing.Name) == aws.StringValue(kv.Name) {
+					existing.Value = kv.Value
+					replaced = true
+				}
+			}
+			if !replaced {
+				cd.Environment = append(cd.Environment, kv)
+			}
+		}
+	}
+	return
+}
+
+// taskNetworkConfiguration rebuilds the awsvpc configuration of a task from its ENI
+func taskNetworkConfiguration(t *ecs.Task) (config *ecs.NetworkConfiguration, err error) {
+	var subnetID, eniID string
+	for _, attachment := range t.Attachments {
+		for _, detail := range attachment.Details {
+			switch aws.StringValue(detail.Name) {
+			case "subnetId":
+				subnetID = aws.StringValue(detail.Value)
+			case "networkInterfaceId":
+				eniID = aws.StringValue(detail.Value)
+			}
+		}
+	}
+
+	if subnetID == "" {
+		return
+	}
+
+	awsvpc := &ecs.AwsVpcConfiguration{
+		Subnets:        []*string{aws.String(subnetID)},
+		AssignPublicIp: aws.String(ecs.AssignPublicIpDisabled),
+	}
+
+	// The ENI of a stopped task may be gone already, the security groups must then be informed on the input
+	if eniID != "" {
+		result, err := ec2I.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+			NetworkInterfaceIds: []*string{aws.String(eniID)},
+		})
+		if err == nil && len(result.NetworkInterfaces) > 0 {
+			eni := result.NetworkInterfaces[0]
+			for _, group := range eni.Groups {
+				awsvpc.SecurityGroups = append(awsvpc.SecurityGroups, group.GroupId)
+			}
+			if eni.Association != nil && eni.Association.PublicIp != nil {
+				awsvpc.AssignPublicIp = aws.String(ecs.AssignPublicIpEnabled)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: the network interface %s of the task is gone, the service is created without security groups (the default of the VPC)\n", eniID)
+		}
+	}
+
+	config = &ecs.NetworkConfiguration{AwsvpcConfiguration: awsvpc}
+	return
+}
+
+func servicesCreateRun(cmd *cobra.Command, args []string) {
+	opts := &servicesCreateOpts
+	name := args[0]
+
+	if opts.registerOverride && opts.dropOverrides {
+		must(errors.New("--register-overrides and --drop-overrides can not be used together"))
+	}
+
+	if (opts.targetGroup == "") != (opts.containerPort == 0) {
+		must(errors.New("--target-group and --container-port must be informed together"))
+	}
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(opts.fromTask)})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+	t := tasks[0]
+
+	td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+	must(err)
+
+	taskDefinition := t.TaskDefinitionArn
+	if hasOverrides(t.Overrides) {
+		switch {
+		case opts.dropOverrides:
+			fmt.Fprintln(os.Stderr, "warning: the overrides the task was run with are dropped, the service runs the Task Definition as registered")
+		case opts.registerOverride:
+			input, err := applyOverrides(td, t.Overrides)
+			must(err)
+
+			content, err := json.MarshalIndent(t.Overrides, "", "  ")
+			must(err)
+			typist.Printf("The overrides of the task are registered as a new revision of %s:\n%s\n", aws.StringValue(td.Family), content)
+
+			if !opts.yes && !typist.Confirm("Register it?") {
+				return
+			}
+
+			input.Tags = resourceTags(nil)
+			registered, err := ecsI.RegisterTaskDefinition(input)
+			must(err)
+
+			taskDefinition = registered.TaskDefinition.TaskDefinitionArn
+		default:
+			must(errors.New("The task was run with overrides (command, environment or resources), which services do not support\nUse --register-overrides to register them as a new revision, or --drop-overrides to ignore them"))
+		}
+	}
+
+	networkConfiguration, err := taskNetworkConfiguration(t)
+	must(err)
+
+	input := &ecs.CreateServiceInput{
+		Cluster:              aws.String(opts.cluster),
+		ServiceName:          aws.String(name),
+		TaskDefinition:       taskDefinition,
+		DesiredCount:         aws.Int64(opts.desired),
+		NetworkConfiguration: networkConfiguration,
+		EnableExecuteCommand: t.EnableExecuteCommand,
+		Tags:                 resourceTags(nil),
+	}
+
+	if t.CapacityProviderName != nil {
+		input.CapacityProviderStrategy = []*ecs.CapacityProviderStrategyItem{
+			{CapacityProvider: t.CapacityProviderName, Weight: aws.Int64(1)},
+		}
+	} else {
+		input.LaunchType = t.LaunchType
+	}
+
+	if aws.StringValue(t.LaunchType) == ecs.LaunchTypeFargate {
+		input.PlatformVersion = t.PlatformVersion
+	}
+
+	if opts.targetGroup != "" {
+		var container *string
+		for _, cd := range td.ContainerDefinitions {
+			for _, pm := range cd.PortMappings {
+				if aws.Int64Value(pm.ContainerPort) == opts.containerPort {
+					container = cd.Name
+				}
+			}
+		}
+
+		if container == nil {
+			must(fmt.Errorf("No container of %s maps the port %d", familyRevision(td.Family, td.Revision), opts.containerPort))
+		}
+
+		input.LoadBalancers = []*ecs.LoadBalancer{{
+			TargetGroupArn: aws.String(opts.targetGroup),
+			ContainerName:  container,
+			ContainerPort:  aws.Int64(opts.containerPort),
+		}}
+	} else {
+		fmt.Fprintln(os.Stderr, "note: a one-off task has no load balancer, the service is created without one (use --target-group and --container-port)")
+	}
+
+	content, err := json.MarshalIndent(input, "", "  ")
+	must(err)
+	typist.Printf("%s\n", content)
+
+	if !opts.yes && !typist.Confirm("Create the service?") {
+		return
+	}
+
+	result, err := ecsI.CreateService(input)
+	must(err)
+
+	printAffected(aws.StringValue(result.Service.ServiceArn), name+" created from task "+taskID(aws.StringValue(t.TaskArn)))
+}
+
+var servicesCreateCmd = &cobra.Command{
+	Use:   "create [service]",
+	Short: "Create a service from a task run before, with its Task Definition, launch type and network",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesCreateRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesCreateCmd)
+
+	flags := servicesCreateCmd.Flags()
+
+	flags.StringVarP(&servicesCreateOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesCreateOpts.fromTask, "from-task", "", requiredSpec+fromTaskSpec)
+	flags.Int64Var(&servicesCreateOpts.desired, "desired", 1, desiredCountSpec)
+	flags.BoolVar(&servicesCreateOpts.registerOverride, "register-overrides", false, registerOverridesSpec)
+	flags.BoolVar(&servicesCreateOpts.dropOverrides, "drop-overrides", false, dropOverridesSpec)
+	flags.StringVar(&servicesCreateOpts.targetGroup, "target-group", "", createTargetGroupArnSpec)
+	flags.Int64Var(&servicesCreateOpts.containerPort, "container-port", 0, containerPortSpec)
+	flags.BoolVarP(&servicesCreateOpts.yes, "yes", "y", false, yesSpec)
+
+	servicesCreateCmd.MarkFlagRequired("cluster")
+	servicesCreateCmd.MarkFlagRequired("from-task")
+}
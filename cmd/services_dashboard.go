@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/spf13/cobra"
+)
+
+type servicesDashboardOptions struct {
+	cluster string
+	name    string
+	create  bool
+	print   bool
+}
+
+var servicesDashboardOpts servicesDashboardOptions
+
+type dashboardWidget struct {
+	Type       string                 `json:"type"`
+	X          int                    `json:"x"`
+	Y          int                    `json:"y"`
+	Width      int                    `json:"width"`
+	Height     int                    `json:"height"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type dashboardBody struct {
+	Widgets []dashboardWidget `json:"widgets"`
+}
+
+// add places the widget on a grid of two widgets per row
+func (d *dashboardBody) add(widgetType string, properties map[string]interface{}) {
+	i := len(d.Widgets)
+	d.Widgets = append(d.Widgets, dashboardWidget{
+		Type:       widgetType,
+		X:          (i % 2) * 12,
+		Y:          (i / 2) * 6,
+		Width:      12,
+		Height:     6,
+		Properties: properties,
+	})
+}
+
+func metricWidget(title, region, stat string, metrics [][]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"title":   title,
+		"region":  region,
+		"stat":    stat,
+		"period":  60,
+		"view":    "timeSeries",
+		"metrics": metrics,
+	}
+}
+
+// serviceLoadBalancers finds the load balancer of each target group of the service,
+// as the "app/name/id" and "targetgroup/name/id" CloudWatch dimensions
+func serviceLoadBalancers(targetGroupArns []*string) (dimensions [][2]string, err error) {
+	if len(targetGroupArns) == 0 {
+		return
+	}
+
+	result, err := elbv2I.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: targetGroupArns,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, tg := range result.TargetGroups {
+		tgDimension := aws.StringValue(tg.TargetGroupArn)
+		tgDimension = tgDimension[strings.Index(tgDimension, "targetgroup/"):]
+
+		for _, lbArn := range aws.StringValueSlice(tg.LoadBalancerArns) {
+			lbDimension := lbArn[strings.Index(lbArn, "loadbalancer/")+len("loadbalancer/"):]
+			dimensions = append(dimensions, [2]string{lbDimension, tgDimension})
+		}
+	}
+	return
+}
+
+// serviceDashboard builds the widgets: utilization, running tasks, load balancer 5xx and latency, and log errors
+func serviceDashboard(cluster, service string) (body dashboardBody, err error) {
+	region := aws.StringValue(awsSession.Config.Region)
+
+	s, err := describeService(cluster, service)
+	if err != nil {
+		return
+	}
+
+	clusterName := cluster[strings.LastIndex(cluster, "/")+1:]
+
+	body.add("metric", metricWidget(service+" CPU and memory utilization", region, "Average", [][]interface{}{
+		{"AWS/ECS", "CPUUtilization", "ClusterName", clusterName, "ServiceName", service},
+		{"AWS/ECS", "MemoryUtilization", "ClusterName", clusterName, "ServiceName", service},
+	}))
+
+	// RunningTaskCount is only published with Container Insights enabled on the cluster
+	body.add("metric", metricWidget(service+" running tasks", region, "Average", [][]interface{}{
+		{"ECS/ContainerInsights", "RunningTaskCount", "ClusterName", clusterName, "ServiceName", service},
+		{"ECS/ContainerInsights", "DesiredTaskCount", "ClusterName", clusterName, "ServiceName", service},
+	}))
+
+	var targetGroupArns []*string
+	for _, lb := range s.LoadBalancers {
+		if lb.TargetGroupArn != nil {
+			targetGroupArns = append(targetGroupArns, lb.TargetGroupArn)
+		}
+	}
+
+	lbs, err := serviceLoadBalancers(targetGroupArns)
+	if err != nil {
+		return
+	}
+
+	for _, lb := range lbs {
+		body.add("metric", metricWidget(service+" 5xx", region, "Sum", [][]interface{}{
+			{"AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", "LoadBalancer", lb[0], "TargetGroup", lb[1]},
+			{"AWS/ApplicationELB", "HTTPCode_ELB_5XX_Count", "LoadBalancer", lb[0]},
+		}))
+
+		body.add("metric", metricWidget(service+" latency", region, "p99", [][]interface{}{
+			{"AWS/ApplicationELB", "TargetResponseTime", "LoadBalancer", lb[0], "TargetGroup", lb[1], map[string]string{"stat": "p50"}},
+			{"AWS/ApplicationELB", "TargetResponseTime", "LoadBalancer", lb[0], "TargetGroup", lb[1], map[string]string{"stat": "p99"}},
+		}))
+	}
+
+	td, err := describeTaskDefinition(aws.StringValue(s.TaskDefinition))
+	if err != nil {
+		return
+	}
+
+	groups := make(map[string]bool)
+	var sources []string
+	for _, stream := range taskLogStreams(td, "") {
+		if !groups[stream.group] {
+			groups[stream.group] = true
+			sources = append(sources, "SOURCE '"+stream.group+"'")
+		}
+	}
+
+	if len(sources) > 0 {
+		body.add("log", map[string]interface{}{
+			"title":  service + " log errors per minute",
+			"region": region,
+			"view":   "timeSeries",
+			"query":  strings.Join(sources, " | ") + " | filter @message like /(?i)error/ | stats count(*) as errors by bin(1m)",
+		})
+	}
+
+	return
+}
+
+func servicesDashboardRun(cmd *cobra.Command, args []string) {
+	opts := &servicesDashboardOpts
+	service := args[0]
+
+	if opts.create == opts.print {
+		must(errors.New("Inform either --create or --print"))
+	}
+
+	body, err := serviceDashboard(opts.cluster, service)
+	must(err)
+
+	if opts.print {
+		output, err := json.MarshalIndent(body, "", "  ")
+		must(err)
+		fmt.Println(string(output))
+		return
+	}
+
+	name := opts.name
+	if name == "" {
+		name = opts.cluster[strings.LastIndex(opts.cluster, "/")+1:] + "-" + service
+	}
+
+	content, err := json.Marshal(body)
+	must(err)
+
+	result, err := cwI.PutDashboard(&cloudwatch.PutDashboardInput{
+		DashboardName: aws.String(name),
+		DashboardBody: aws.String(string(content)),
+	})
+	must(err)
+
+	for _, message := range result.DashboardValidationMessages {
+		fmt.Fprintf(os.Stderr, "warning: %s %s\n", aws.StringValue(message.DataPath), aws.StringValue(message.Message))
+	}
+
+	region := aws.StringValue(awsSession.Config.Region)
+	printAffected(name, fmt.Sprintf("https://%s.console.aws.amazon.com/cloudwatch/home?region=%s#dashboards:name=%s", region, region, name))
+}
+
+var servicesDashboardCmd = &cobra.Command{
+	Use:   "dashboard [service]",
+	Short: "Create a CloudWatch dashboard for a service",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesDashboardRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesDashboardCmd)
+
+	flags := servicesDashboardCmd.Flags()
+
+	flags.StringVarP(&servicesDashboardOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesDashboardOpts.name, "name", "", dashboardNameSpec)
+	flags.BoolVar(&servicesDashboardOpts.create, "create", false, createDashboardSpec)
+	flags.BoolVar(&servicesDashboardOpts.print, "print", false, printDashboardSpec)
+
+	servicesDashboardCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/spf13/cobra"
+)
+
+type servicesDecommissionOptions struct {
+	cluster        string
+	keepTaskDef    bool
+	plan           bool
+	timeout        time.Duration
+	yes            bool
+	overrideFreeze bool
+}
+
+var servicesDecommissionOpts servicesDecommissionOptions
+
+// registryInstances are the Cloud Map instances of the tasks of the service in a registry, with the longest TTL
+// of its DNS records. Registries may be shared (e.g. by services move), the instances of others are left.
+type registryInstances struct {
+	serviceID string
+	name      string
+	instances []string
+	others    int
+	ttl       time.Duration
+}
+
+// groupTargets are the targets of the tasks of the service in a target group, which may be shared as well
+type groupTargets struct {
+	targetGroupArn string
+	targets        []*elbv2.TargetDescription
+	others         int
+}
+
+// decommissionPlan is everything the decommission removes, read before acting
+type decommissionPlan struct {
+	service        *ecs.Service
+	registries     []registryInstances
+	targetGroups   []groupTargets
+	taskDefinition string
+}
+
+// registryServiceID is the ID of the Cloud Map service of a registry ARN, .../service/srv-xxx
+func registryServiceID(registryArn string) string {
+	return registryArn[strings.LastIndex(registryArn, "/")+1:]
+}
+
+func listRegistryInstances(serviceID string) (ids []string, err error) {
+	err = sdI.ListInstancesPages(&servicediscovery.ListInstancesInput{
+		ServiceId: aws.String(serviceID),
+	}, func(page *servicediscovery.ListInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.Instances {
+			ids = append(ids, aws.StringValue(instance.Id))
+		}
+		return !lastPage
+	})
+	return
+}
+
+func registeredTargets(targetGroupArn string) (targets []*elbv2.TargetDescription, err error) {
+	health, err := elbv2I.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(targetGroupArn),
+	})
+	if err != nil {
+		return
+	}
+
+	for _, description := range health.TargetHealthDescriptions {
+		targets = append(targets, description.Target)
+	}
+	return
+}
+
+// ownInstances keeps the instances of the tasks, ECS registers each task with its ID as the instance ID
+func ownInstances(ids []string, taskIDs map[string]bool) (own []string) {
+	for _, id := range ids {
+		if taskIDs[id] {
+			own = append(own, id)
+		}
+	}
+	return
+}
+
+// ownTargets keeps the targets of the tasks related by the keys
+func ownTargets(targets []*elbv2.TargetDescription, keys map[string]string) (own []*elbv2.TargetDescription) {
+	for _, target := range targets {
+		if targetTask(keys, target) != "" {
+			own = append(own, target)
+		}
+	}
+	return
+}
+
+func planDecommission(cluster string, s *ecs.Service, keepTaskDef bool) (plan decommissionPlan, err error) {
+	plan.service = s
+
+	tasks, err := serviceTasks(cluster, aws.StringValue(s.ServiceName), ecs.DesiredStatusRunning)
+	if err != nil {
+		return
+	}
+
+	taskIDs := make(map[string]bool)
+	for _, t := range tasks {
+		taskIDs[taskID(aws.StringValue(t.TaskArn))] = true
+	}
+
+	keys, err := targetKeys(cluster, tasks)
+	if err != nil {
+		return
+	}
+
+	for _, registry := range s.ServiceRegistries {
+		r := registryInstances{serviceID: registryServiceID(aws.StringValue(registry.RegistryArn))}
+
+		described, err := sdI.GetService(&servicediscovery.GetServiceInput{Id: aws.String(r.serviceID)})
+		if err != nil {
+			return plan, err
+		}
+
+		r.name = aws.StringValue(described.Service.Name)
+		if dns := described.Service.DnsConfig; dns != nil {
+			for _, record := range dns.DnsRecords {
+				if ttl := time.Duration(aws.Int64Value(record.TTL)) * time.Second; ttl > r.ttl {
+					r.ttl = ttl
+				}
+			}
+		}
+
+		instances, err := listRegistryInstances(r.serviceID)
+		if err != nil {
+			return plan, err
+		}
+
+		r.instances = ownInstances(instances, taskIDs)
+		r.others = len(instances) - len(r.instances)
+
+		plan.registries = append(plan.registries, r)
+	}
+
+	seen := make(map[string]bool)
+	for _, lb := range s.LoadBalancers {
+		arn := aws.StringValue(lb.TargetGroupArn)
+		if arn == "" || seen[arn] {
+			continue
+		}
+		seen[arn] = true
+
+		targets, err := registeredTargets(arn)
+		if err != nil {
+			return plan, err
+		}
+
+		own := ownTargets(targets, keys)
+		plan.targetGroups = append(plan.targetGroups, groupTargets{targetGroupArn: arn, targets: own, others: len(targets) - len(own)})
+	}
+
+	if !keepTaskDef {
+		plan.taskDefinition = aws.StringValue(s.TaskDefinition)
+	}
+	return
+}
+
+func formatTarget(target *elbv2.TargetDescription) string {
+	return fmt.Sprintf("%s:%d", aws.StringValue(target.Id), aws.Int64Value(target.Port))
+}
+
+func printDecommissionPlan(plan decommissionPlan) {
+	s := plan.service
+	service := aws.StringValue(s.ServiceName)
+
+	typist.Printf("1. deregister the Cloud Map instances of %s\n", service)
+	if len(plan.registries) == 0 {
+		typist.Println("   no service registries")
+	}
+	for _, r := range plan.registries {
+		typist.Printf("   %s (%s): %d instances, then wait the DNS TTL of %s\n", r.name, r.serviceID, len(r.instances), r.ttl)
+		if r.others > 0 {
+			typist.Printf("   %d instances of other services are kept\n", r.others)
+		}
+	}
+
+	typist.Printf("2. deregister the targets of %s and wait for them to drain\n", service)
+	if len(plan.targetGroups) == 0 {
+		typist.Println("   no target groups")
+	}
+	for _, tg := range plan.targetGroups {
+		typist.Printf("   %s: %d targets\n", tg.targetGroupArn, len(tg.targets))
+		if tg.others > 0 {
+			typist.Printf("   %d targets of other services are kept\n", tg.others)
+		}
+	}
+
+	typist.Printf("3. scale %s from %d to 0 tasks\n", service, aws.Int64Value(s.DesiredCount))
+	typist.Printf("4. delete %s\n", aws.StringValue(s.ServiceArn))
+
+	if plan.taskDefinition != "" {
+		typist.Printf("5. deregister %s\n", plan.taskDefinition)
+	} else {
+		typist.Printf("5. keep %s (--keep-taskdef)\n", aws.StringValue(s.TaskDefinition))
+	}
+}
+
+// waitUntil polls the check until it is done, the timeout passes or the command is interrupted
+func waitUntil(ctx context.Context, timeout time.Duration, what string, check func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out after %s waiting for %s", timeout, what)
+		}
+
+		if !sleepContext(ctx, 5*time.Second) {
+			return fmt.Errorf("Interrupted while waiting for %s", what)
+		}
+	}
+}
+
+func deregisterRegistryInstances(ctx context.Context, r registryInstances, timeout time.Duration) (removed []string, err error) {
+	for _, id := range r.instances {
+		_, err = sdI.DeregisterInstance(&servicediscovery.DeregisterInstanceInput{
+			ServiceId:  aws.String(r.serviceID),
+			InstanceId: aws.String(id),
+		})
+		if err != nil {
+			return
+		}
+
+		removed = append(removed, fmt.Sprintf("Cloud Map instance %s of %s", id, r.name))
+	}
+
+	taskIDs := make(map[string]bool)
+	for _, id := range r.instances {
+		taskIDs[id] = true
+	}
+
+	// The deregistration is asynchronous, the instances only disappear once it is done
+	err = waitUntil(ctx, timeout, "the Cloud Map instances of "+r.name+" to be deregistered", func() (bool, error) {
+		remaining, err := listRegistryInstances(r.serviceID)
+		return len(ownInstances(remaining, taskIDs)) == 0, err
+	})
+	return
+}
+
+func deregisterGroupTargets(ctx context.Context, tg groupTargets, timeout time.Duration) (removed []string, err error) {
+	if len(tg.targets) > 0 {
+		_, err = elbv2I.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+			TargetGroupArn: aws.String(tg.targetGroupArn),
+			Targets:        tg.targets,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	for _, target := range tg.targets {
+		removed = append(removed, fmt.Sprintf("target %s of %s", formatTarget(target), tg.targetGroupArn))
+	}
+
+	keys := make(map[string]string)
+	for _, target := range tg.targets {
+		keys[formatTarget(target)] = formatTarget(target)
+	}
+
+	// Draining targets are still listed until their deregistration delay is over
+	err = waitUntil(ctx, timeout, "the targets of "+tg.targetGroupArn+" to drain", func() (bool, error) {
+		registered, err := registeredTargets(tg.targetGroupArn)
+		remaining := ownTargets(registered, keys)
+		if len(remaining) > 0 && err == nil && !quiet {
+			fmt.Fprintf(os.Stderr, "%d targets still draining from %s\n", len(remaining), tg.targetGroupArn)
+		}
+		return len(remaining) == 0, err
+	})
+	return
+}
+
+func decommissionService(cluster string, plan decommissionPlan, timeout time.Duration) (removed []string) {
+	ctx := interruptContext()
+	s := plan.service
+	service := aws.StringValue(s.ServiceName)
+
+	// Each phase is verified before the next one, whatever was removed so far is reported on a failure
+	fail := func(err error) {
+		if err == nil {
+			return
+		}
+
+		for _, r := range removed {
+			fmt.Fprintf(os.Stderr, "removed %s\n", r)
+		}
+		must(err)
+	}
+
+	typist.Println("phase 1: Cloud Map instances")
+	var ttl time.Duration
+	for _, r := range plan.registries {
+		deregistered, err := deregisterRegistryInstances(ctx, r, timeout)
+		removed = append(removed, deregistered...)
+		fail(err)
+
+		if r.ttl > ttl {
+			ttl = r.ttl
+		}
+	}
+
+	if ttl > 0 {
+		typist.Printf("waiting %s for the DNS TTL, so resolvers stop returning the tasks\n", ttl)
+		if !sleepContext(ctx, ttl) {
+			fail(fmt.Errorf("Interrupted while waiting for the DNS TTL"))
+		}
+	}
+
+	typist.Println("phase 2: load balancer targets")
+	for _, tg := range plan.targetGroups {
+		deregistered, err := deregisterGroupTargets(ctx, tg, timeout)
+		removed = append(removed, deregistered...)
+		fail(err)
+	}
+
+	typist.Println("phase 3: scale to 0")
+	if aws.Int64Value(s.DesiredCount) > 0 {
+		_, err := ecsI.UpdateService(&ecs.UpdateServiceInput{
+			Cluster:      aws.String(cluster),
+			Service:      s.ServiceName,
+			DesiredCount: aws.Int64(0),
+		})
+		fail(err)
+
+		failed, err := waitServices(cluster, []string{service}, timeout, false, false)
+		for _, f := range failed {
+			fmt.Fprintf(os.Stderr, "%s failed: %s\n", f.service, f.reason)
+		}
+		fail(err)
+
+		removed = append(removed, fmt.Sprintf("%d tasks of %s", aws.Int64Value(s.DesiredCount), service))
+	}
+
+	typist.Println("phase 4: delete the service")
+	_, err := ecsI.DeleteService(&ecs.DeleteServiceInput{
+		Cluster: aws.String(cluster),
+		Service: s.ServiceName,
+	})
+	fail(err)
+
+	fail(ecsI.WaitUntilServicesInactive(&ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []*string{s.ServiceName},
+	}))
+	removed = append(removed, "service "+aws.StringValue(s.ServiceArn))
+
+	if plan.taskDefinition != "" {
+		typist.Println("phase 5: deregister the task definition")
+		_, err := ecsI.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{
+			TaskDefinition: aws.String(plan.taskDefinition),
+		})
+		fail(err)
+
+		removed = append(removed, "task definition "+plan.taskDefinition)
+	}
+
+	return
+}
+
+func servicesDecommissionRun(cmd *cobra.Command, args []string) {
+	opts := &servicesDecommissionOpts
+
+	s, err := describeService(opts.cluster, args[0])
+	must(err)
+
+	plan, err := planDecommission(opts.cluster, s, opts.keepTaskDef)
+	must(err)
+
+	if opts.plan {
+		printDecommissionPlan(plan)
+		return
+	}
+
+	must(checkServiceFreeze(opts.cluster, aws.StringValue(s.ServiceName), opts.overrideFreeze))
+
+	if !opts.yes {
+		printDecommissionPlan(plan)
+		if !typist.Confirm("Do you really want to decommission " + aws.StringValue(s.ServiceName) + "?") {
+			return
+		}
+	}
+
+	removed := decommissionService(opts.cluster, plan, opts.timeout)
+
+	typist.Println("removed:")
+	for _, r := range removed {
+		typist.Printf("  %s\n", r)
+	}
+
+	printAffected(aws.StringValue(s.ServiceArn), aws.StringValue(s.ServiceName)+" decommissioned")
+}
+
+var servicesDecommissionCmd = &cobra.Command{
+	Use:   "decommission [service]",
+	Short: "Remove a service from Cloud Map and its target groups, then scale it down and delete it",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesDecommissionRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesDecommissionCmd)
+
+	flags := servicesDecommissionCmd.Flags()
+
+	flags.StringVarP(&servicesDecommissionOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&servicesDecommissionOpts.keepTaskDef, "keep-taskdef", false, keepTaskDefSpec)
+	flags.BoolVar(&servicesDecommissionOpts.plan, "plan", false, decommissionPlanSpec)
+	flags.DurationVar(&servicesDecommissionOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVarP(&servicesDecommissionOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVar(&servicesDecommissionOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesDecommissionCmd.MarkFlagRequired("cluster")
+
+	protectClusters(servicesDecommissionCmd, "decommission services", func(args []string) []string {
+		return []string{servicesDecommissionOpts.cluster}
+	})
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestOwnTargets(t *testing.T) {
+	// The tasks of the service: one with awsvpc, one with bridge on an instance shared with another service
+	keys := map[string]string{
+		"10.0.1.15":                 "a1",
+		"i-0123456789abcdef0:32768": "b2",
+	}
+
+	target := func(id string, port int64) *elbv2.TargetDescription {
+		return &elbv2.TargetDescription{Id: aws.String(id), Port: aws.Int64(port)}
+	}
+
+	targets := []*elbv2.TargetDescription{
+		target("10.0.1.15", 8080),
+		target("10.0.1.16", 8080),
+		target("i-0123456789abcdef0", 32768),
+		target("i-0123456789abcdef0", 32769),
+	}
+
+	own := ownTargets(targets, keys)
+	if len(own) != 2 || formatTarget(own[0]) != "10.0.1.15:8080" || formatTarget(own[1]) != "i-0123456789abcdef0:32768" {
+		t.Errorf("got %v, want only the targets of the tasks of the service", own)
+	}
+}
+
+func TestOwnInstances(t *testing.T) {
+	own := ownInstances([]string{"a1", "c3", "b2"}, map[string]bool{"a1": true, "b2": true})
+	if len(own) != 2 || own[0] != "a1" || own[1] != "b2" {
+		t.Errorf("got %v, want the instances of the tasks of the service", own)
+	}
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+)
+
+type roleSummary struct {
+	Kind     string   `json:"kind"`
+	Arn      string   `json:"arn"`
+	Name     string   `json:"name"`
+	Policies []string `json:"policies,omitempty"`
+	Inline   []string `json:"inlinePolicies,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type logGroupSummary struct {
+	Name          string   `json:"name"`
+	Containers    []string `json:"containers"`
+	RetentionDays int64    `json:"retentionDays"`
+	StoredBytes   int64    `json:"storedBytes"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type targetGroupSummary struct {
+	Arn         string `json:"arn"`
+	Name        string `json:"name,omitempty"`
+	Container   string `json:"container"`
+	Port        int64  `json:"containerPort"`
+	HealthCheck string `json:"healthCheck,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type securityGroupSummary struct {
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	IngressRules int    `json:"ingressRules"`
+	EgressRules  int    `json:"egressRules"`
+	Error        string `json:"error,omitempty"`
+}
+
+type registrySummary struct {
+	Arn        string `json:"arn"`
+	Name       string `json:"name,omitempty"`
+	Namespace  string `json:"namespaceId,omitempty"`
+	DNSRecords string `json:"dnsRecords,omitempty"`
+	Instances  int64  `json:"instances"`
+	Error      string `json:"error,omitempty"`
+}
+
+type scalingPolicySummary struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+}
+
+type scalingSummary struct {
+	MinCapacity int64                  `json:"minCapacity"`
+	MaxCapacity int64                  `json:"maxCapacity"`
+	Policies    []scalingPolicySummary `json:"policies,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// serviceDependencies are the resources the service references, each summarized.
+// A lookup that fails, often for lack of permission, is kept with its error instead of failing every other.
+type serviceDependencies struct {
+	Roles          []roleSummary          `json:"roles"`
+	LogGroups      []logGroupSummary      `json:"logGroups"`
+	TargetGroups   []targetGroupSummary   `json:"targetGroups"`
+	SecurityGroups []securityGroupSummary `json:"securityGroups"`
+	Registries     []registrySummary      `json:"serviceRegistries"`
+	AutoScaling    *scalingSummary        `json:"autoScaling,omitempty"`
+}
+
+// lookupError is the placeholder of a resource that could not be looked up
+func lookupError(err error) string {
+	switch awsErrorCode(err) {
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation", "UnauthorizedException":
+		return "access denied"
+	}
+	return err.Error()
+}
+
+func summarizeRole(kind, arn string) (r roleSummary) {
+	r = roleSummary{Kind: kind, Arn: arn, Name: arn[strings.LastIndex(arn, "/")+1:]}
+
+	err := iamI.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(r.Name),
+	}, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+		for _, p := range page.AttachedPolicies {
+			r.Policies = append(r.Policies, aws.StringValue(p.PolicyName))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		r.Error = lookupError(err)
+		return
+	}
+
+	err = iamI.ListRolePoliciesPages(&iam.ListRolePoliciesInput{
+		RoleName: aws.String(r.Name),
+	}, func(page *iam.ListRolePoliciesOutput, lastPage bool) bool {
+		r.Inline = append(r.Inline, aws.StringValueSlice(page.PolicyNames)...)
+		return !lastPage
+	})
+	if err != nil {
+		r.Error = lookupError(err)
+	}
+	return
+}
+
+func summarizeLogGroups(td *ecs.TaskDefinition) (groups []logGroupSummary) {
+	containers := make(map[string][]string)
+	var names []string
+	for _, cd := range td.ContainerDefinitions {
+		if cd.LogConfiguration == nil || aws.StringValue(cd.LogConfiguration.LogDriver) != ecs.LogDriverAwslogs {
+			continue
+		}
+
+		name := aws.StringValue(cd.LogConfiguration.Options["awslogs-group"])
+		if _, ok := containers[name]; !ok {
+			names = append(names, name)
+		}
+		containers[name] = append(containers[name], aws.StringValue(cd.Name))
+	}
+
+	for _, name := range names {
+		g := logGroupSummary{Name: name, Containers: containers[name]}
+
+		described, err := cwlI.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{
+			LogGroupNamePrefix: aws.String(name),
+		})
+		if err != nil {
+			g.Error = lookupError(err)
+		} else {
+			g.Error = "not found"
+			for _, lg := range described.LogGroups {
+				if aws.StringValue(lg.LogGroupName) == name {
+					g.RetentionDays = aws.Int64Value(lg.RetentionInDays)
+					g.StoredBytes = aws.Int64Value(lg.StoredBytes)
+					g.Error = ""
+				}
+			}
+		}
+
+		groups = append(groups, g)
+	}
+	return
+}
+
+func describeHealthCheck(tg *elbv2.TargetGroup) string {
+	check := aws.StringValue(tg.HealthCheckProtocol)
+	if path := aws.StringValue(tg.HealthCheckPath); path != "" {
+		check += " " + path
+	}
+
+	check += fmt.Sprintf(" on %s every %ds, %d healthy / %d unhealthy", aws.StringValue(tg.HealthCheckPort),
+		aws.Int64Value(tg.HealthCheckIntervalSeconds), aws.Int64Value(tg.HealthyThresholdCount), aws.Int64Value(tg.UnhealthyThresholdCount))
+
+	if tg.Matcher != nil && aws.StringValue(tg.Matcher.HttpCode) != "" {
+		check += ", expecting " + aws.StringValue(tg.Matcher.HttpCode)
+	}
+	return check
+}
+
+func summarizeTargetGroups(s *ecs.Service) (groups []targetGroupSummary) {
+	for _, lb := range s.LoadBalancers {
+		if lb.TargetGroupArn == nil {
+			continue
+		}
+
+		g := targetGroupSummary{Arn: aws.StringValue(lb.TargetGroupArn), Container: aws.StringValue(lb.ContainerName), Port: aws.Int64Value(lb.ContainerPort)}
+
+		described, err := elbv2I.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+			TargetGroupArns: []*string{lb.TargetGroupArn},
+		})
+		if err == nil && len(described.TargetGroups) > 0 {
+			g.Name = aws.StringValue(described.TargetGroups[0].TargetGroupName)
+			g.HealthCheck = describeHealthCheck(described.TargetGroups[0])
+		} else if err != nil {
+			g.Error = lookupError(err)
+		}
+
+		groups = append(groups, g)
+	}
+	return
+}
+
+// countRules counts every source or destination of the permissions, as the console lists them
+func countRules(permissions []*ec2.IpPermission) (count int) {
+	for _, p := range permissions {
+		count += len(p.IpRanges) + len(p.Ipv6Ranges) + len(p.UserIdGroupPairs) + len(p.PrefixListIds)
+	}
+	return
+}
+
+func summarizeSecurityGroups(s *ecs.Service) (groups []securityGroupSummary) {
+	if s.NetworkConfiguration == nil || s.NetworkConfiguration.AwsvpcConfiguration == nil {
+		return
+	}
+
+	for _, id := range s.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups {
+		g := securityGroupSummary{ID: aws.StringValue(id)}
+
+		described, err := ec2I.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{GroupIds: []*string{id}})
+		if err == nil && len(described.SecurityGroups) > 0 {
+			sg := described.SecurityGroups[0]
+			g.Name = aws.StringValue(sg.GroupName)
+			g.IngressRules = countRules(sg.IpPermissions)
+			g.EgressRules = countRules(sg.IpPermissionsEgress)
+		} else if err != nil {
+			g.Error = lookupError(err)
+		}
+
+		groups = append(groups, g)
+	}
+	return
+}
+
+func summarizeRegistries(s *ecs.Service) (registries []registrySummary) {
+	for _, sr := range s.ServiceRegistries {
+		r := registrySummary{Arn: aws.StringValue(sr.RegistryArn)}
+
+		described, err := sdI.GetService(&servicediscovery.GetServiceInput{
+			Id: aws.String(registryServiceID(r.Arn)),
+		})
+		if err != nil {
+			r.Error = lookupError(err)
+			registries = append(registries, r)
+			continue
+		}
+
+		r.Name = aws.StringValue(described.Service.Name)
+		r.Namespace = aws.StringValue(described.Service.NamespaceId)
+		r.Instances = aws.Int64Value(described.Service.InstanceCount)
+
+		if dns := described.Service.DnsConfig; dns != nil {
+			var records []string
+			for _, record := range dns.DnsRecords {
+				records = append(records, fmt.Sprintf("%s %ds", aws.StringValue(record.Type), aws.Int64Value(record.TTL)))
+			}
+			r.DNSRecords = strings.Join(records, ", ")
+		}
+
+		registries = append(registries, r)
+	}
+	return
+}
+
+// summarizeAutoScaling finds the scalable target of the service and its policies, nil when it does not scale automatically
+func summarizeAutoScaling(cluster string, s *ecs.Service) *scalingSummary {
+	resourceID := serviceScalableResourceID(cluster, aws.StringValue(s.ServiceName))
+
+	targets, err := aasI.DescribeScalableTargets(&applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceIds:      []*string{aws.String(resourceID)},
+	})
+	if err != nil {
+		return &scalingSummary{Error: lookupError(err)}
+	}
+
+	if len(targets.ScalableTargets) == 0 {
+		return nil
+	}
+
+	scaling := &scalingSummary{
+		MinCapacity: aws.Int64Value(targets.ScalableTargets[0].MinCapacity),
+		MaxCapacity: aws.Int64Value(targets.ScalableTargets[0].MaxCapacity),
+	}
+
+	policies, err := aasI.DescribeScalingPolicies(&applicationautoscaling.DescribeScalingPoliciesInput{
+		ServiceNamespace: aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceId:       aws.String(resourceID),
+	})
+	if err != nil {
+		scaling.Error = lookupError(err)
+		return scaling
+	}
+
+	for _, p := range policies.ScalingPolicies {
+		policy := scalingPolicySummary{Name: aws.StringValue(p.PolicyName), Type: aws.StringValue(p.PolicyType)}
+
+		if c := p.TargetTrackingScalingPolicyConfiguration; c != nil {
+			metric := "custom metric"
+			if c.PredefinedMetricSpecification != nil {
+				metric = aws.StringValue(c.PredefinedMetricSpecification.PredefinedMetricType)
+			} else if c.CustomizedMetricSpecification != nil {
+				metric = aws.StringValue(c.CustomizedMetricSpecification.MetricName)
+			}
+			policy.Target = fmt.Sprintf("%s at %g", metric, aws.Float64Value(c.TargetValue))
+		}
+
+		if c := p.StepScalingPolicyConfiguration; c != nil {
+			policy.Target = fmt.Sprintf("%d steps, %s", len(c.StepAdjustments), aws.StringValue(c.AdjustmentType))
+		}
+
+		scaling.Policies = append(scaling.Policies, policy)
+	}
+	return scaling
+}
+
+// resolveServiceDependencies follows the references of the service and of its task definition
+func resolveServiceDependencies(cluster string, s *ecs.Service, td *ecs.TaskDefinition) *serviceDependencies {
+	deps := &serviceDependencies{}
+
+	roles := []struct{ kind, arn string }{
+		{"task", aws.StringValue(td.TaskRoleArn)},
+		{"execution", aws.StringValue(td.ExecutionRoleArn)},
+		{"service", aws.StringValue(s.RoleArn)},
+	}
+	for _, role := range roles {
+		if role.arn != "" {
+			deps.Roles = append(deps.Roles, summarizeRole(role.kind, role.arn))
+		}
+	}
+
+	deps.LogGroups = summarizeLogGroups(td)
+	deps.TargetGroups = summarizeTargetGroups(s)
+	deps.SecurityGroups = summarizeSecurityGroups(s)
+	deps.Registries = summarizeRegistries(s)
+	deps.AutoScaling = summarizeAutoScaling(cluster, s)
+	return deps
+}
+
+// orError is the summary, or the error of the lookup in its place
+func orError(summary, err string) string {
+	if err != "" {
+		return err
+	}
+	return summary
+}
+
+func printServiceDependencies(deps *serviceDependencies) {
+	w := newTable()
+
+	fmt.Fprintln(w, "Roles:")
+	for _, r := range deps.Roles {
+		policies := append(append([]string{}, r.Policies...), r.Inline...)
+		sort.Strings(policies)
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", r.Kind, r.Name, orError(strings.Join(policies, ", "), r.Error))
+	}
+
+	fmt.Fprintln(w, "Log Groups:")
+	for _, g := range deps.LogGroups {
+		retention := "never expire"
+		if g.RetentionDays > 0 {
+			retention = fmt.Sprintf("retention %dd", g.RetentionDays)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", g.Name, strings.Join(g.Containers, ", "), orError(retention, g.Error))
+	}
+
+	fmt.Fprintln(w, "Target Groups:")
+	for _, g := range deps.TargetGroups {
+		fmt.Fprintf(w, "  %s\t%s:%d\t%s\n", firstSetting(g.Name, g.Arn), g.Container, g.Port, orError(g.HealthCheck, g.Error))
+	}
+
+	fmt.Fprintln(w, "Security Groups:")
+	for _, g := range deps.SecurityGroups {
+		rules := fmt.Sprintf("%d ingress, %d egress rules", g.IngressRules, g.EgressRules)
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", g.ID, g.Name, orError(rules, g.Error))
+	}
+
+	fmt.Fprintln(w, "Cloud Map:")
+	for _, r := range deps.Registries {
+		records := fmt.Sprintf("%s, %d instances", r.DNSRecords, r.Instances)
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", firstSetting(r.Name, r.Arn), r.Namespace, orError(records, r.Error))
+	}
+
+	fmt.Fprintln(w, "Auto Scaling:")
+	if a := deps.AutoScaling; a != nil && a.Error != "" && a.MaxCapacity == 0 {
+		fmt.Fprintf(w, "  capacity\t\t%s\n", a.Error)
+	} else if a != nil {
+		fmt.Fprintf(w, "  capacity\t%d-%d tasks\t\n", a.MinCapacity, a.MaxCapacity)
+		for _, p := range a.Policies {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", p.Name, p.Type, p.Target)
+		}
+
+		if a.Error != "" {
+			fmt.Fprintf(w, "  policies\t\t%s\n", a.Error)
+		}
+	}
+
+	w.Flush()
+}
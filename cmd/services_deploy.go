@@ -6,71 +6,124 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
+type servicesDeployOptions struct {
+	cluster        string
+	containerName  string
+	image          string
+	tag            string
+	repository     string
+	wait           bool
+	timeout        time.Duration
+	overrideFreeze bool
+	preflight      bool
+	push           string
+	buildContext   string
+	revisionTags   []string
+	pinRevision    bool
+	keepPrevious   bool
+	ignoreRunning  bool
+	healthy        bool
+	verify         bool
+	verifyOptions  verifyOptions
+	taskDefinition string
+	capacityCheck  bool
+	watchErrors    bool
+	errorWatch     errorWatchOptions
+}
+
+var servicesDeployOpts servicesDeployOptions
+
 func servicesDeployRun(cmd *cobra.Command, args []string) {
+	opts := &servicesDeployOpts
 	service := args[0]
 
-	clustersDescription, err := ecsI.DescribeClusters(&ecs.DescribeClustersInput{
-		Clusters: []*string{
-			aws.String(cluster),
-		},
-	})
+	if (opts.verify || opts.healthy) && !opts.wait {
+		must(errors.New("--verify and --healthy require --wait"))
+	}
 
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+	if opts.taskDefinition != "" && (opts.image != "" || opts.tag != "" || opts.push != "" || opts.buildContext != "") {
+		must(errors.New("--task-definition deploys a registered revision, it can not be used with --image, --tag, --push or --build-context"))
 	}
 
-	if len(clustersDescription.Clusters) == 0 {
-		fmt.Println(errors.New("Cluster informed not found"))
-		os.Exit(1)
+	if opts.push != "" && opts.buildContext != "" {
+		must(errors.New("--push and --build-context can not be used together"))
 	}
 
-	c := clustersDescription.Clusters[0]
+	// Without a new image the current revision is redeployed, and there would be nothing to push it as
+	if (opts.push != "" || opts.buildContext != "") && opts.image == "" && opts.tag == "" {
+		must(errors.New("--push and --build-context require --image or --tag, naming the image pushed"))
+	}
 
-	servicesDescription, err := ecsI.DescribeServices(&ecs.DescribeServicesInput{
-		Cluster: c.ClusterName,
-		Services: []*string{
-			aws.String(service),
-		},
-	})
+	revisionTags, err := parseResourceTags(opts.revisionTags)
+	must(err)
 
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+	c, err := describeCluster(opts.cluster)
+	must(err)
+
+	s, err := describeService(aws.StringValue(c.ClusterName), service)
+	must(err)
+
+	must(checkServiceFreeze(aws.StringValue(c.ClusterName), service, opts.overrideFreeze))
+
+	if !opts.ignoreRunning {
+		revisions, err := runningRevisions(aws.StringValue(c.ClusterName), service)
+		must(err)
+
+		for _, mismatch := range revisionMismatches(s, revisions) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", mismatch)
+		}
 	}
 
-	if len(servicesDescription.Services) == 0 {
-		fmt.Println(errors.New("Service informed not found"))
-		os.Exit(1)
+	bareFamily := isBareFamily(aws.StringValue(s.TaskDefinition))
+	if bareFamily && !opts.pinRevision {
+		fmt.Fprintf(os.Stderr, "warning: %s is configured with the bare family %s, registering a revision changes what the next scaling event launches. Use --pin-revision to switch it to the deployed revision\n", service, aws.StringValue(s.TaskDefinition))
 	}
 
-	s := servicesDescription.Services[0]
+	// A new image does not change the reservations, the current revision is checked for it
+	if opts.capacityCheck {
+		target := aws.StringValue(s.TaskDefinition)
+		if opts.taskDefinition != "" {
+			target = opts.taskDefinition
+		}
+
+		td, err := describeTaskDefinition(target)
+		must(err)
+		must(runCapacityCheck(aws.StringValue(c.ClusterName), s, td))
+	}
+
+	// A registered revision, or the current one again, is deployed without registering anything
+	if opts.taskDefinition != "" || (opts.image == "" && opts.tag == "") {
+		deployRevision(aws.StringValue(c.ClusterName), s, opts)
+		finishDeploy(aws.StringValue(c.ClusterName), s, opts)
+		return
+	}
 
 	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
 		TaskDefinition: s.TaskDefinition,
+		Include:        []*string{aws.String(ecs.TaskDefinitionFieldTags)},
 	})
 
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		exit(1)
 	}
 
 	td := tdDescription.TaskDefinition
 
 	var cdToUpdate *ecs.ContainerDefinition
 
-	if containerName == "" {
+	if opts.containerName == "" {
 		cdToUpdate = td.ContainerDefinitions[0]
 	} else {
 		for _, cd := range td.ContainerDefinitions {
-			if aws.StringValue(cd.Name) == containerName {
+			if aws.StringValue(cd.Name) == opts.containerName {
 				cdToUpdate = cd
 				break
 			}
@@ -79,15 +132,26 @@ func servicesDeployRun(cmd *cobra.Command, args []string) {
 
 	if cdToUpdate == nil {
 		fmt.Println(fmt.Errorf("No container on the Task Family %s", aws.StringValue(td.Family)))
-		os.Exit(1)
+		exit(1)
 	}
 
-	if tag != "" {
-		image = strings.Split(aws.StringValue(cdToUpdate.Image), ":")[0] + ":" + tag
+	image := opts.image
+	if opts.tag != "" {
+		image = strings.Split(aws.StringValue(cdToUpdate.Image), ":")[0] + ":" + opts.tag
 	}
 
 	cdToUpdate.Image = aws.String(image)
 
+	if opts.push != "" || opts.buildContext != "" {
+		must(pushImage(image, opts.push, opts.buildContext))
+	}
+
+	if opts.preflight {
+		checks, err := preflightTaskDefinition(aws.StringValue(c.ClusterName), td, aws.StringValue(s.LaunchType))
+		must(err)
+		must(printPreflight(checks))
+	}
+
 	newTDDescription, err := ecsI.RegisterTaskDefinition(&ecs.RegisterTaskDefinitionInput{
 		ContainerDefinitions:    td.ContainerDefinitions,
 		Cpu:                     td.Cpu,
@@ -99,36 +163,106 @@ func servicesDeployRun(cmd *cobra.Command, args []string) {
 		RequiresCompatibilities: td.RequiresCompatibilities,
 		TaskRoleArn:             td.TaskRoleArn,
 		Volumes:                 td.Volumes,
+		Tags:                    resourceTags(tdDescription.Tags),
 	})
 
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		exit(1)
 	}
 
 	newTD := newTDDescription.TaskDefinition
+	tagRevision(aws.StringValue(newTD.TaskDefinitionArn), revisionTags)
 	oldFamilyRevision := aws.StringValue(td.Family) + ":" + strconv.FormatInt(aws.Int64Value(td.Revision), 10)
 
-	_, err = ecsI.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{
-		TaskDefinition: aws.String(oldFamilyRevision),
-	})
+	// An INACTIVE revision can not be deployed again, so the replaced one is kept for services rollback when asked
+	if !opts.keepPrevious {
+		_, err = ecsI.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{
+			TaskDefinition: aws.String(oldFamilyRevision),
+		})
 
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(1)
+		}
 	}
 
 	newFamilyRevision := aws.StringValue(newTD.Family) + ":" + strconv.FormatInt(aws.Int64Value(newTD.Revision), 10)
 
+	// Services configured by the bare family keep it, so ECS resolves it to the revision just registered
+	serviceTaskDefinition := newFamilyRevision
+	if bareFamily && !opts.pinRevision {
+		serviceTaskDefinition = aws.StringValue(newTD.Family)
+	}
+
 	_, err = ecsI.UpdateService(&ecs.UpdateServiceInput{
 		Cluster:        c.ClusterName,
 		Service:        aws.String(service),
-		TaskDefinition: aws.String(newFamilyRevision),
+		TaskDefinition: aws.String(serviceTaskDefinition),
 	})
 
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		exit(1)
+	}
+
+	printAffected(aws.StringValue(newTD.TaskDefinitionArn), service+" deployed with "+newFamilyRevision)
+
+	finishDeploy(aws.StringValue(c.ClusterName), s, opts)
+}
+
+// deployRevision updates the service to a registered revision, or forces a new deployment of the current one
+func deployRevision(cluster string, s *ecs.Service, opts *servicesDeployOptions) {
+	input := &ecs.UpdateServiceInput{
+		Cluster: aws.String(cluster),
+		Service: s.ServiceName,
+	}
+
+	if opts.taskDefinition == "" {
+		input.ForceNewDeployment = aws.Bool(true)
+	} else {
+		td, err := describeTaskDefinition(opts.taskDefinition)
+		must(err)
+
+		reference := familyRevision(td.Family, td.Revision)
+		if isBareFamily(opts.taskDefinition) && !opts.pinRevision {
+			reference = aws.StringValue(td.Family)
+		}
+		input.TaskDefinition = aws.String(reference)
+	}
+
+	result, err := ecsI.UpdateService(input)
+	must(err)
+
+	td := aws.StringValue(result.Service.TaskDefinition)
+	family, revision := splitTaskDefinitionArn(td)
+
+	message := fmt.Sprintf("%s deployed with %s:%d", aws.StringValue(s.ServiceName), family, revision)
+	if opts.taskDefinition == "" {
+		message = fmt.Sprintf("%s redeployed with %s:%d", aws.StringValue(s.ServiceName), family, revision)
+	}
+	printAffected(td, message)
+}
+
+// finishDeploy waits for the deployment and verifies the service when asked
+func finishDeploy(cluster string, s *ecs.Service, opts *servicesDeployOptions) {
+	service := aws.StringValue(s.ServiceName)
+
+	if opts.wait {
+		failed, err := waitServices(cluster, []string{service}, opts.timeout, false, opts.healthy)
+		reportServicesWait(failed, err)
+	}
+
+	if opts.verify {
+		must(verifyService(cluster, s, opts.verifyOptions))
+		typist.Printf("%s verified\n", service)
+	}
+
+	if opts.watchErrors {
+		w, err := watchErrors(cluster, service, time.Time{}, opts.errorWatch)
+		must(err)
+
+		reportErrorWatch(service, w, opts.errorWatch)
 	}
 }
 
@@ -144,14 +278,35 @@ func init() {
 
 	flags := servicesDeployCmd.Flags()
 
-	flags.StringVar(&containerName, "container", "", containerNameSpec)
-
-	flags.StringVarP(&tag, "tag", "t", "", tagSpec)
-	flags.StringVarP(&image, "image", "i", "", imageSpec)
-	flags.StringVarP(&cluster, "cluster", "c", "", requiredSpec+clusterSpec)
-	flags.StringVarP(&repository, "repository", "r", "", repositorySpec)
+	flags.StringVar(&servicesDeployOpts.containerName, "container", "", containerNameSpec)
+
+	flags.StringVarP(&servicesDeployOpts.tag, "tag", "t", "", tagSpec)
+	flags.StringVarP(&servicesDeployOpts.image, "image", "i", "", imageSpec)
+	flags.StringVar(&servicesDeployOpts.taskDefinition, "task-definition", "", deployTaskDefinitionSpec)
+	flags.StringVarP(&servicesDeployOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVarP(&servicesDeployOpts.repository, "repository", "r", "", repositorySpec)
+	flags.BoolVarP(&servicesDeployOpts.wait, "wait", "w", false, waitSpec)
+	flags.DurationVar(&servicesDeployOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVar(&servicesDeployOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+	flags.BoolVar(&servicesDeployOpts.preflight, "preflight", false, preflightSpec)
+	flags.BoolVar(&servicesDeployOpts.capacityCheck, "capacity-check", false, capacityCheckSpec)
+	flags.StringVar(&servicesDeployOpts.push, "push", "", pushSpec)
+	flags.StringVar(&servicesDeployOpts.buildContext, "build-context", "", buildContextSpec)
+	flags.StringArrayVar(&servicesDeployOpts.revisionTags, "revision-tag", []string{}, revisionTagSpec)
+	flags.BoolVar(&servicesDeployOpts.pinRevision, "pin-revision", false, pinRevisionSpec)
+	flags.BoolVar(&servicesDeployOpts.keepPrevious, "keep-previous", false, keepPreviousSpec)
+	flags.BoolVar(&servicesDeployOpts.healthy, "healthy", false, healthySpec)
+	flags.BoolVar(&servicesDeployOpts.verify, "verify", false, deployVerifySpec)
+	flags.StringVar(&servicesDeployOpts.verifyOptions.urlPath, "url-path", "/", urlPathSpec)
+	flags.IntVar(&servicesDeployOpts.verifyOptions.expectStatus, "expect-status", 200, expectStatusSpec)
+	flags.StringVar(&servicesDeployOpts.verifyOptions.expectBodyContains, "expect-body-contains", "", expectBodyContainsSpec)
+	flags.DurationVar(&servicesDeployOpts.verifyOptions.timeout, "verify-timeout", 2*time.Minute, verifyTimeoutSpec)
+	flags.BoolVar(&servicesDeployOpts.watchErrors, "watch-errors", false, deployWatchErrorsSpec)
+	flags.StringVar(&servicesDeployOpts.errorWatch.pattern, "error-pattern", `"ERROR"`, errorPatternSpec)
+	flags.DurationVar(&servicesDeployOpts.errorWatch.window, "watch-window", 5*time.Minute, errorWindowSpec)
+	flags.DurationVar(&servicesDeployOpts.errorWatch.baselineWindow, "baseline-window", 30*time.Minute, baselineWindowSpec)
+	flags.Float64Var(&servicesDeployOpts.errorWatch.threshold, "error-threshold", 2.0, errorThresholdSpec)
+	flags.BoolVar(&servicesDeployOpts.ignoreRunning, "ignore-running-check", false, ignoreRunningCheckSpec)
 
 	servicesDeployCmd.MarkFlagRequired("cluster")
-
-	viper.BindPFlag("cluster", servicesDeployCmd.Flags().Lookup("cluster"))
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type servicesDescribeOptions struct {
+	cluster   string
+	events    int
+	recursive bool
+}
+
+var servicesDescribeOpts servicesDescribeOptions
+
+// printServiceJSON prints the service as described, plus the task definitions the running tasks use
+// serviceDescription is the JSON output, the service as described by the API along with its running revisions
+type serviceDescription struct {
+	*ecs.Service
+	RunningTaskDefinitions map[string]int       `json:"runningTaskDefinitions"`
+	Dependencies           *serviceDependencies `json:"dependencies,omitempty"`
+}
+
+func printServiceJSON(s *ecs.Service, revisions map[string]int, deps *serviceDependencies) {
+	content, err := json.Marshal(serviceDescription{s, revisions, deps})
+	must(err)
+
+	described := map[string]interface{}{}
+	must(json.Unmarshal(content, &described))
+	described["schemaVersion"] = outputSchemaVersion
+
+	output := outputConfiguration{Expand: true}
+	formatted, err := output.Formatter().Marshal(described)
+	must(err)
+	fmt.Println(string(formatted))
+}
+
+func servicesDescribeRun(cmd *cobra.Command, args []string) {
+	opts := &servicesDescribeOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	s, err := describeService(opts.cluster, args[0])
+	must(err)
+
+	revisions, err := runningRevisions(opts.cluster, aws.StringValue(s.ServiceName))
+	must(err)
+
+	if quiet && outputFormat != "json" {
+		printID(aws.StringValue(s.ServiceArn))
+		return
+	}
+
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: s.TaskDefinition,
+		Include:        []*string{aws.String(ecs.TaskDefinitionFieldTags)},
+	})
+	must(err)
+
+	td := tdDescription.TaskDefinition
+
+	var deps *serviceDependencies
+	if opts.recursive {
+		deps = resolveServiceDependencies(opts.cluster, s, td)
+	}
+
+	if outputFormat == "json" {
+		printServiceJSON(s, revisions, deps)
+		return
+	}
+
+	typist.Printf("Service:         %s\n", aws.StringValue(s.ServiceName))
+	typist.Printf("ARN:             %s\n", aws.StringValue(s.ServiceArn))
+	typist.Printf("Status:          %s\n", aws.StringValue(s.Status))
+	typist.Printf("Placement:       %s\n", currentPlacement(s))
+	typist.Printf("Tasks:           %d desired, %d running, %d pending\n", aws.Int64Value(s.DesiredCount), aws.Int64Value(s.RunningCount), aws.Int64Value(s.PendingCount))
+	typist.Printf("Task Definition: %s\n", aws.StringValue(s.TaskDefinition))
+	typist.Printf("Revision:        %s\n", familyRevision(td.Family, td.Revision))
+
+	if len(tdDescription.Tags) > 0 {
+		typist.Printf("Revision Tags:   %s\n", formatTags(tdDescription.Tags))
+	}
+
+	var arns []string
+	for arn := range revisions {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	typist.Println("Running Revisions:")
+	for _, arn := range arns {
+		family, revision := splitTaskDefinitionArn(arn)
+		typist.Printf("  %s:%d\t%d task(s)\n", family, revision, revisions[arn])
+	}
+
+	for _, mismatch := range revisionMismatches(s, revisions) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", mismatch)
+	}
+
+	typist.Println("Deployments:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  ID\tSTATUS\tROLLOUT\tREVISION\tDESIRED\tRUNNING\tPENDING\tUPDATED")
+	for _, d := range s.Deployments {
+		family, revision := splitTaskDefinitionArn(aws.StringValue(d.TaskDefinition))
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s:%d\t%d\t%d\t%d\t%s\n", aws.StringValue(d.Id), aws.StringValue(d.Status), aws.StringValue(d.RolloutState),
+			family, revision, aws.Int64Value(d.DesiredCount), aws.Int64Value(d.RunningCount), aws.Int64Value(d.PendingCount),
+			humanizeTime(d.UpdatedAt))
+	}
+	w.Flush()
+
+	// The events are returned newest first, they are printed newest last as logs are
+	events := s.Events
+	if len(events) > opts.events {
+		events = events[:opts.events]
+	}
+
+	red := color.New(color.FgRed).SprintFunc()
+	typist.Println("Events:")
+	for i := len(events) - 1; i >= 0; i-- {
+		typist.Printf("  [%s] %s\n", red(aws.TimeValue(events[i].CreatedAt).Format(time.RFC3339)), aws.StringValue(events[i].Message))
+	}
+
+	if deps != nil {
+		printServiceDependencies(deps)
+	}
+}
+
+var servicesDescribeCmd = &cobra.Command{
+	Use:         "describe [service]",
+	Short:       "Describe a service: tasks, task definition, deployments and recent events",
+	Args:        cobra.ExactArgs(1),
+	Run:         servicesDescribeRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesDescribeCmd)
+
+	registerOutputSchema(servicesDescribeCmd, "services describe", serviceDescription{})
+
+	flags := servicesDescribeCmd.Flags()
+
+	flags.StringVarP(&servicesDescribeOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.IntVar(&servicesDescribeOpts.events, "events", 10, serviceEventsCountSpec)
+	flags.BoolVar(&servicesDescribeOpts.recursive, "recursive", false, describeRecursiveSpec)
+
+	servicesDescribeCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+)
+
+type servicesEndpointOptions struct {
+	cluster    string
+	resolveDNS bool
+}
+
+var servicesEndpointOpts servicesEndpointOptions
+
+// actionsForward tells if any of the actions forwards to the target group
+func actionsForward(actions []*elbv2.Action, targetGroupArn string) bool {
+	for _, action := range actions {
+		if aws.StringValue(action.TargetGroupArn) == targetGroupArn {
+			return true
+		}
+
+		if action.ForwardConfig == nil {
+			continue
+		}
+
+		for _, tg := range action.ForwardConfig.TargetGroups {
+			if aws.StringValue(tg.TargetGroupArn) == targetGroupArn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listenerURL builds scheme://host[:port][path], omitting the default port of the scheme
+func listenerURL(listener *elbv2.Listener, host, path string) string {
+	scheme := strings.ToLower(aws.StringValue(listener.Protocol))
+	port := aws.Int64Value(listener.Port)
+
+	url := scheme + "://" + host
+	if !(scheme == "http" && port == 80) && !(scheme == "https" && port == 443) {
+		url += fmt.Sprintf(":%d", port)
+	}
+	return url + path
+}
+
+// ruleHostsAndPaths extracts the host-header and path-pattern values of the rule conditions
+func ruleHostsAndPaths(rule *elbv2.Rule) (hosts, paths []string) {
+	for _, condition := range rule.Conditions {
+		switch aws.StringValue(condition.Field) {
+		case "host-header":
+			if condition.HostHeaderConfig != nil {
+				hosts = append(hosts, aws.StringValueSlice(condition.HostHeaderConfig.Values)...)
+			} else {
+				hosts = append(hosts, aws.StringValueSlice(condition.Values)...)
+			}
+		case "path-pattern":
+			if condition.PathPatternConfig != nil {
+				paths = append(paths, aws.StringValueSlice(condition.PathPatternConfig.Values)...)
+			} else {
+				paths = append(paths, aws.StringValueSlice(condition.Values)...)
+			}
+		}
+	}
+	return
+}
+
+// loadBalancerEndpoints walks from the target group to the listeners and rules forwarding to it
+func loadBalancerEndpoints(targetGroupArn string) (urls []string, dnsNames []string, err error) {
+	tgs, err := elbv2I.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{aws.String(targetGroupArn)},
+	})
+	if err != nil {
+		return
+	}
+
+	for _, tg := range tgs.TargetGroups {
+		if len(tg.LoadBalancerArns) == 0 {
+			continue
+		}
+
+		lbs, err := elbv2I.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+			LoadBalancerArns: tg.LoadBalancerArns,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, lb := range lbs.LoadBalancers {
+			dnsName := aws.StringValue(lb.DNSName)
+			dnsNames = append(dnsNames, dnsName)
+
+			listeners, err := elbv2I.DescribeListeners(&elbv2.DescribeListenersInput{LoadBalancerArn: lb.LoadBalancerArn})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, listener := range listeners.Listeners {
+				// Only Application Load Balancers have rules, the others forward every connection
+				if aws.StringValue(lb.Type) != elbv2.LoadBalancerTypeEnumApplication {
+					if actionsForward(listener.DefaultActions, targetGroupArn) {
+						urls = append(urls, listenerURL(listener, dnsName, ""))
+					}
+					continue
+				}
+
+				rules, err := elbv2I.DescribeRules(&elbv2.DescribeRulesInput{ListenerArn: listener.ListenerArn})
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for _, rule := range rules.Rules {
+					if !actionsForward(rule.Actions, targetGroupArn) {
+						continue
+					}
+
+					hosts, paths := ruleHostsAndPaths(rule)
+					if len(hosts) == 0 {
+						hosts = []string{dnsName}
+					}
+					if len(paths) == 0 {
+						paths = []string{""}
+					}
+
+					for _, host := range hosts {
+						for _, path := range paths {
+							urls = append(urls, listenerURL(listener, host, path))
+						}
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// aliasRecords scans the hosted zones for alias records pointing at the load balancers
+func aliasRecords(dnsNames []string) (records []string, err error) {
+	targets := make(map[string]bool)
+	for _, name := range dnsNames {
+		targets[strings.ToLower(strings.TrimSuffix(name, "."))] = true
+	}
+
+	normalize := func(name string) string {
+		return strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(name, ".")), "dualstack.")
+	}
+
+	err = r53I.ListHostedZonesPages(&route53.ListHostedZonesInput{}, func(zones *route53.ListHostedZonesOutput, lastZones bool) bool {
+		for _, zone := range zones.HostedZones {
+			err = r53I.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{
+				HostedZoneId: zone.Id,
+			}, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+				for _, record := range page.ResourceRecordSets {
+					if record.AliasTarget != nil && targets[normalize(aws.StringValue(record.AliasTarget.DNSName))] {
+						records = append(records, strings.TrimSuffix(aws.StringValue(record.Name), "."))
+					}
+				}
+				return !lastPage
+			})
+			if err != nil {
+				return false
+			}
+		}
+		return !lastZones
+	})
+	return
+}
+
+// taskEndpoints is the fallback for services without load balancer: the address of each task and its ports
+func taskEndpoints(cluster string, s *ecs.Service) (endpoints []string, err error) {
+	tasks, err := serviceTasks(cluster, aws.StringValue(s.ServiceName), ecs.DesiredStatusRunning)
+	if err != nil {
+		return
+	}
+
+	instanceIPs := make(map[string]string)
+	for _, t := range tasks {
+		td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+		if err != nil {
+			return nil, err
+		}
+
+		var ip string
+		for _, attachment := range t.Attachments {
+			for _, detail := range attachment.Details {
+				if aws.StringValue(detail.Name) == "privateIPv4Address" {
+					ip = aws.StringValue(detail.Value)
+				}
+			}
+		}
+
+		if ip != "" {
+			for _, cd := range td.ContainerDefinitions {
+				for _, m := range cd.PortMappings {
+					endpoints = append(endpoints, fmt.Sprintf("%s:%d\t%s\t%s", ip, aws.Int64Value(m.ContainerPort), aws.StringValue(cd.Name), taskID(aws.StringValue(t.TaskArn))))
+				}
+			}
+			continue
+		}
+
+		// bridge and host network modes are reached through the instance and the host port
+		instanceIP, err := containerInstanceIP(cluster, aws.StringValue(t.ContainerInstanceArn), instanceIPs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range t.Containers {
+			for _, binding := range c.NetworkBindings {
+				endpoints = append(endpoints, fmt.Sprintf("%s:%d\t%s\t%s", instanceIP, aws.Int64Value(binding.HostPort), aws.StringValue(c.Name), taskID(aws.StringValue(t.TaskArn))))
+			}
+		}
+	}
+	return
+}
+
+// containerInstanceIP finds the private IP of the EC2 instance of a container instance, caching it
+func containerInstanceIP(cluster, containerInstanceArn string, cache map[string]string) (ip string, err error) {
+	if ip, ok := cache[containerInstanceArn]; ok || containerInstanceArn == "" {
+		return ip, nil
+	}
+
+	instances, err := ecsxI.DescribeAllContainerInstances(cluster, []*string{aws.String(containerInstanceArn)})
+	if err != nil || len(instances) == 0 {
+		return
+	}
+
+	result, err := ec2I.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{instances[0].Ec2InstanceId},
+	})
+	if err != nil {
+		return
+	}
+
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			ip = aws.StringValue(instance.PrivateIpAddress)
+		}
+	}
+
+	cache[containerInstanceArn] = ip
+	return
+}
+
+func servicesEndpointRun(cmd *cobra.Command, args []string) {
+	opts := &servicesEndpointOpts
+
+	s, err := describeService(opts.cluster, args[0])
+	must(err)
+
+	if len(s.LoadBalancers) == 0 {
+		endpoints, err := taskEndpoints(opts.cluster, s)
+		must(err)
+
+		for _, endpoint := range endpoints {
+			if quiet {
+				printID(strings.SplitN(endpoint, "\t", 2)[0])
+				continue
+			}
+			typist.Println(endpoint)
+		}
+		return
+	}
+
+	var urls, dnsNames []string
+	for _, lb := range s.LoadBalancers {
+		if lb.TargetGroupArn == nil {
+			// Classic Load Balancers are informed by name
+			typist.Printf("classic load balancer %s\n", aws.StringValue(lb.LoadBalancerName))
+			continue
+		}
+
+		found, names, err := loadBalancerEndpoints(aws.StringValue(lb.TargetGroupArn))
+		must(err)
+
+		urls = append(urls, found...)
+		dnsNames = append(dnsNames, names...)
+	}
+
+	sort.Strings(urls)
+	for _, url := range urls {
+		printID(url)
+	}
+
+	if !opts.resolveDNS {
+		return
+	}
+
+	records, err := aliasRecords(dnsNames)
+	must(err)
+
+	sort.Strings(records)
+	for _, record := range records {
+		printAffected(record, record+"\talias to the load balancer")
+	}
+}
+
+var servicesEndpointCmd = &cobra.Command{
+	Use:   "endpoint [service]",
+	Short: "Print the URLs a service is reachable at",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesEndpointRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesEndpointCmd)
+
+	flags := servicesEndpointCmd.Flags()
+
+	flags.StringVarP(&servicesEndpointOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&servicesEndpointOpts.resolveDNS, "resolve-dns", false, resolveDNSSpec)
+
+	servicesEndpointCmd.MarkFlagRequired("cluster")
+}
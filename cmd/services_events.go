@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type servicesEventsOptions struct {
+	cluster      string
+	follow       bool
+	since        string
+	pollInterval time.Duration
+}
+
+var servicesEventsOpts servicesEventsOptions
+
+type serviceEvent struct {
+	label string
+	event *ecs.ServiceEvent
+}
+
+// newServiceEvents returns the events of the services not seen yet and created after since, oldest first.
+// DescribeServices only returns the latest 100 events of each service.
+func newServiceEvents(described []*ecs.Service, labels map[string]string, seen map[string]bool, since time.Time) (events []serviceEvent) {
+	for _, s := range described {
+		for _, event := range s.Events {
+			id := aws.StringValue(event.Id)
+			if seen[id] || aws.TimeValue(event.CreatedAt).Before(since) {
+				continue
+			}
+
+			seen[id] = true
+			events = append(events, serviceEvent{labels[aws.StringValue(s.ServiceName)], event})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.TimeValue(events[i].event.CreatedAt).Before(aws.TimeValue(events[j].event.CreatedAt))
+	})
+	return
+}
+
+func printServiceEvent(e serviceEvent) {
+	red := color.New(color.FgRed).SprintFunc()
+
+	line := fmt.Sprintf("[%s] ", red(aws.TimeValue(e.event.CreatedAt).Format(time.RFC3339)))
+	if e.label != "" {
+		line += e.label + " "
+	}
+	typist.Println(line + aws.StringValue(e.event.Message))
+}
+
+func servicesEventsRun(cmd *cobra.Command, services []string) {
+	opts := &servicesEventsOpts
+
+	var since time.Time
+	if opts.since != "" {
+		var err error
+		since, err = parseSince(opts.since)
+		must(err)
+	}
+
+	// Events are labeled by service name when more than one is informed, as logs tail does
+	var names []string
+	width := 0
+	for _, service := range services {
+		name := service[strings.LastIndex(service, "/")+1:]
+		if len(name) > width {
+			width = len(name)
+		}
+		names = append(names, name)
+	}
+
+	labels := make(map[string]string)
+	if len(names) > 1 {
+		for i, name := range names {
+			labels[name] = color.New(serviceLabelColors[i%len(serviceLabelColors)]).Sprintf("%-*s", width, name)
+		}
+	}
+
+	ctx := interruptContext()
+	seen := make(map[string]bool)
+	for {
+		described, err := describeServices(opts.cluster, aws.StringSlice(services))
+		must(err)
+
+		for _, e := range newServiceEvents(described, labels, seen, since) {
+			printServiceEvent(e)
+		}
+
+		if !opts.follow || !sleepContext(ctx, opts.pollInterval) {
+			return
+		}
+	}
+}
+
+var servicesEventsCmd = &cobra.Command{
+	Use:   "events [services...]",
+	Short: "Show the events of services, oldest first, optionally following the new ones",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesEventsRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesEventsCmd)
+
+	flags := servicesEventsCmd.Flags()
+
+	flags.StringVarP(&servicesEventsOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVarP(&servicesEventsOpts.follow, "follow", "f", false, eventsFollowSpec)
+	flags.StringVar(&servicesEventsOpts.since, "since", "", eventsSinceSpec)
+	flags.DurationVar(&servicesEventsOpts.pollInterval, "poll-interval", 5*time.Second, eventsPollIntervalSpec)
+
+	servicesEventsCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/spf13/cobra"
+)
+
+type servicesFreezeOptions struct {
+	cluster string
+	reason  string
+}
+
+var servicesFreezeOpts servicesFreezeOptions
+
+func servicesFreezeRun(cmd *cobra.Command, services []string) {
+	opts := &servicesFreezeOpts
+
+	identity, err := stsI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	must(err)
+
+	described, err := describeServices(opts.cluster, aws.StringSlice(services))
+	must(err)
+
+	for _, s := range described {
+		_, err := ecsI.TagResource(&ecs.TagResourceInput{
+			ResourceArn: s.ServiceArn,
+			Tags: []*ecs.Tag{
+				{Key: aws.String(freezeTagKey), Value: aws.String(opts.reason)},
+				{Key: aws.String(frozenByTagKey), Value: identity.Arn},
+				{Key: aws.String(frozenAtTagKey), Value: aws.String(time.Now().UTC().Format(time.RFC3339))},
+			},
+		})
+		must(err)
+
+		printAffected(aws.StringValue(s.ServiceArn), aws.StringValue(s.ServiceName)+" frozen")
+	}
+}
+
+var servicesFreezeCmd = &cobra.Command{
+	Use:   "freeze [services...]",
+	Short: "Block ecsctl from changing services until they are unfrozen",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesFreezeRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesFreezeCmd)
+
+	flags := servicesFreezeCmd.Flags()
+
+	flags.StringVarP(&servicesFreezeOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesFreezeOpts.reason, "reason", "", requiredSpec+freezeReasonSpec)
+
+	servicesFreezeCmd.MarkFlagRequired("cluster")
+	servicesFreezeCmd.MarkFlagRequired("reason")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+)
+
+type servicesListOptions struct {
+	cluster   string
+	namesOnly bool
+}
+
+var servicesListOpts servicesListOptions
+
+func servicesListRun(cmd *cobra.Command, args []string) {
+	opts := &servicesListOpts
+
+	serviceArns, err := listServices(opts.cluster)
+	must(err)
+
+	for _, arn := range serviceArns {
+		if opts.namesOnly {
+			printID(taskID(aws.StringValue(arn)))
+			continue
+		}
+		printID(aws.StringValue(arn))
+	}
+}
+
+var servicesListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the services of a cluster",
+	Args:        cobra.NoArgs,
+	Run:         servicesListRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesListCmd)
+
+	flags := servicesListCmd.Flags()
+
+	flags.StringVarP(&servicesListOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&servicesListOpts.namesOnly, "names-only", false, namesOnlySpec)
+
+	servicesListCmd.MarkFlagRequired("cluster")
+
+	getAlias(servicesListCmd, "services", "service", "svc")
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesLogsOptions struct {
+	cluster       string
+	previous      bool
+	since         string
+	until         string
+	outputDir     string
+	filterPattern string
+	where         logFieldFilter
+	containers    containerFilter
+	output        outputConfiguration
+}
+
+var servicesLogsOpts servicesLogsOptions
+
+// previousDeploymentTasks finds the stopped tasks of the most recent deployment that is not the primary one.
+// Service tasks are started by their deployment ID, which identifies the deployment even after it is gone.
+func previousDeploymentTasks(cluster string, s *ecs.Service) (tasks []*ecs.Task, err error) {
+	var primaryID string
+	for _, d := range s.Deployments {
+		if aws.StringValue(d.Status) == "PRIMARY" {
+			primaryID = aws.StringValue(d.Id)
+		}
+	}
+
+	stopped, err := serviceTasks(cluster, aws.StringValue(s.ServiceName), ecs.DesiredStatusStopped)
+	if err != nil {
+		return
+	}
+
+	var previousID string
+	var lastStoppedAt time.Time
+	for _, t := range stopped {
+		startedBy := aws.StringValue(t.StartedBy)
+		if startedBy == primaryID {
+			continue
+		}
+
+		if aws.TimeValue(t.StoppedAt).After(lastStoppedAt) {
+			lastStoppedAt = aws.TimeValue(t.StoppedAt)
+			previousID = startedBy
+		}
+	}
+
+	for _, t := range stopped {
+		if previousID != "" && aws.StringValue(t.StartedBy) == previousID {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return
+}
+
+func servicesLogsRun(cmd *cobra.Command, args []string) {
+	opts := &servicesLogsOpts
+	service := args[0]
+
+	var startTime time.Time
+	if opts.since != "" {
+		var err error
+		startTime, err = parseSince(opts.since)
+		must(err)
+	}
+
+	s, err := describeService(opts.cluster, service)
+	must(err)
+
+	if opts.outputDir != "" {
+		if opts.since == "" || opts.previous {
+			must(errors.New("--output-dir requires --since and can not be used with --previous"))
+		}
+
+		if len(opts.where.conditions) > 0 || opts.output.Grep.pattern != nil || opts.output.GrepV.pattern != nil {
+			must(errors.New("--where, --grep and --grep-v can not be used with --output-dir, the events are exported as they are"))
+		}
+
+		endTime := time.Now()
+		if opts.until != "" {
+			endTime, err = parseSince(opts.until)
+			must(err)
+		}
+
+		must(exportServiceLogs(opts.outputDir, opts.cluster, s, startTime, endTime, opts.filterPattern, opts.containers))
+		return
+	}
+
+	var tasks []*ecs.Task
+	if opts.previous {
+		tasks, err = previousDeploymentTasks(opts.cluster, s)
+		must(err)
+
+		if len(tasks) == 0 {
+			must(errors.New("No stopped tasks of a previous deployment were found"))
+		}
+	} else {
+		tasks, err = serviceTasks(opts.cluster, service, ecs.DesiredStatusRunning)
+		must(err)
+	}
+
+	var streams []logStream
+	var tds []*ecs.TaskDefinition
+	for _, t := range tasks {
+		td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+		must(err)
+
+		tds = append(tds, td)
+		streams = append(streams, taskLogStreams(td, taskID(aws.StringValue(t.TaskArn)))...)
+	}
+
+	must(opts.containers.validate(tds))
+	streams = opts.containers.apply(streams)
+
+	events, err := fetchLogEvents(streams, startTime, opts.filterPattern)
+	must(err)
+
+	for _, event := range opts.where.apply(events) {
+		printEvent(&opts.output, event)
+	}
+}
+
+var servicesLogsCmd = &cobra.Command{
+	Use:         "logs [service]",
+	Short:       "Show the CloudWatch logs of the tasks of a service",
+	Args:        cobra.ExactArgs(1),
+	Run:         servicesLogsRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesLogsCmd)
+
+	flags := servicesLogsCmd.Flags()
+
+	flags.StringVarP(&servicesLogsOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&servicesLogsOpts.previous, "previous", false, previousSpec)
+	flags.StringVar(&servicesLogsOpts.since, "since", "", sinceSpec)
+	flags.StringVar(&servicesLogsOpts.until, "until", "", untilSpec)
+	flags.StringVar(&servicesLogsOpts.outputDir, "output-dir", "", outputDirSpec)
+	flags.StringVar(&servicesLogsOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	addLogFieldFilterFlags(flags, &servicesLogsOpts.where)
+	flags.StringArrayVar(&servicesLogsOpts.containers.include, "container", []string{}, logsContainerSpec)
+	flags.StringArrayVar(&servicesLogsOpts.containers.exclude, "exclude-container", []string{}, logsExcludeContainerSpec)
+	addLogOutputFlags(flags, &servicesLogsOpts.output)
+
+	servicesLogsCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const exportManifestName = "manifest.json"
+
+type exportedStream struct {
+	Group  string `json:"group"`
+	Stream string `json:"stream"`
+	File   string `json:"file"`
+	Events int64  `json:"events"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+type exportManifest struct {
+	Cluster       string           `json:"cluster"`
+	Service       string           `json:"service"`
+	Since         time.Time        `json:"since"`
+	Until         time.Time        `json:"until"`
+	FilterPattern string           `json:"filterPattern,omitempty"`
+	Streams       []exportedStream `json:"streams"`
+}
+
+type exportedEvent struct {
+	Timestamp     int64  `json:"timestamp"`
+	IngestionTime int64  `json:"ingestionTime"`
+	Stream        string `json:"stream"`
+	Message       string `json:"message"`
+}
+
+// serviceStreamPrefixes computes the stream prefix (prefix/container/) of every awslogs container
+// of the Task Definitions used by the deployments of the service
+func serviceStreamPrefixes(s *ecs.Service, containers containerFilter) (prefixes []logStream, err error) {
+	tdArns := []string{aws.StringValue(s.TaskDefinition)}
+	for _, d := range s.Deployments {
+		tdArns = append(tdArns, aws.StringValue(d.TaskDefinition))
+	}
+
+	seen := make(map[string]bool)
+	var tds []*ecs.TaskDefinition
+	for _, tdArn := range tdArns {
+		if tdArn == "" || seen[tdArn] {
+			continue
+		}
+		seen[tdArn] = true
+
+		td, err := describeTaskDefinition(tdArn)
+		if err != nil {
+			return nil, err
+		}
+		tds = append(tds, td)
+
+		for _, stream := range taskLogStreams(td, "") {
+			if !containers.allows(stream.container) || seen[stream.group+":"+stream.name] {
+				continue
+			}
+			seen[stream.group+":"+stream.name] = true
+
+			prefixes = append(prefixes, stream)
+		}
+	}
+
+	err = containers.validate(tds)
+	return
+}
+
+// discoverStreams finds by prefix the streams with events within the window, including the ones of tasks long gone
+func discoverStreams(prefixes []logStream, since, until time.Time) (streams []logStream, err error) {
+	for _, prefix := range prefixes {
+		err = cwlI.DescribeLogStreamsPages(&cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName:        aws.String(prefix.group),
+			LogStreamNamePrefix: aws.String(prefix.name),
+		}, func(page *cloudwatchlogs.DescribeLogStreamsOutput, lastPage bool) bool {
+			for _, ls := range page.LogStreams {
+				if ls.LastEventTimestamp != nil && aws.Int64Value(ls.LastEventTimestamp) < aws.TimeUnixMilli(since) {
+					continue
+				}
+
+				if ls.FirstEventTimestamp != nil && aws.Int64Value(ls.FirstEventTimestamp) > aws.TimeUnixMilli(until) {
+					continue
+				}
+
+				name := aws.StringValue(ls.LogStreamName)
+				streams = append(streams, logStream{
+					group:     prefix.group,
+					prefix:    prefix.prefix,
+					name:      name,
+					container: prefix.container,
+					taskID:    taskID(name),
+				})
+			}
+			return !lastPage
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].group+streams[i].name < streams[j].group+streams[j].name
+	})
+	return
+}
+
+// streamEvents pages through the events of a single stream within the window
+func streamEvents(stream logStream, since, until time.Time, filterPattern string, fn func(event exportedEvent) error) (err error) {
+	var fnErr error
+
+	if filterPattern != "" {
+		err = cwlI.FilterLogEventsPages(&cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName:   aws.String(stream.group),
+			LogStreamNames: []*string{aws.String(stream.name)},
+			StartTime:      aws.Int64(aws.TimeUnixMilli(since)),
+			EndTime:        aws.Int64(aws.TimeUnixMilli(until)),
+			FilterPattern:  aws.String(filterPattern),
+		}, func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+			for _, e := range page.Events {
+				if fnErr = fn(exportedEvent{aws.Int64Value(e.Timestamp), aws.Int64Value(e.IngestionTime), stream.name, aws.StringValue(e.Message)}); fnErr != nil {
+					return false
+				}
+			}
+			return !lastPage
+		})
+		if err == nil {
+			err = fnErr
+		}
+		return
+	}
+
+	input := &cloudwatchlogs.GetLogEventsInput{
+		LogGroupName:  aws.String(stream.group),
+		LogStreamName: aws.String(stream.name),
+		StartTime:     aws.Int64(aws.TimeUnixMilli(since)),
+		EndTime:       aws.Int64(aws.TimeUnixMilli(until)),
+		StartFromHead: aws.Bool(true),
+	}
+
+	for {
+		page, err := cwlI.GetLogEvents(input)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range page.Events {
+			if err := fn(exportedEvent{aws.Int64Value(e.Timestamp), aws.Int64Value(e.IngestionTime), stream.name, aws.StringValue(e.Message)}); err != nil {
+				return err
+			}
+		}
+
+		// GetLogEvents returns the same token when the end of the stream is reached
+		if page.NextForwardToken == nil || aws.StringValue(page.NextForwardToken) == aws.StringValue(input.NextToken) {
+			return nil
+		}
+		input.NextToken = page.NextForwardToken
+	}
+}
+
+// exportStream writes the events of the stream as gzip compressed NDJSON.
+// The file is written under a temporary name, so an interrupted export never leaves a file looking complete.
+func exportStream(dir string, stream logStream, since, until time.Time, filterPattern string) (exported exportedStream, err error) {
+	exported = exportedStream{
+		Group:  stream.group,
+		Stream: stream.name,
+		File:   strings.ReplaceAll(strings.Trim(stream.group, "/"), "/", "_") + "__" + strings.ReplaceAll(stream.name, "/", "_") + ".ndjson.gz",
+	}
+
+	path := filepath.Join(dir, exported.File)
+	file, err := os.Create(path + ".partial")
+	if err != nil {
+		return
+	}
+	defer os.Remove(path + ".partial")
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(file, hash)}
+	zw := gzip.NewWriter(counter)
+	encoder := json.NewEncoder(zw)
+
+	err = streamEvents(stream, since, until, filterPattern, func(event exportedEvent) error {
+		exported.Events++
+		return encoder.Encode(event)
+	})
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+
+	exported.Bytes = counter.n
+	exported.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	err = os.Rename(path+".partial", path)
+	return
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.n += int64(n)
+	return
+}
+
+// alreadyExported tells if the file of the stream exists and matches its checksum on the manifest
+func alreadyExported(dir string, entry exportedStream) bool {
+	file, err := os.Open(filepath.Join(dir, entry.File))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return false
+	}
+	return hex.EncodeToString(hash.Sum(nil)) == entry.SHA256
+}
+
+func readExportManifest(dir string) (manifest exportManifest) {
+	content, err := os.ReadFile(filepath.Join(dir, exportManifestName))
+	if err != nil {
+		return
+	}
+
+	json.Unmarshal(content, &manifest)
+	return
+}
+
+func writeExportManifest(dir string, manifest exportManifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, exportManifestName)
+	if err := os.WriteFile(path+".partial", content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".partial", path)
+}
+
+// exportServiceLogs writes one file per stream and the manifest, which is updated after every stream.
+// Streams already exported with the same window are skipped, so an interrupted export can be resumed.
+func exportServiceLogs(dir, cluster string, s *ecs.Service, since, until time.Time, filterPattern string, containers containerFilter) (err error) {
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	prefixes, err := serviceStreamPrefixes(s, containers)
+	if err != nil {
+		return
+	}
+
+	streams, err := discoverStreams(prefixes, since, until)
+	if err != nil {
+		return
+	}
+
+	previous := readExportManifest(dir)
+	sameWindow := previous.Since.Equal(since) && previous.Until.Equal(until) && previous.FilterPattern == filterPattern
+
+	done := make(map[string]exportedStream)
+	if sameWindow {
+		for _, entry := range previous.Streams {
+			done[entry.Group+":"+entry.Stream] = entry
+		}
+	}
+
+	manifest := exportManifest{
+		Cluster:       cluster,
+		Service:       aws.StringValue(s.ServiceName),
+		Since:         since,
+		Until:         until,
+		FilterPattern: filterPattern,
+		Streams:       []exportedStream{},
+	}
+
+	var total int64
+	for i, stream := range streams {
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(streams), stream.name)
+
+		if entry, ok := done[stream.group+":"+stream.name]; ok && alreadyExported(dir, entry) {
+			fmt.Fprintf(os.Stderr, "%s: already exported, skipping\n", progress)
+			manifest.Streams = append(manifest.Streams, entry)
+			total += entry.Events
+			continue
+		}
+
+		entry, err := exportStream(dir, stream, since, until, filterPattern)
+		if err != nil {
+			return fmt.Errorf("%s: %s", progress, err.Error())
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %d events, %d bytes\n", progress, entry.Events, entry.Bytes)
+		manifest.Streams = append(manifest.Streams, entry)
+		total += entry.Events
+
+		if err := writeExportManifest(dir, manifest); err != nil {
+			return err
+		}
+	}
+
+	if err = writeExportManifest(dir, manifest); err != nil {
+		return
+	}
+
+	typist.Printf("%d events of %d streams exported to %s\n", total, len(streams), dir)
+	return
+}
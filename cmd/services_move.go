@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/gumieri/ecsctl/ecsx"
+	"github.com/spf13/cobra"
+)
+
+type servicesMoveOptions struct {
+	cluster        string
+	toStrategy     string
+	toLaunchType   string
+	subnets        []string
+	securityGroups []string
+	assignPublicIP bool
+	suffix         string
+	timeout        time.Duration
+	yes            bool
+	overrideFreeze bool
+}
+
+var servicesMoveOpts servicesMoveOptions
+
+// parseCapacityProviderStrategy reads provider:weight=N[,base=N] items separated by commas,
+// e.g. FARGATE_SPOT:weight=3,FARGATE:weight=1,base=1
+func parseCapacityProviderStrategy(value string) (strategy []*ecs.CapacityProviderStrategyItem, err error) {
+	invalid := errors.New("Invalid strategy '" + value + "', e.g. FARGATE_SPOT:weight=3,FARGATE:weight=1,base=1")
+
+	var item *ecs.CapacityProviderStrategyItem
+	for _, part := range strings.Split(value, ",") {
+		param := part
+		if kv := strings.SplitN(part, ":", 2); len(kv) == 2 || !strings.Contains(part, "=") {
+			if kv[0] == "" {
+				return nil, invalid
+			}
+
+			item = &ecs.CapacityProviderStrategyItem{CapacityProvider: aws.String(kv[0])}
+			strategy = append(strategy, item)
+
+			if len(kv) == 1 {
+				continue
+			}
+			param = kv[1]
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		if item == nil || len(kv) != 2 {
+			return nil, invalid
+		}
+
+		n, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil || n < 0 {
+			return nil, invalid
+		}
+
+		switch kv[0] {
+		case "weight":
+			item.Weight = aws.Int64(n)
+		case "base":
+			item.Base = aws.Int64(n)
+		default:
+			return nil, invalid
+		}
+	}
+
+	if len(strategy) == 0 {
+		return nil, invalid
+	}
+	return
+}
+
+func formatCapacityProviderStrategy(strategy []*ecs.CapacityProviderStrategyItem) string {
+	var items []string
+	for _, item := range strategy {
+		items = append(items, fmt.Sprintf("%s:weight=%d,base=%d", aws.StringValue(item.CapacityProvider), aws.Int64Value(item.Weight), aws.Int64Value(item.Base)))
+	}
+	return strings.Join(items, " ")
+}
+
+// currentPlacement describes where the tasks of the service run today
+func currentPlacement(s *ecs.Service) string {
+	if len(s.CapacityProviderStrategy) > 0 {
+		return formatCapacityProviderStrategy(s.CapacityProviderStrategy)
+	}
+	return "launch type " + aws.StringValue(s.LaunchType)
+}
+
+// moveStrategy changes the capacity provider strategy in place, with a forced deployment replacing every task
+func moveStrategy(cluster string, s *ecs.Service, strategy []*ecs.CapacityProviderStrategyItem, timeout time.Duration, yes bool) {
+	service := aws.StringValue(s.ServiceName)
+
+	typist.Printf("about to update %s from %s to %s, forcing a new deployment\n", service, currentPlacement(s), formatCapacityProviderStrategy(strategy))
+	if !yes && !typist.Confirm("Proceed?") {
+		return
+	}
+
+	_, err := ecsI.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:                  aws.String(cluster),
+		Service:                  s.ServiceName,
+		CapacityProviderStrategy: strategy,
+		ForceNewDeployment:       aws.Bool(true),
+	})
+	must(err)
+
+	failed, err := waitServices(cluster, []string{service}, timeout, false, false)
+	reportServicesWait(failed, err)
+
+	printAffected(aws.StringValue(s.ServiceArn), service+" moved to "+formatCapacityProviderStrategy(strategy))
+}
+
+// findService describes the service with its tags, returning nil when it does not exist or is not ACTIVE
+func findService(cluster, service string) (s *ecs.Service, err error) {
+	described, err := ecsxI.DescribeAllServices(cluster, []*string{aws.String(service)}, ecs.ServiceFieldTags)
+	if ecsx.IsMissing(err) {
+		return nil, nil
+	}
+
+	for _, d := range described {
+		if aws.StringValue(d.Status) == "ACTIVE" {
+			s = d
+		}
+	}
+	return
+}
+
+// moveLaunchType creates a parallel service on the launch type behind the same load balancers, waits for it,
+// then scales down and deletes the original. Each phase checks what was already done, so it can be run again to resume.
+func moveLaunchType(cluster string, original *ecs.Service, launchType string, opts *servicesMoveOptions) {
+	service := aws.StringValue(original.ServiceName)
+	target := service + opts.suffix
+
+	td, err := describeTaskDefinition(aws.StringValue(original.TaskDefinition))
+	must(err)
+
+	compatible := false
+	for _, c := range td.Compatibilities {
+		compatible = compatible || aws.StringValue(c) == launchType
+	}
+	if !compatible {
+		must(fmt.Errorf("%s is not compatible with %s, register a revision requiring it first", familyRevision(td.Family, td.Revision), launchType))
+	}
+
+	moved, err := findService(cluster, target)
+	must(err)
+
+	// Phase 1: the parallel service
+	if moved != nil {
+		typist.Printf("%s already exists, skipping its creation\n", target)
+	} else {
+		networkConfiguration := original.NetworkConfiguration
+		if networkConfiguration == nil || len(opts.subnets) > 0 || len(opts.securityGroups) > 0 {
+			networkConfiguration, err = runNetworkConfiguration(td, launchType, opts.subnets, opts.securityGroups, opts.assignPublicIP)
+			must(err)
+		}
+
+		typist.Printf("about to create %s on %s with %d tasks of %s, behind the same load balancers as %s\n",
+			target, launchType, aws.Int64Value(original.DesiredCount), familyRevision(td.Family, td.Revision), service)
+		if !opts.yes && !typist.Confirm("Proceed?") {
+			return
+		}
+
+		result, err := ecsI.CreateService(&ecs.CreateServiceInput{
+			Cluster:                       aws.String(cluster),
+			ServiceName:                   aws.String(target),
+			TaskDefinition:                original.TaskDefinition,
+			DesiredCount:                  original.DesiredCount,
+			LaunchType:                    aws.String(launchType),
+			LoadBalancers:                 original.LoadBalancers,
+			NetworkConfiguration:          networkConfiguration,
+			DeploymentConfiguration:       original.DeploymentConfiguration,
+			HealthCheckGracePeriodSeconds: original.HealthCheckGracePeriodSeconds,
+			ServiceRegistries:             original.ServiceRegistries,
+			Tags:                          resourceTags(original.Tags),
+		})
+		must(err)
+
+		moved = result.Service
+		printAffected(aws.StringValue(moved.ServiceArn), target+" created")
+	}
+
+	// Phase 2: the parallel service healthy
+	typist.Printf("waiting for %s to be ready\n", target)
+	failed, err := waitServices(cluster, []string{target}, opts.timeout, false, hasHealthCheck(td))
+	reportServicesWait(failed, err)
+
+	// Phase 3: the original scaled down
+	if aws.Int64Value(original.DesiredCount) > 0 {
+		must(checkServiceFreeze(cluster, service, opts.overrideFreeze))
+
+		typist.Printf("about to scale %s down from %d to 0 tasks, %s keeps serving\n", service, aws.Int64Value(original.DesiredCount), target)
+		if !opts.yes && !typist.Confirm("Proceed?") {
+			return
+		}
+
+		_, err = ecsI.UpdateService(&ecs.UpdateServiceInput{
+			Cluster:      aws.String(cluster),
+			Service:      original.ServiceName,
+			DesiredCount: aws.Int64(0),
+		})
+		must(err)
+
+		failed, err := waitServices(cluster, []string{service}, opts.timeout, false, false)
+		reportServicesWait(failed, err)
+	} else {
+		typist.Printf("%s is already scaled down, skipping\n", service)
+	}
+
+	// Phase 4: the original deleted, always confirmed
+	typist.Printf("about to delete %s\n", service)
+	if !typist.Confirm("Do you really want to delete " + service + "?") {
+		typist.Printf("%s kept with 0 tasks, run the move again to delete it\n", service)
+		return
+	}
+
+	_, err = ecsI.DeleteService(&ecs.DeleteServiceInput{
+		Cluster: aws.String(cluster),
+		Service: original.ServiceName,
+	})
+	must(err)
+
+	printAffected(aws.StringValue(moved.ServiceArn), service+" moved to "+target+" on "+launchType)
+}
+
+func servicesMoveRun(cmd *cobra.Command, args []string) {
+	opts := &servicesMoveOpts
+	service := args[0]
+
+	if (opts.toStrategy == "") == (opts.toLaunchType == "") {
+		must(errors.New("Inform either --to-strategy or --to-launch-type"))
+	}
+
+	if opts.toStrategy != "" && (len(opts.subnets) > 0 || len(opts.securityGroups) > 0) {
+		must(errors.New("--subnet and --security-group only apply to --to-launch-type"))
+	}
+
+	s, err := findService(opts.cluster, service)
+	must(err)
+
+	launchType := strings.ToUpper(opts.toLaunchType)
+
+	if s == nil {
+		// The original is deleted by the last phase, a resumed move has nothing left to do
+		if moved, err := findService(opts.cluster, service+opts.suffix); err == nil && moved != nil && launchType != "" {
+			printAffected(aws.StringValue(moved.ServiceArn), service+" was already moved to "+service+opts.suffix)
+			return
+		}
+		must(errors.New("Service informed not found"))
+	}
+
+	if opts.toStrategy != "" {
+		strategy, err := parseCapacityProviderStrategy(opts.toStrategy)
+		must(err)
+
+		must(checkServiceFreeze(opts.cluster, service, opts.overrideFreeze))
+		moveStrategy(opts.cluster, s, strategy, opts.timeout, opts.yes)
+		return
+	}
+
+	switch launchType {
+	case ecs.LaunchTypeEc2, ecs.LaunchTypeFargate:
+	default:
+		must(errors.New("Invalid --to-launch-type '" + opts.toLaunchType + "', expected EC2 or FARGATE"))
+	}
+
+	if aws.StringValue(s.LaunchType) == launchType {
+		must(fmt.Errorf("%s already runs on %s", service, launchType))
+	}
+
+	moveLaunchType(opts.cluster, s, launchType, opts)
+}
+
+var servicesMoveCmd = &cobra.Command{
+	Use:   "move [service]",
+	Short: "Move a service to another capacity provider strategy or launch type without downtime",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesMoveRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesMoveCmd)
+
+	flags := servicesMoveCmd.Flags()
+
+	flags.StringVarP(&servicesMoveOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesMoveOpts.toStrategy, "to-strategy", "", toStrategySpec)
+	flags.StringVar(&servicesMoveOpts.toLaunchType, "to-launch-type", "", toLaunchTypeSpec)
+	flags.StringSliceVarP(&servicesMoveOpts.subnets, "subnet", "n", []string{}, runSubnetsSpec)
+	flags.StringSliceVarP(&servicesMoveOpts.securityGroups, "security-group", "g", []string{}, runSecurityGroupsSpec)
+	pluralNetworkFlags(flags)
+	flags.BoolVar(&servicesMoveOpts.assignPublicIP, "assign-public-ip", false, assignPublicIPSpec)
+	flags.StringVar(&servicesMoveOpts.suffix, "suffix", "-moved", moveSuffixSpec)
+	flags.DurationVar(&servicesMoveOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVarP(&servicesMoveOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVar(&servicesMoveOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesMoveCmd.MarkFlagRequired("cluster")
+
+	// Moving to another strategy only updates the service, moving to another launch type deletes it
+	protectClusters(servicesMoveCmd, "scale down and delete the original service", func(args []string) []string {
+		if servicesMoveOpts.toLaunchType == "" {
+			return nil
+		}
+		return []string{servicesMoveOpts.cluster}
+	})
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesNetworkOptions struct {
+	cluster  string
+	canReach string
+	port     int64
+}
+
+var servicesNetworkOpts servicesNetworkOptions
+
+type serviceNetwork struct {
+	subnets        []*ec2.Subnet
+	securityGroups []*ec2.SecurityGroup
+}
+
+func describeServiceNetwork(s *ecs.Service) (network serviceNetwork, err error) {
+	if s.NetworkConfiguration == nil || s.NetworkConfiguration.AwsvpcConfiguration == nil {
+		err = fmt.Errorf("Service %s does not use the awsvpc network mode", aws.StringValue(s.ServiceName))
+		return
+	}
+
+	vpcConfiguration := s.NetworkConfiguration.AwsvpcConfiguration
+
+	subnets, err := ec2I.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: vpcConfiguration.Subnets,
+	})
+	if err != nil {
+		return
+	}
+
+	network.subnets = subnets.Subnets
+
+	if len(vpcConfiguration.SecurityGroups) == 0 {
+		return
+	}
+
+	securityGroups, err := ec2I.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: vpcConfiguration.SecurityGroups,
+	})
+	if err != nil {
+		return
+	}
+
+	network.securityGroups = securityGroups.SecurityGroups
+	return
+}
+
+// permissionCoversPort checks the protocol (tcp or all) and the port range of a rule
+func permissionCoversPort(permission *ec2.IpPermission, port int64) bool {
+	protocol := aws.StringValue(permission.IpProtocol)
+	if protocol == "-1" {
+		return true
+	}
+
+	if protocol != "tcp" && protocol != "6" {
+		return false
+	}
+
+	return aws.Int64Value(permission.FromPort) <= port && port <= aws.Int64Value(permission.ToPort)
+}
+
+func describePermission(permission *ec2.IpPermission) string {
+	ports := "all"
+	if aws.StringValue(permission.IpProtocol) != "-1" {
+		ports = fmt.Sprintf("%s %d", aws.StringValue(permission.IpProtocol), aws.Int64Value(permission.FromPort))
+		if aws.Int64Value(permission.ToPort) != aws.Int64Value(permission.FromPort) {
+			ports = fmt.Sprintf("%s-%d", ports, aws.Int64Value(permission.ToPort))
+		}
+	}
+
+	var sources []string
+	for _, r := range permission.IpRanges {
+		sources = append(sources, aws.StringValue(r.CidrIp))
+	}
+	for _, r := range permission.Ipv6Ranges {
+		sources = append(sources, aws.StringValue(r.CidrIpv6))
+	}
+	for _, p := range permission.UserIdGroupPairs {
+		sources = append(sources, aws.StringValue(p.GroupId))
+	}
+	for _, p := range permission.PrefixListIds {
+		sources = append(sources, aws.StringValue(p.PrefixListId))
+	}
+
+	return ports + " <- " + strings.Join(sources, ", ")
+}
+
+// cidrContains checks if the inner network is entirely inside the outer one
+func cidrContains(outer, inner string) bool {
+	_, outerNet, err := net.ParseCIDR(outer)
+	if err != nil {
+		return false
+	}
+
+	_, innerNet, err := net.ParseCIDR(inner)
+	if err != nil {
+		return false
+	}
+
+	outerOnes, _ := outerNet.Mask.Size()
+	innerOnes, _ := innerNet.Mask.Size()
+
+	return outerNet.Contains(innerNet.IP) && outerOnes <= innerOnes
+}
+
+// findAllowingRule looks for a rule of the groups allowing the traffic on the port
+// from (or to) any of the peer groups or all the peer subnets
+func findAllowingRule(groups []*ec2.SecurityGroup, egress bool, port int64, peer serviceNetwork) (string, bool) {
+	peerGroups := make(map[string]bool)
+	for _, sg := range peer.securityGroups {
+		peerGroups[aws.StringValue(sg.GroupId)] = true
+	}
+
+	for _, sg := range groups {
+		permissions := sg.IpPermissions
+		if egress {
+			permissions = sg.IpPermissionsEgress
+		}
+
+		for _, permission := range permissions {
+			if !permissionCoversPort(permission, port) {
+				continue
+			}
+
+			for _, pair := range permission.UserIdGroupPairs {
+				if peerGroups[aws.StringValue(pair.GroupId)] {
+					return aws.StringValue(sg.GroupId) + ": " + describePermission(permission), true
+				}
+			}
+
+			for _, r := range permission.IpRanges {
+				coversAll := len(peer.subnets) > 0
+				for _, subnet := range peer.subnets {
+					coversAll = coversAll && cidrContains(aws.StringValue(r.CidrIp), aws.StringValue(subnet.CidrBlock))
+				}
+
+				if coversAll {
+					return aws.StringValue(sg.GroupId) + ": " + describePermission(permission), true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+func printServiceNetwork(network serviceNetwork) {
+	typist.Println("Subnets:")
+	for _, subnet := range network.subnets {
+		typist.Printf("  %s\t%s\t%s\n", aws.StringValue(subnet.SubnetId), aws.StringValue(subnet.AvailabilityZone), aws.StringValue(subnet.CidrBlock))
+	}
+
+	typist.Println("Security Groups:")
+	for _, sg := range network.securityGroups {
+		typist.Printf("  %s (%s)\n", aws.StringValue(sg.GroupId), aws.StringValue(sg.GroupName))
+
+		if len(sg.IpPermissions) == 0 {
+			typist.Println("    no inbound rules")
+		}
+
+		for _, permission := range sg.IpPermissions {
+			typist.Printf("    %s\n", describePermission(permission))
+		}
+	}
+}
+
+func servicesNetworkRun(cmd *cobra.Command, args []string) {
+	opts := &servicesNetworkOpts
+
+	s, err := describeService(opts.cluster, args[0])
+	must(err)
+
+	network, err := describeServiceNetwork(s)
+	must(err)
+
+	if opts.canReach == "" {
+		printServiceNetwork(network)
+		return
+	}
+
+	if opts.port == 0 {
+		must(errors.New("--can-reach requires the --port"))
+	}
+
+	target, err := describeService(opts.cluster, opts.canReach)
+	must(err)
+
+	targetNetwork, err := describeServiceNetwork(target)
+	must(err)
+
+	reachable := true
+
+	if rule, ok := findAllowingRule(network.securityGroups, true, opts.port, targetNetwork); ok {
+		typist.Printf("egress allowed by %s\n", rule)
+	} else {
+		reachable = false
+		typist.Printf("egress to %s on port %d is not allowed by any security group of %s\n", opts.canReach, opts.port, args[0])
+	}
+
+	if rule, ok := findAllowingRule(targetNetwork.securityGroups, false, opts.port, network); ok {
+		typist.Printf("ingress allowed by %s\n", rule)
+	} else {
+		reachable = false
+		typist.Printf("ingress from %s on port %d is not allowed by any security group of %s\n", args[0], opts.port, opts.canReach)
+
+		if len(network.securityGroups) > 0 && len(targetNetwork.securityGroups) > 0 {
+			typist.Printf("  aws ec2 authorize-security-group-ingress --group-id %s --protocol tcp --port %d --source-group %s\n",
+				aws.StringValue(targetNetwork.securityGroups[0].GroupId), opts.port, aws.StringValue(network.securityGroups[0].GroupId))
+		}
+	}
+
+	if !reachable {
+		must(fmt.Errorf("%s can not reach %s on port %d", args[0], opts.canReach, opts.port))
+	}
+
+	typist.Printf("%s can reach %s on port %d\n", args[0], opts.canReach, opts.port)
+}
+
+var servicesNetworkCmd = &cobra.Command{
+	Use:         "network [service]",
+	Short:       "Show the subnets and security groups of a service and check its reachability",
+	Args:        cobra.ExactArgs(1),
+	Run:         servicesNetworkRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesNetworkCmd)
+
+	flags := servicesNetworkCmd.Flags()
+
+	flags.StringVarP(&servicesNetworkOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesNetworkOpts.canReach, "can-reach", "", canReachSpec)
+	flags.Int64VarP(&servicesNetworkOpts.port, "port", "p", 0, reachPortSpec)
+
+	servicesNetworkCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/gumieri/ecsctl/ecsx"
+	"github.com/spf13/cobra"
+)
+
+type servicesQuickstartOptions struct {
+	cluster           string
+	taskDefinition    string
+	port              int64
+	subnets           []string
+	securityGroups    []string
+	targetGroup       string
+	createTargetGroup bool
+	listener          string
+	path              string
+	desiredCount      int64
+	assignPublicIP    bool
+	tags              []string
+}
+
+var servicesQuickstartOpts servicesQuickstartOptions
+
+// targetGroupNamePattern is what ELB accepts as a target group name: up to 32 alphanumeric characters
+// or hyphens, not beginning nor ending with a hyphen
+var targetGroupNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,30}[A-Za-z0-9])?$`)
+
+// checkTargetGroupName tells why the target group named after the service can not be created, if it can not
+func checkTargetGroupName(name string) error {
+	if !targetGroupNamePattern.MatchString(name) {
+		return fmt.Errorf("the target group can not be named %s: up to 32 alphanumeric characters or hyphens are allowed, not beginning nor ending with a hyphen", name)
+	}
+
+	_, err := elbv2I.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{Names: []*string{aws.String(name)}})
+	switch {
+	case awsErrorCode(err) == elbv2.ErrCodeTargetGroupNotFoundException:
+		return nil
+	case err != nil:
+		return fmt.Errorf("target group %s: %s", name, err.Error())
+	}
+	return fmt.Errorf("a target group named %s already exists, use it with --target-group", name)
+}
+
+// removeExposure deletes the listener rule and the target group created for a service that could not be created,
+// warning about what is left behind
+func removeExposure(ruleArn, targetGroupArn string) {
+	if ruleArn != "" {
+		if _, err := elbv2I.DeleteRule(&elbv2.DeleteRuleInput{RuleArn: aws.String(ruleArn)}); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: listener rule %s left behind: %s\n", ruleArn, err.Error())
+		}
+	}
+
+	if _, err := elbv2I.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(targetGroupArn)}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: target group %s left behind: %s\n", targetGroupArn, err.Error())
+		return
+	}
+	typist.Printf("target group %s deleted\n", targetGroupArn)
+}
+
+// nextRulePriority returns the lowest priority greater than every rule already on the listener
+func nextRulePriority(listenerArn string) (priority int64, err error) {
+	rules, err := elbv2I.DescribeRules(&elbv2.DescribeRulesInput{
+		ListenerArn: aws.String(listenerArn),
+	})
+	if err != nil {
+		return
+	}
+
+	for _, rule := range rules.Rules {
+		// the default rule has the priority "default"
+		p, convErr := strconv.ParseInt(aws.StringValue(rule.Priority), 10, 64)
+		if convErr == nil && p > priority {
+			priority = p
+		}
+	}
+
+	priority = priority + 1
+	return
+}
+
+func servicesQuickstartRun(cmd *cobra.Command, args []string) {
+	opts := &servicesQuickstartOpts
+	serviceName := args[0]
+
+	if opts.path == "" {
+		opts.path = "/" + serviceName
+	}
+
+	tags, err := parseResourceTags(opts.tags)
+	must(err)
+
+	// Every problem found is collected so the user can fix all of them at once
+	var problems []string
+
+	if c, err := describeCluster(opts.cluster); err != nil || aws.StringValue(c.Status) != "ACTIVE" {
+		problems = append(problems, fmt.Sprintf("cluster %s not found or not ACTIVE", opts.cluster))
+	} else {
+		described, err := ecsxI.DescribeAllServices(opts.cluster, []*string{aws.String(serviceName)})
+		if !ecsx.IsMissing(err) {
+			must(err)
+		}
+
+		for _, s := range described {
+			if aws.StringValue(s.Status) != "INACTIVE" {
+				problems = append(problems, fmt.Sprintf("service %s already exists on cluster %s", serviceName, opts.cluster))
+			}
+		}
+	}
+
+	var td *ecs.TaskDefinition
+	var exposedContainer *ecs.ContainerDefinition
+
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(opts.taskDefinition),
+	})
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("task definition %s: %s", opts.taskDefinition, err.Error()))
+	} else {
+		td = tdDescription.TaskDefinition
+
+		if aws.StringValue(td.NetworkMode) != ecs.NetworkModeAwsvpc {
+			problems = append(problems, fmt.Sprintf("task definition %s uses network mode %s, quickstart requires awsvpc", opts.taskDefinition, aws.StringValue(td.NetworkMode)))
+		}
+
+		for _, cd := range td.ContainerDefinitions {
+			for _, pm := range cd.PortMappings {
+				if aws.Int64Value(pm.ContainerPort) == opts.port {
+					exposedContainer = cd
+				}
+			}
+		}
+
+		if exposedContainer == nil {
+			problems = append(problems, fmt.Sprintf("no container of %s maps the port %d", opts.taskDefinition, opts.port))
+		}
+	}
+
+	var subnetIDs []*string
+	var vpcID, vpcSubnet string
+	for _, s := range opts.subnets {
+		subnet, err := findSubnet(s)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		subnetIDs = append(subnetIDs, subnet.SubnetId)
+
+		switch {
+		case vpcID == "":
+			vpcID = aws.StringValue(subnet.VpcId)
+			vpcSubnet = s
+		case aws.StringValue(subnet.VpcId) != vpcID:
+			problems = append(problems, fmt.Sprintf("subnet %s is on %s but %s is on %s, the subnets must share a VPC",
+				s, aws.StringValue(subnet.VpcId), vpcSubnet, vpcID))
+		}
+	}
+
+	var securityGroupIDs []*string
+	for _, s := range opts.securityGroups {
+		sg, err := findSecurityGroup(s)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		securityGroupIDs = append(securityGroupIDs, sg.GroupId)
+	}
+
+	if opts.targetGroup != "" && opts.createTargetGroup {
+		problems = append(problems, "--target-group and --create-target-group are mutually exclusive")
+	}
+
+	if opts.createTargetGroup && opts.listener == "" {
+		problems = append(problems, "--create-target-group requires the --listener where the path rule will be created")
+	}
+
+	if opts.createTargetGroup {
+		if err := checkTargetGroupName(serviceName); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if opts.targetGroup != "" {
+		tgDescription, err := elbv2I.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+			TargetGroupArns: []*string{aws.String(opts.targetGroup)},
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("target group %s: %s", opts.targetGroup, err.Error()))
+		} else {
+			tg := tgDescription.TargetGroups[0]
+
+			if aws.StringValue(tg.TargetType) != elbv2.TargetTypeEnumIp {
+				problems = append(problems, fmt.Sprintf("target group %s has target type %s, awsvpc tasks require 'ip'", aws.StringValue(tg.TargetGroupName), aws.StringValue(tg.TargetType)))
+			}
+
+			if len(tg.LoadBalancerArns) == 0 {
+				problems = append(problems, fmt.Sprintf("target group %s is not associated with any load balancer listener", aws.StringValue(tg.TargetGroupName)))
+			}
+
+			if vpcID != "" && aws.StringValue(tg.VpcId) != vpcID {
+				problems = append(problems, fmt.Sprintf("target group %s is on %s but the subnets are on %s", aws.StringValue(tg.TargetGroupName), aws.StringValue(tg.VpcId), vpcID))
+			}
+		}
+	}
+
+	if opts.listener != "" {
+		_, err := elbv2I.DescribeListeners(&elbv2.DescribeListenersInput{
+			ListenerArns: []*string{aws.String(opts.listener)},
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("listener %s: %s", opts.listener, err.Error()))
+		}
+	}
+
+	var launchType string
+	if td != nil {
+		for _, compatibility := range td.RequiresCompatibilities {
+			if aws.StringValue(compatibility) == ecs.CompatibilityFargate {
+				launchType = ecs.LaunchTypeFargate
+			}
+		}
+	}
+
+	if launchType != "" {
+		capabilities, err := probeCluster(opts.cluster)
+		if err == nil {
+			err = capabilities.checkLaunchType(launchType)
+		}
+
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		must(errors.New("Unable to create the service:\n\t" + strings.Join(problems, "\n\t")))
+	}
+
+	targetGroupArn := opts.targetGroup
+	var ruleArn string
+
+	if opts.createTargetGroup {
+		tgResult, err := elbv2I.CreateTargetGroup(&elbv2.CreateTargetGroupInput{
+			Name:            aws.String(serviceName),
+			Port:            aws.Int64(opts.port),
+			Protocol:        aws.String(elbv2.ProtocolEnumHttp),
+			TargetType:      aws.String(elbv2.TargetTypeEnumIp),
+			VpcId:           aws.String(vpcID),
+			HealthCheckPath: aws.String(opts.path),
+		})
+		must(err)
+
+		targetGroupArn = aws.StringValue(tgResult.TargetGroups[0].TargetGroupArn)
+		typist.Printf("target group %s created\n", targetGroupArn)
+
+		priority, err := nextRulePriority(opts.listener)
+		if err != nil {
+			removeExposure("", targetGroupArn)
+			must(err)
+		}
+
+		rule, err := elbv2I.CreateRule(&elbv2.CreateRuleInput{
+			ListenerArn: aws.String(opts.listener),
+			Priority:    aws.Int64(priority),
+			Conditions: []*elbv2.RuleCondition{
+				{
+					Field:  aws.String("path-pattern"),
+					Values: []*string{aws.String(opts.path), aws.String(opts.path + "/*")},
+				},
+			},
+			Actions: []*elbv2.Action{
+				{
+					Type:           aws.String(elbv2.ActionTypeEnumForward),
+					TargetGroupArn: aws.String(targetGroupArn),
+				},
+			},
+		})
+		if err != nil {
+			removeExposure("", targetGroupArn)
+			must(err)
+		}
+
+		ruleArn = aws.StringValue(rule.Rules[0].RuleArn)
+		typist.Printf("listener rule for %s created with priority %d\n", opts.path, priority)
+	}
+
+	assignPublicIP := ecs.AssignPublicIpDisabled
+	if opts.assignPublicIP {
+		assignPublicIP = ecs.AssignPublicIpEnabled
+	}
+
+	input := &ecs.CreateServiceInput{
+		Cluster:        aws.String(opts.cluster),
+		ServiceName:    aws.String(serviceName),
+		TaskDefinition: td.TaskDefinitionArn,
+		DesiredCount:   aws.Int64(opts.desiredCount),
+		Tags:           resourceTags(tags),
+		NetworkConfiguration: &ecs.NetworkConfiguration{
+			AwsvpcConfiguration: &ecs.AwsVpcConfiguration{
+				Subnets:        subnetIDs,
+				SecurityGroups: securityGroupIDs,
+				AssignPublicIp: aws.String(assignPublicIP),
+			},
+		},
+	}
+
+	if launchType != "" {
+		input.LaunchType = aws.String(launchType)
+	}
+
+	if targetGroupArn != "" {
+		input.LoadBalancers = []*ecs.LoadBalancer{
+			{
+				TargetGroupArn: aws.String(targetGroupArn),
+				ContainerName:  exposedContainer.Name,
+				ContainerPort:  aws.Int64(opts.port),
+			},
+		}
+	}
+
+	result, err := ecsI.CreateService(input)
+	if err != nil && opts.createTargetGroup {
+		removeExposure(ruleArn, targetGroupArn)
+	}
+	must(err)
+
+	printAffected(aws.StringValue(result.Service.ServiceArn), aws.StringValue(result.Service.ServiceArn)+" created")
+
+	if targetGroupArn != "" {
+		return
+	}
+
+	typist.Println("")
+	typist.Println("The service is not exposed by any load balancer. To expose it run:")
+	typist.Printf("  aws elbv2 create-target-group --name %s --protocol HTTP --port %d --target-type ip --vpc-id %s --health-check-path %s\n", serviceName, opts.port, vpcID, opts.path)
+	typist.Printf("  aws elbv2 create-rule --listener-arn LISTENER_ARN --priority PRIORITY --conditions Field=path-pattern,Values='%s,%s/*' --actions Type=forward,TargetGroupArn=TARGET_GROUP_ARN\n", opts.path, opts.path)
+	typist.Printf("  aws ecs update-service --cluster %s --service %s --load-balancers targetGroupArn=TARGET_GROUP_ARN,containerName=%s,containerPort=%d\n", opts.cluster, serviceName, aws.StringValue(exposedContainer.Name), opts.port)
+	typist.Println("Or recreate it with: --create-target-group --listener LISTENER_ARN")
+}
+
+var servicesQuickstartCmd = &cobra.Command{
+	Use:     "quickstart [service]",
+	Short:   "Create an awsvpc service from a Task Definition, optionally exposed by a load balancer",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"create-from-taskdef"},
+	Run:     servicesQuickstartRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesQuickstartCmd)
+
+	flags := servicesQuickstartCmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&servicesQuickstartOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVarP(&servicesQuickstartOpts.taskDefinition, "task-definition", "d", "", requiredSpec+taskDefinitionSpec)
+	flags.Int64VarP(&servicesQuickstartOpts.port, "port", "p", 0, requiredSpec+portSpec)
+	flags.StringSliceVarP(&servicesQuickstartOpts.subnets, "subnet", "n", []string{}, requiredSpec+subnetsSpec)
+	flags.StringSliceVarP(&servicesQuickstartOpts.securityGroups, "security-group", "g", []string{}, securityGroupsSpec)
+	pluralNetworkFlags(flags)
+	flags.StringVar(&servicesQuickstartOpts.targetGroup, "target-group", "", targetGroupSpec)
+	flags.BoolVar(&servicesQuickstartOpts.createTargetGroup, "create-target-group", false, createTargetGroupSpec)
+	flags.StringVar(&servicesQuickstartOpts.listener, "listener", "", listenerSpec)
+	flags.StringVar(&servicesQuickstartOpts.path, "path", "", pathSpec)
+	flags.Int64Var(&servicesQuickstartOpts.desiredCount, "desired-count", 1, desiredCountSpec)
+	flags.BoolVar(&servicesQuickstartOpts.assignPublicIP, "assign-public-ip", false, assignPublicIPSpec)
+	flags.StringSliceVarP(&servicesQuickstartOpts.tags, "tag", "t", []string{}, resourceTagsSpec)
+
+	servicesQuickstartCmd.MarkFlagRequired("cluster")
+	servicesQuickstartCmd.MarkFlagRequired("task-definition")
+	servicesQuickstartCmd.MarkFlagRequired("port")
+	servicesQuickstartCmd.MarkFlagRequired("subnet")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesRestartTaskOptions struct {
+	cluster        string
+	task           string
+	wait           bool
+	timeout        time.Duration
+	force          bool
+	overrideFreeze bool
+}
+
+var servicesRestartTaskOpts servicesRestartTaskOptions
+
+// replacementReady tells if a RUNNING task is ready to replace the stopped one: HEALTHY when its
+// Task Definition declares a health check, otherwise the UNKNOWN status it keeps is accepted
+func replacementReady(t *ecs.Task, healthChecked bool) bool {
+	switch aws.StringValue(t.HealthStatus) {
+	case ecs.HealthStatusHealthy:
+		return true
+	case ecs.HealthStatusUnknown, "":
+		return !healthChecked
+	}
+	return false
+}
+
+// waitReplacementTask waits until the service has a task, not known before, RUNNING and ready
+func waitReplacementTask(cluster, service string, known map[string]bool, timeout time.Duration) (replacement *ecs.Task, err error) {
+	ctx := interruptContext()
+	deadline := time.Now().Add(timeout)
+	healthChecked := make(map[string]bool)
+
+	for time.Now().Before(deadline) {
+		if !sleepContext(ctx, 5*time.Second) {
+			return nil, errors.New("Interrupted while waiting for the replacement task, the task was already stopped")
+		}
+
+		tasks, err := serviceTasks(cluster, service, ecs.DesiredStatusRunning)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range tasks {
+			if known[aws.StringValue(t.TaskArn)] || aws.StringValue(t.LastStatus) != ecs.DesiredStatusRunning {
+				continue
+			}
+
+			arn := aws.StringValue(t.TaskDefinitionArn)
+			if _, ok := healthChecked[arn]; !ok {
+				td, err := describeTaskDefinition(arn)
+				if err != nil {
+					return nil, err
+				}
+				healthChecked[arn] = hasHealthCheck(td)
+			}
+
+			if replacementReady(t, healthChecked[arn]) {
+				return t, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("No replacement task became RUNNING and HEALTHY within %s", timeout)
+}
+
+func servicesRestartTaskRun(cmd *cobra.Command, args []string) {
+	opts := &servicesRestartTaskOpts
+	service := args[0]
+
+	s, err := describeService(opts.cluster, service)
+	must(err)
+
+	must(checkServiceFreeze(opts.cluster, service, opts.overrideFreeze))
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(opts.task)})
+	must(err)
+
+	if len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	t := tasks[0]
+	if aws.StringValue(t.Group) != "service:"+aws.StringValue(s.ServiceName) {
+		must(fmt.Errorf("Task %s does not belong to the service %s", opts.task, service))
+	}
+
+	if aws.Int64Value(s.RunningCount) < aws.Int64Value(s.DesiredCount) && !opts.force {
+		must(fmt.Errorf("Service %s is running %d of %d desired tasks, stopping one more could make it worse. Use --force to restart anyway",
+			service, aws.Int64Value(s.RunningCount), aws.Int64Value(s.DesiredCount)))
+	}
+
+	running, err := serviceTasks(opts.cluster, service, ecs.DesiredStatusRunning)
+	must(err)
+
+	known := make(map[string]bool)
+	for _, rt := range running {
+		known[aws.StringValue(rt.TaskArn)] = true
+	}
+
+	_, err = ecsI.StopTask(&ecs.StopTaskInput{
+		Cluster: aws.String(opts.cluster),
+		Task:    t.TaskArn,
+		Reason:  aws.String("Restarted by ecsctl services restart-task"),
+	})
+	must(err)
+
+	oldID := taskID(aws.StringValue(t.TaskArn))
+
+	if !opts.wait {
+		printAffected(oldID, oldID+" stopped, the service will replace it")
+		return
+	}
+
+	typist.Printf("%s stopped, waiting for the replacement\n", oldID)
+
+	replacement, err := waitReplacementTask(opts.cluster, service, known, opts.timeout)
+	must(err)
+
+	newID := taskID(aws.StringValue(replacement.TaskArn))
+	printAffected(newID, fmt.Sprintf("%s replaced by %s", oldID, newID))
+}
+
+var servicesRestartTaskCmd = &cobra.Command{
+	Use:   "restart-task [service]",
+	Short: "Replace a single task of a service",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesRestartTaskRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesRestartTaskCmd)
+
+	flags := servicesRestartTaskCmd.Flags()
+
+	flags.StringVarP(&servicesRestartTaskOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesRestartTaskOpts.task, "task", "", requiredSpec+restartTaskSpec)
+	flags.BoolVarP(&servicesRestartTaskOpts.wait, "wait", "w", false, waitReplacementSpec)
+	flags.DurationVar(&servicesRestartTaskOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVarP(&servicesRestartTaskOpts.force, "force", "f", false, forceRestartSpec)
+	flags.BoolVar(&servicesRestartTaskOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesRestartTaskCmd.MarkFlagRequired("cluster")
+	servicesRestartTaskCmd.MarkFlagRequired("task")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+func TestReplacementReady(t *testing.T) {
+	tests := []struct {
+		health        string
+		healthChecked bool
+		want          bool
+	}{
+		{ecs.HealthStatusHealthy, true, true},
+		{ecs.HealthStatusUnknown, true, false},
+		{"", true, false},
+		{ecs.HealthStatusUnhealthy, true, false},
+		{ecs.HealthStatusUnknown, false, true},
+		{"", false, true},
+		{ecs.HealthStatusUnhealthy, false, false},
+	}
+
+	for _, test := range tests {
+		task := &ecs.Task{HealthStatus: aws.String(test.health)}
+		if got := replacementReady(task, test.healthChecked); got != test.want {
+			t.Errorf("replacementReady(%q, %v) = %v, want %v", test.health, test.healthChecked, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesRollbackOptions struct {
+	cluster        string
+	to             string
+	wait           bool
+	timeout        time.Duration
+	yes            bool
+	overrideFreeze bool
+}
+
+var servicesRollbackOpts servicesRollbackOptions
+
+// previousActiveRevision is the latest ACTIVE revision of the family before the revision, 0 when there is none
+func previousActiveRevision(family string, revision int64) (previous int64, err error) {
+	input := &ecs.ListTaskDefinitionsInput{
+		FamilyPrefix: aws.String(family),
+		Status:       aws.String(ecs.TaskDefinitionStatusActive),
+		Sort:         aws.String(ecs.SortOrderDesc),
+	}
+
+	err = ecsI.ListTaskDefinitionsPages(input, func(page *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+		for _, arn := range page.TaskDefinitionArns {
+			// FamilyPrefix also matches other families starting with the same name
+			if f, r := splitTaskDefinitionArn(aws.StringValue(arn)); f == family && r < revision {
+				previous = r
+				return false
+			}
+		}
+		return !lastPage
+	})
+	return
+}
+
+// rollbackRevision is the revision to roll the service back to: the one informed, the one of a deployment
+// being replaced, or else the revision before the current one, which must still be ACTIVE
+func rollbackRevision(s *ecs.Service, current *ecs.TaskDefinition, to string) (target string, err error) {
+	family := aws.StringValue(current.Family)
+	revision := aws.Int64Value(current.Revision)
+
+	if to != "" {
+		n, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("Invalid --to '%s', expected a revision number of %s", to, family)
+		}
+
+		if n == revision {
+			return "", fmt.Errorf("%s is already on %s:%d", aws.StringValue(s.ServiceName), family, n)
+		}
+		return fmt.Sprintf("%s:%d", family, n), nil
+	}
+
+	// A deployment still running the tasks being replaced is what the service was on before
+	for _, d := range s.Deployments {
+		if aws.StringValue(d.Status) == "ACTIVE" && aws.StringValue(d.TaskDefinition) != aws.StringValue(current.TaskDefinitionArn) {
+			return aws.StringValue(d.TaskDefinition), nil
+		}
+	}
+
+	if revision == 1 {
+		return "", fmt.Errorf("%s is on %s:1, there is no previous revision to roll back to", aws.StringValue(s.ServiceName), family)
+	}
+
+	previous, err := previousActiveRevision(family, revision)
+	if err != nil {
+		return "", err
+	}
+
+	if previous != revision-1 {
+		message := fmt.Sprintf("The previous revision %s:%d is INACTIVE, it can not be deployed anymore.", family, revision-1)
+		if previous == 0 {
+			return "", errors.New(message + " No revision before it is ACTIVE either")
+		}
+		return "", fmt.Errorf("%s Use --to %d to roll back to the latest ACTIVE revision before it, and services deploy --keep-previous to keep the revisions it replaces", message, previous)
+	}
+	return fmt.Sprintf("%s:%d", family, previous), nil
+}
+
+func servicesRollbackRun(cmd *cobra.Command, args []string) {
+	opts := &servicesRollbackOpts
+	service := args[0]
+
+	c, err := describeCluster(opts.cluster)
+	must(err)
+	cluster := aws.StringValue(c.ClusterName)
+
+	s, err := describeService(cluster, service)
+	must(err)
+
+	must(checkServiceFreeze(cluster, service, opts.overrideFreeze))
+
+	current, err := describeTaskDefinition(aws.StringValue(s.TaskDefinition))
+	must(err)
+
+	reference, err := rollbackRevision(s, current, opts.to)
+	must(err)
+
+	target, err := describeTaskDefinition(reference)
+	must(err)
+
+	from := familyRevision(current.Family, current.Revision)
+	to := familyRevision(target.Family, target.Revision)
+
+	if aws.StringValue(target.Status) != ecs.TaskDefinitionStatusActive {
+		must(fmt.Errorf("%s is %s, it can not be deployed anymore", to, aws.StringValue(target.Status)))
+	}
+
+	if !opts.yes && !typist.Confirm(fmt.Sprintf("Do you really want to roll %s back from %s to %s?", service, from, to)) {
+		return
+	}
+
+	_, err = ecsI.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:        aws.String(cluster),
+		Service:        s.ServiceName,
+		TaskDefinition: aws.String(to),
+	})
+	must(err)
+
+	printAffected(aws.StringValue(target.TaskDefinitionArn), fmt.Sprintf("%s rolled back from %s to %s", service, from, to))
+
+	if opts.wait {
+		failed, err := waitServices(cluster, []string{service}, opts.timeout, false, false)
+		reportServicesWait(failed, err)
+	}
+}
+
+var servicesRollbackCmd = &cobra.Command{
+	Use:   "rollback [service]",
+	Short: "Put a service back on its previous Task Definition revision",
+	Long: `Put a service back on its previous Task Definition revision: the one still running during a deployment,
+otherwise the revision before the current one, or the one informed with --to.
+services deploy deregisters the revision it replaces when registering a new image, and INACTIVE revisions can not be
+deployed anymore, so deploy with --keep-previous the services to be rolled back without --to.`,
+	Args: cobra.ExactArgs(1),
+	Run:  servicesRollbackRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesRollbackCmd)
+
+	flags := servicesRollbackCmd.Flags()
+
+	flags.StringVarP(&servicesRollbackOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesRollbackOpts.to, "to", "", rollbackToSpec)
+	flags.BoolVarP(&servicesRollbackOpts.wait, "wait", "w", false, waitSpec)
+	flags.DurationVar(&servicesRollbackOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVarP(&servicesRollbackOpts.yes, "yes", "y", false, yesSpec)
+	flags.BoolVar(&servicesRollbackOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesRollbackCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesScaleOptions struct {
+	cluster        string
+	desiredCount   int64
+	wait           bool
+	timeout        time.Duration
+	overrideFreeze bool
+}
+
+var servicesScaleOpts servicesScaleOptions
+
+func servicesScaleRun(cmd *cobra.Command, services []string) {
+	opts := &servicesScaleOpts
+
+	if opts.desiredCount < 0 {
+		must(errors.New("--desired-count can not be negative"))
+	}
+
+	for _, service := range services {
+		must(checkServiceFreeze(opts.cluster, service, opts.overrideFreeze))
+	}
+
+	var scaled []string
+	for _, service := range services {
+		s, err := describeService(opts.cluster, service)
+		must(err)
+
+		result, err := ecsI.UpdateService(&ecs.UpdateServiceInput{
+			Cluster:      aws.String(opts.cluster),
+			Service:      s.ServiceName,
+			DesiredCount: aws.Int64(opts.desiredCount),
+		})
+		must(err)
+
+		printAffected(aws.StringValue(result.Service.ServiceArn),
+			fmt.Sprintf("%s scaled from %d to %d", aws.StringValue(s.ServiceName), aws.Int64Value(s.DesiredCount), opts.desiredCount))
+		scaled = append(scaled, aws.StringValue(s.ServiceName))
+	}
+
+	if opts.wait {
+		failed, err := waitServices(opts.cluster, scaled, opts.timeout, false, false)
+		reportServicesWait(failed, err)
+	}
+}
+
+var servicesScaleCmd = &cobra.Command{
+	Use:   "scale [services...]",
+	Short: "Change the desired count of services, optionally waiting for them to run it",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesScaleRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesScaleCmd)
+
+	flags := servicesScaleCmd.Flags()
+
+	flags.StringVarP(&servicesScaleOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.Int64Var(&servicesScaleOpts.desiredCount, "desired-count", 0, requiredSpec+desiredCountSpec)
+	flags.BoolVarP(&servicesScaleOpts.wait, "wait", "w", false, scaleWaitSpec)
+	flags.DurationVar(&servicesScaleOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVar(&servicesScaleOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesScaleCmd.MarkFlagRequired("cluster")
+	servicesScaleCmd.MarkFlagRequired("desired-count")
+
+	protectClusters(servicesScaleCmd, "scale services to 0", func(args []string) []string {
+		if servicesScaleOpts.desiredCount > 0 {
+			return nil
+		}
+		return []string{servicesScaleOpts.cluster}
+	})
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/spf13/cobra"
+)
+
+type servicesScaleScheduleOptions struct {
+	cluster        string
+	up             string
+	upCount        int64
+	down           string
+	downCount      int64
+	timezone       string
+	list           bool
+	delete         bool
+	overrideFreeze bool
+}
+
+var servicesScaleScheduleOpts servicesScaleScheduleOptions
+
+// scheduledScalingAction is the name of the scheduled actions created by ecsctl, e.g. ecsctl-api-up
+func scheduledScalingAction(service, direction string) string {
+	return "ecsctl-" + service + "-" + direction
+}
+
+func serviceScalableResourceID(cluster, service string) string {
+	return "service/" + cluster + "/" + service
+}
+
+// describeCronSchedule shows when the schedule runs next, on its timezone and on the local time
+func describeCronSchedule(expression, timezone string) string {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "invalid timezone"
+	}
+
+	c, err := parseCron(expression)
+	if err != nil {
+		return "unknown"
+	}
+
+	next, found := c.next(time.Now().In(location))
+	if !found {
+		return "never"
+	}
+
+	return fmt.Sprintf("%s (%s local)", next.Format("Mon 2006-01-02 15:04 MST"), next.Local().Format("Mon 15:04 MST"))
+}
+
+func listScheduledScaling(resourceID string) {
+	var actions []*applicationautoscaling.ScheduledAction
+	err := aasI.DescribeScheduledActionsPages(&applicationautoscaling.DescribeScheduledActionsInput{
+		ServiceNamespace:  aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceId:        aws.String(resourceID),
+		ScalableDimension: aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+	}, func(page *applicationautoscaling.DescribeScheduledActionsOutput, lastPage bool) bool {
+		actions = append(actions, page.ScheduledActions...)
+		return !lastPage
+	})
+	must(err)
+
+	if quiet {
+		for _, action := range actions {
+			printID(aws.StringValue(action.ScheduledActionName))
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tTIMEZONE\tCOUNT\tNEXT RUN")
+	for _, action := range actions {
+		timezone := aws.StringValue(action.Timezone)
+		if timezone == "" {
+			timezone = "UTC"
+		}
+
+		count := "-"
+		if target := action.ScalableTargetAction; target != nil {
+			count = fmt.Sprintf("%d-%d", aws.Int64Value(target.MinCapacity), aws.Int64Value(target.MaxCapacity))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			aws.StringValue(action.ScheduledActionName),
+			aws.StringValue(action.Schedule),
+			timezone,
+			count,
+			describeCronSchedule(aws.StringValue(action.Schedule), timezone),
+		)
+	}
+	w.Flush()
+}
+
+func servicesScaleScheduleRun(cmd *cobra.Command, args []string) {
+	opts := &servicesScaleScheduleOpts
+	service := args[0]
+	resourceID := serviceScalableResourceID(opts.cluster, service)
+
+	if opts.list {
+		listScheduledScaling(resourceID)
+		return
+	}
+
+	if opts.delete {
+		for _, direction := range []string{"up", "down"} {
+			name := scheduledScalingAction(service, direction)
+			_, err := aasI.DeleteScheduledAction(&applicationautoscaling.DeleteScheduledActionInput{
+				ServiceNamespace:    aws.String(applicationautoscaling.ServiceNamespaceEcs),
+				ResourceId:          aws.String(resourceID),
+				ScalableDimension:   aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+				ScheduledActionName: aws.String(name),
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+				continue
+			}
+
+			printAffected(name, name+" deleted")
+		}
+		return
+	}
+
+	if opts.up == "" && opts.down == "" {
+		must(errors.New("Inform --up and/or --down, or use --list or --delete"))
+	}
+
+	if opts.up != "" && !cmd.Flags().Changed("up-count") {
+		must(errors.New("--up requires --up-count"))
+	}
+
+	if opts.down != "" && !cmd.Flags().Changed("down-count") {
+		must(errors.New("--down requires --down-count"))
+	}
+
+	_, err := time.LoadLocation(opts.timezone)
+	must(err)
+
+	for _, expression := range []string{opts.up, opts.down} {
+		if expression != "" {
+			_, err := parseCron(expression)
+			must(err)
+		}
+	}
+
+	_, err = describeService(opts.cluster, service)
+	must(err)
+
+	must(checkServiceFreeze(opts.cluster, service, opts.overrideFreeze))
+
+	targets, err := aasI.DescribeScalableTargets(&applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace:  aws.String(applicationautoscaling.ServiceNamespaceEcs),
+		ResourceIds:       []*string{aws.String(resourceID)},
+		ScalableDimension: aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+	})
+	must(err)
+
+	// An existing target keeps its capacity limits, they may be used by other scaling policies
+	if len(targets.ScalableTargets) == 0 {
+		minCapacity, maxCapacity := opts.downCount, opts.upCount
+		if opts.up == "" {
+			maxCapacity = opts.downCount
+		}
+		if opts.down == "" {
+			minCapacity = opts.upCount
+		}
+
+		_, err = aasI.RegisterScalableTarget(&applicationautoscaling.RegisterScalableTargetInput{
+			ServiceNamespace:  aws.String(applicationautoscaling.ServiceNamespaceEcs),
+			ResourceId:        aws.String(resourceID),
+			ScalableDimension: aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			MinCapacity:       aws.Int64(minCapacity),
+			MaxCapacity:       aws.Int64(maxCapacity),
+		})
+		must(err)
+	}
+
+	actions := []struct {
+		direction string
+		schedule  string
+		count     int64
+	}{
+		{"up", opts.up, opts.upCount},
+		{"down", opts.down, opts.downCount},
+	}
+
+	for _, action := range actions {
+		if action.schedule == "" {
+			continue
+		}
+
+		_, err = aasI.PutScheduledAction(&applicationautoscaling.PutScheduledActionInput{
+			ServiceNamespace:    aws.String(applicationautoscaling.ServiceNamespaceEcs),
+			ResourceId:          aws.String(resourceID),
+			ScalableDimension:   aws.String(applicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ScheduledActionName: aws.String(scheduledScalingAction(service, action.direction)),
+			Schedule:            aws.String(action.schedule),
+			Timezone:            aws.String(opts.timezone),
+			ScalableTargetAction: &applicationautoscaling.ScalableTargetAction{
+				MinCapacity: aws.Int64(action.count),
+				MaxCapacity: aws.Int64(action.count),
+			},
+		})
+		must(err)
+	}
+
+	listScheduledScaling(resourceID)
+}
+
+var servicesScaleScheduleCmd = &cobra.Command{
+	Use:   "scale-schedule [service]",
+	Short: "Scale a service up and down on a recurring schedule",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesScaleScheduleRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesScaleScheduleCmd)
+
+	flags := servicesScaleScheduleCmd.Flags()
+
+	flags.StringVarP(&servicesScaleScheduleOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesScaleScheduleOpts.up, "up", "", scheduleUpSpec)
+	flags.Int64Var(&servicesScaleScheduleOpts.upCount, "up-count", 0, scheduleUpCountSpec)
+	flags.StringVar(&servicesScaleScheduleOpts.down, "down", "", scheduleDownSpec)
+	flags.Int64Var(&servicesScaleScheduleOpts.downCount, "down-count", 0, scheduleDownCountSpec)
+	flags.StringVar(&servicesScaleScheduleOpts.timezone, "timezone", "UTC", scheduleTimezoneSpec)
+	flags.BoolVar(&servicesScaleScheduleOpts.list, "list", false, listScheduleSpec)
+	flags.BoolVar(&servicesScaleScheduleOpts.delete, "delete", false, deleteScheduleSpec)
+	flags.BoolVar(&servicesScaleScheduleOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesScaleScheduleCmd.MarkFlagRequired("cluster")
+
+	protectClusters(servicesScaleScheduleCmd, "schedule the service scaling to 0", func(args []string) []string {
+		if servicesScaleScheduleOpts.down == "" || servicesScaleScheduleOpts.downCount > 0 {
+			return nil
+		}
+		return []string{servicesScaleScheduleOpts.cluster}
+	})
+}
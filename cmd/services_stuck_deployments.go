@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesStuckDeploymentsOptions struct {
+	cluster     string
+	allClusters bool
+	olderThan   time.Duration
+}
+
+var servicesStuckDeploymentsOpts servicesStuckDeploymentsOptions
+
+type stuckDeployment struct {
+	Cluster      string    `json:"cluster"`
+	Service      string    `json:"service"`
+	DeploymentID string    `json:"deploymentId"`
+	Status       string    `json:"status"`
+	Rollout      string    `json:"rolloutState,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Age          string    `json:"age"`
+	Desired      int64     `json:"desired"`
+	Running      int64     `json:"running"`
+	Pending      int64     `json:"pending"`
+	Failed       int64     `json:"failed"`
+	LastEvent    string    `json:"lastEvent,omitempty"`
+}
+
+// lastRelevantEvent skips the "has reached a steady state" events, which tell nothing about why it is stuck
+func lastRelevantEvent(s *ecs.Service) string {
+	for _, event := range s.Events {
+		message := aws.StringValue(event.Message)
+		if !strings.Contains(message, "has reached a steady state") {
+			return aws.TimeValue(event.CreatedAt).Format(time.RFC3339) + " " + message
+		}
+	}
+	return ""
+}
+
+// stuckDeployments finds the deployments not converging for longer than the threshold:
+// any deployment that is not the PRIMARY one, and the PRIMARY while IN_PROGRESS
+func stuckDeployments(cluster string, olderThan time.Duration) (stuck []stuckDeployment, err error) {
+	serviceArns, err := listServices(cluster)
+	if err != nil {
+		return
+	}
+
+	services, err := describeServices(cluster, serviceArns)
+	if err != nil {
+		return
+	}
+
+	for _, s := range services {
+		for _, d := range s.Deployments {
+			status := aws.StringValue(d.Status)
+			rollout := aws.StringValue(d.RolloutState)
+
+			if status == "PRIMARY" && rollout != ecs.DeploymentRolloutStateInProgress {
+				continue
+			}
+
+			age := time.Since(aws.TimeValue(d.CreatedAt))
+			if age < olderThan {
+				continue
+			}
+
+			stuck = append(stuck, stuckDeployment{
+				Cluster:      cluster[strings.LastIndex(cluster, "/")+1:],
+				Service:      aws.StringValue(s.ServiceName),
+				DeploymentID: aws.StringValue(d.Id),
+				Status:       status,
+				Rollout:      rollout,
+				CreatedAt:    aws.TimeValue(d.CreatedAt),
+				Age:          age.Round(time.Minute).String(),
+				Desired:      aws.Int64Value(d.DesiredCount),
+				Running:      aws.Int64Value(d.RunningCount),
+				Pending:      aws.Int64Value(d.PendingCount),
+				Failed:       aws.Int64Value(d.FailedTasks),
+				LastEvent:    lastRelevantEvent(s),
+			})
+		}
+	}
+	return
+}
+
+func servicesStuckDeploymentsRun(cmd *cobra.Command, args []string) {
+	opts := &servicesStuckDeploymentsOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	if !opts.allClusters && opts.cluster == "" {
+		must(errors.New("Inform a --cluster or use --all-clusters"))
+	}
+
+	clusters := []*string{aws.String(opts.cluster)}
+	if opts.allClusters {
+		var err error
+		clusters, err = listClusters()
+		must(err)
+	}
+
+	stuck := []stuckDeployment{}
+	for _, cluster := range clusters {
+		found, err := stuckDeployments(aws.StringValue(cluster), opts.olderThan)
+		must(err)
+
+		stuck = append(stuck, found...)
+	}
+
+	switch outputFormat {
+	case "json":
+		must(printVersionedJSON(stuck))
+	case "text", "table":
+		if quiet {
+			for _, d := range stuck {
+				printID(d.Cluster + "/" + d.Service)
+			}
+			break
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CLUSTER\tSERVICE\tDEPLOYMENT\tSTATUS\tAGE\tRUNNING/DESIRED\tPENDING\tFAILED\tLAST EVENT")
+		for _, d := range stuck {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%d\t%d\t%s\n",
+				d.Cluster, d.Service, d.DeploymentID, strings.TrimSuffix(d.Status+" "+d.Rollout, " "), d.Age,
+				d.Running, d.Desired, d.Pending, d.Failed, d.LastEvent)
+		}
+		w.Flush()
+	}
+
+	if len(stuck) > 0 {
+		exit(1)
+	}
+}
+
+var servicesStuckDeploymentsCmd = &cobra.Command{
+	Use:         "stuck-deployments",
+	Short:       "List deployments not converging for too long, exiting with error when any is found",
+	Args:        cobra.NoArgs,
+	Run:         servicesStuckDeploymentsRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesStuckDeploymentsCmd)
+
+	registerOutputSchema(servicesStuckDeploymentsCmd, "services stuck-deployments", []stuckDeployment{})
+
+	flags := servicesStuckDeploymentsCmd.Flags()
+
+	flags.StringVarP(&servicesStuckDeploymentsOpts.cluster, "cluster", "c", "", clusterSpec)
+	flags.BoolVar(&servicesStuckDeploymentsOpts.allClusters, "all-clusters", false, allClustersSpec)
+	flags.DurationVar(&servicesStuckDeploymentsOpts.olderThan, "older-than", 30*time.Minute, olderThanSpec)
+}
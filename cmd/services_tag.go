@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesTagOptions struct {
+	cluster     string
+	tags        []string
+	stdin       bool
+	yes         bool
+	concurrency int
+}
+
+var servicesTagOpts servicesTagOptions
+
+// parseResourceTags parses 'key=value' pairs to ECS resource tags
+func parseResourceTags(tags []string) (parsed []*ecs.Tag, err error) {
+	for _, kv := range tags {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 || kvs[0] == "" {
+			err = errors.New("Invalid tag '" + kv + "', expected 'key=value'")
+			return
+		}
+
+		parsed = append(parsed, &ecs.Tag{
+			Key:   aws.String(kvs[0]),
+			Value: aws.String(kvs[1]),
+		})
+	}
+	return
+}
+
+func servicesTagRun(cmd *cobra.Command, args []string) {
+	opts := &servicesTagOpts
+
+	tags, err := parseResourceTags(opts.tags)
+	must(err)
+
+	if len(tags) == 0 {
+		must(errors.New("No --tag informed"))
+	}
+
+	services, err := batchItems(args, opts.stdin, servicePattern, "services")
+	must(err)
+
+	if opts.stdin && !opts.yes {
+		typist.Printf("%d services to be tagged\n", len(services))
+
+		if !confirmBatch("Do you really want to tag these services?", opts.stdin) {
+			return
+		}
+	}
+
+	failures := runBatch(services, opts.concurrency, func(service string) error {
+		serviceArn := service
+
+		if !strings.HasPrefix(service, "arn:") {
+			described, err := describeServices(opts.cluster, []*string{aws.String(service)})
+			if err != nil {
+				return err
+			}
+
+			if len(described) == 0 {
+				return errors.New("service not found")
+			}
+
+			serviceArn = aws.StringValue(described[0].ServiceArn)
+		}
+
+		_, err := ecsI.TagResource(&ecs.TagResourceInput{
+			ResourceArn: aws.String(serviceArn),
+			Tags:        tags,
+		})
+		return err
+	})
+
+	reportBatch(len(services), failures)
+}
+
+var servicesTagCmd = &cobra.Command{
+	Use:   "tag [services...]",
+	Short: "Tag services",
+	Run:   servicesTagRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesTagCmd)
+
+	flags := servicesTagCmd.Flags()
+
+	flags.StringVarP(&servicesTagOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringSliceVarP(&servicesTagOpts.tags, "tag", "t", []string{}, requiredSpec+resourceTagsSpec)
+	flags.BoolVar(&servicesTagOpts.stdin, "stdin", false, stdinSpec)
+	flags.BoolVarP(&servicesTagOpts.yes, "yes", "y", false, yesSpec)
+	flags.IntVar(&servicesTagOpts.concurrency, "concurrency", 5, concurrencySpec)
+
+	servicesTagCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesUnfreezeOptions struct {
+	cluster string
+}
+
+var servicesUnfreezeOpts servicesUnfreezeOptions
+
+func servicesUnfreezeRun(cmd *cobra.Command, services []string) {
+	opts := &servicesUnfreezeOpts
+
+	described, err := describeServices(opts.cluster, aws.StringSlice(services))
+	must(err)
+
+	for _, s := range described {
+		_, err := ecsI.UntagResource(&ecs.UntagResourceInput{
+			ResourceArn: s.ServiceArn,
+			TagKeys:     aws.StringSlice([]string{freezeTagKey, frozenByTagKey, frozenAtTagKey}),
+		})
+		must(err)
+
+		printAffected(aws.StringValue(s.ServiceArn), aws.StringValue(s.ServiceName)+" unfrozen")
+	}
+}
+
+var servicesUnfreezeCmd = &cobra.Command{
+	Use:   "unfreeze [services...]",
+	Short: "Remove the freeze of services",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesUnfreezeRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesUnfreezeCmd)
+
+	flags := servicesUnfreezeCmd.Flags()
+
+	flags.StringVarP(&servicesUnfreezeOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+
+	servicesUnfreezeCmd.MarkFlagRequired("cluster")
+}
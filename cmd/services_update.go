@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type servicesUpdateOptions struct {
+	cluster              string
+	enableExecuteCommand bool
+	noRedeploy           bool
+	overrideFreeze       bool
+}
+
+var servicesUpdateOpts servicesUpdateOptions
+
+func servicesUpdateRun(cmd *cobra.Command, args []string) {
+	opts := &servicesUpdateOpts
+	service := args[0]
+
+	if !cmd.Flags().Changed("enable-execute-command") {
+		must(errors.New("Nothing to update, inform --enable-execute-command"))
+	}
+
+	must(checkServiceFreeze(opts.cluster, service, opts.overrideFreeze))
+
+	// Only the tasks started after the update have ECS Exec enabled, so they are replaced unless asked not to
+	result, err := ecsI.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:              aws.String(opts.cluster),
+		Service:              aws.String(service),
+		EnableExecuteCommand: aws.Bool(opts.enableExecuteCommand),
+		ForceNewDeployment:   aws.Bool(!opts.noRedeploy),
+	})
+	must(err)
+
+	state := "disabled"
+	if opts.enableExecuteCommand {
+		state = "enabled"
+	}
+
+	message := fmt.Sprintf("%s updated, ECS Exec %s on its new tasks", service, state)
+	if !opts.noRedeploy {
+		message = fmt.Sprintf("%s updated, ECS Exec %s and its tasks being replaced", service, state)
+	}
+	printAffected(aws.StringValue(result.Service.ServiceArn), message)
+}
+
+var servicesUpdateCmd = &cobra.Command{
+	Use:   "update [service]",
+	Short: "Update settings of a service, such as ECS Exec",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesUpdateRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesUpdateCmd)
+
+	flags := servicesUpdateCmd.Flags()
+
+	flags.StringVarP(&servicesUpdateOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&servicesUpdateOpts.enableExecuteCommand, "enable-execute-command", false, enableExecuteCommandSpec)
+	flags.BoolVar(&servicesUpdateOpts.noRedeploy, "no-redeploy", false, noRedeploySpec)
+	flags.BoolVar(&servicesUpdateOpts.overrideFreeze, "override-freeze", false, overrideFreezeSpec)
+
+	servicesUpdateCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type verifyOptions struct {
+	urlPath            string
+	expectStatus       int
+	expectBodyContains string
+	exec               bool
+	command            []string
+	container          string
+	timeout            time.Duration
+}
+
+type servicesVerifyOptions struct {
+	cluster string
+	verifyOptions
+}
+
+var servicesVerifyOpts servicesVerifyOptions
+
+// maxVerifyOutput bounds how much of a response or command output is kept to be shown on failure
+const maxVerifyOutput = 4 << 10
+
+// verifyURLs resolves where the service is checked: the load balancer listeners, or the tasks for internal services
+func verifyURLs(cluster string, s *ecs.Service, urlPath string) (urls []string, err error) {
+	seen := make(map[string]bool)
+	add := func(base string) {
+		if !seen[base] {
+			seen[base] = true
+			urls = append(urls, base+urlPath)
+		}
+	}
+
+	if len(s.LoadBalancers) == 0 {
+		endpoints, err := taskEndpoints(cluster, s)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, endpoint := range endpoints {
+			add("http://" + strings.SplitN(endpoint, "\t", 2)[0])
+		}
+		return urls, nil
+	}
+
+	for _, lb := range s.LoadBalancers {
+		if lb.TargetGroupArn == nil {
+			continue
+		}
+
+		found, _, err := loadBalancerEndpoints(aws.StringValue(lb.TargetGroupArn))
+		if err != nil {
+			return nil, err
+		}
+
+		// The path patterns of the rules are not requests, only the scheme and host are kept
+		for _, endpoint := range found {
+			u, err := url.Parse(endpoint)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				continue
+			}
+			add(u.Scheme + "://" + u.Host)
+		}
+	}
+	return
+}
+
+// checkHTTP requests the URL, failing with the captured response when it is not the expected one
+func checkHTTP(client *http.Client, target string, expectStatus int, expectBodyContains string) error {
+	response, err := client.Get(target)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxVerifyOutput))
+
+	if response.StatusCode != expectStatus {
+		return fmt.Errorf("%s returned %d, expected %d:\n%s", target, response.StatusCode, expectStatus, body)
+	}
+
+	if expectBodyContains != "" && !strings.Contains(string(body), expectBodyContains) {
+		return fmt.Errorf("%s response does not contain %q:\n%s", target, expectBodyContains, body)
+	}
+	return nil
+}
+
+// verifyOnce checks every URL of the service, or runs the command in one of its running tasks
+func verifyOnce(cluster string, s *ecs.Service, opts verifyOptions, client *http.Client) error {
+	if len(opts.command) > 0 {
+		tasks, err := serviceTasks(cluster, aws.StringValue(s.ServiceName), ecs.DesiredStatusRunning)
+		if err != nil {
+			return err
+		}
+
+		if len(tasks) == 0 {
+			return errors.New("no running task to run the command in")
+		}
+
+		output, err := runECSExec(cluster, aws.StringValue(tasks[0].TaskArn), opts.container, opts.command)
+		if err != nil {
+			return fmt.Errorf("%s in task %s:\n%s", err.Error(), taskID(aws.StringValue(tasks[0].TaskArn)), output)
+		}
+
+		debugf("%s", output)
+		return nil
+	}
+
+	urls, err := verifyURLs(cluster, s, opts.urlPath)
+	if err != nil {
+		return err
+	}
+
+	if len(urls) == 0 {
+		return errors.New("no endpoint found to be checked")
+	}
+
+	for _, target := range urls {
+		if err := checkHTTP(client, target, opts.expectStatus, opts.expectBodyContains); err != nil {
+			return err
+		}
+		debugf("%s returned %d", target, opts.expectStatus)
+	}
+	return nil
+}
+
+// verifyService retries the check until it passes or the timeout is reached, returning the last failure
+func verifyService(cluster string, s *ecs.Service, opts verifyOptions) (err error) {
+	ctx := interruptContext()
+	client := &http.Client{Timeout: 10 * time.Second}
+	deadline := time.Now().Add(opts.timeout)
+
+	for attempt := 1; ; attempt++ {
+		err = verifyOnce(cluster, s, opts, client)
+		if err == nil {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Verification of %s failed after %d attempts: %s", aws.StringValue(s.ServiceName), attempt, err.Error())
+		}
+
+		fmt.Fprintf(os.Stderr, "attempt %d failed, retrying: %s\n", attempt, strings.SplitN(err.Error(), "\n", 2)[0])
+		if !sleepContext(ctx, 5*time.Second) {
+			return fmt.Errorf("Verification of %s interrupted: %s", aws.StringValue(s.ServiceName), err.Error())
+		}
+	}
+}
+
+func servicesVerifyRun(cmd *cobra.Command, args []string) {
+	opts := &servicesVerifyOpts
+
+	service := args[0]
+	if dash := cmd.ArgsLenAtDash(); dash == 1 {
+		opts.command = args[dash:]
+	}
+
+	if len(args) > 1 && len(opts.command) == 0 || opts.exec != (len(opts.command) > 0) {
+		must(errors.New("Inform the command to be run with --exec after --, e.g. verify api --exec -- /app/bin/smoke"))
+	}
+
+	s, err := describeService(opts.cluster, service)
+	must(err)
+
+	must(verifyService(opts.cluster, s, opts.verifyOptions))
+	printAffected(aws.StringValue(s.ServiceArn), service+" verified")
+}
+
+var servicesVerifyCmd = &cobra.Command{
+	Use:   "verify [service] [-- command...]",
+	Short: "Check a service answers as expected, over HTTP or running a command in a task with ECS Exec",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesVerifyRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesVerifyCmd)
+
+	flags := servicesVerifyCmd.Flags()
+
+	flags.StringVarP(&servicesVerifyOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesVerifyOpts.urlPath, "url-path", "/", urlPathSpec)
+	flags.IntVar(&servicesVerifyOpts.expectStatus, "expect-status", http.StatusOK, expectStatusSpec)
+	flags.StringVar(&servicesVerifyOpts.expectBodyContains, "expect-body-contains", "", expectBodyContainsSpec)
+	flags.BoolVar(&servicesVerifyOpts.exec, "exec", false, verifyExecSpec)
+	flags.StringVar(&servicesVerifyOpts.container, "container", "", execContainerSpec)
+	flags.DurationVar(&servicesVerifyOpts.timeout, "timeout", 2*time.Minute, timeoutSpec)
+
+	servicesVerifyCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/gumieri/ecsctl/ecsx"
+	"github.com/spf13/cobra"
+)
+
+type servicesWaitOptions struct {
+	cluster  string
+	timeout  time.Duration
+	failFast bool
+	healthy  bool
+}
+
+var servicesWaitOpts servicesWaitOptions
+
+type serviceWaitStatus struct {
+	service      string
+	deploymentID string
+	revision     string
+	running      int64
+	desired      int64
+	rollout      string
+	done         bool
+	failed       bool
+	reason       string
+	waitHealthy  bool
+	healthy      int64
+	hasHealth    bool
+	since        time.Time
+	seenEvents   map[string]bool
+}
+
+// printEvents prints the service events created since the wait started and not printed yet, oldest first
+func (s *serviceWaitStatus) printEvents(service *ecs.Service) {
+	if quiet {
+		return
+	}
+
+	red := color.New(color.FgRed).SprintFunc()
+	for i := len(service.Events) - 1; i >= 0; i-- {
+		event := service.Events[i]
+		if s.seenEvents[aws.StringValue(event.Id)] || aws.TimeValue(event.CreatedAt).Before(s.since) {
+			continue
+		}
+
+		s.seenEvents[aws.StringValue(event.Id)] = true
+		typist.Printf("[%s] %s\n", red(aws.TimeValue(event.CreatedAt).Format(time.RFC3339)), aws.StringValue(event.Message))
+	}
+}
+
+func (s *serviceWaitStatus) row() string {
+	row := fmt.Sprintf("%s\t%s\t%d/%d\t%s", s.service, s.revision, s.running, s.desired, s.rollout)
+	if s.waitHealthy {
+		row += fmt.Sprintf("\t%d/%d", s.healthy, s.desired)
+	}
+	return row
+}
+
+// hasHealthCheck tells if any container of the Task Definition declares a health check,
+// without one the tasks never become HEALTHY
+func hasHealthCheck(td *ecs.TaskDefinition) bool {
+	for _, cd := range td.ContainerDefinitions {
+		if cd.HealthCheck != nil && len(cd.HealthCheck.Command) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updateHealth keeps the completed deployment waiting until as many of its tasks as desired are HEALTHY
+func (s *serviceWaitStatus) updateHealth(cluster string, service *ecs.Service) (err error) {
+	if !s.waitHealthy || !s.done || s.failed {
+		return
+	}
+
+	if !s.hasHealth {
+		td, err := describeTaskDefinition(aws.StringValue(service.TaskDefinition))
+		if err != nil {
+			return err
+		}
+
+		if !hasHealthCheck(td) {
+			s.failed = true
+			s.reason = s.revision + " declares no container health check, its tasks never become HEALTHY"
+			return nil
+		}
+		s.hasHealth = true
+	}
+
+	tasks, err := serviceTasks(cluster, aws.StringValue(service.ServiceName), ecs.DesiredStatusRunning)
+	if err != nil {
+		return
+	}
+
+	s.healthy = 0
+	for _, t := range tasks {
+		if aws.StringValue(t.StartedBy) == s.deploymentID && aws.StringValue(t.HealthStatus) == ecs.HealthStatusHealthy {
+			s.healthy++
+		}
+	}
+
+	if s.healthy < s.desired {
+		s.done = false
+		s.rollout = "WAITING HEALTHY"
+	}
+	return
+}
+
+// update checks the deployment being waited, which is the primary one when the wait started
+func (s *serviceWaitStatus) update(service *ecs.Service) {
+	var deployment *ecs.Deployment
+	var primary *ecs.Deployment
+	for _, d := range service.Deployments {
+		if aws.StringValue(d.Status) == "PRIMARY" {
+			primary = d
+		}
+
+		if aws.StringValue(d.Id) == s.deploymentID {
+			deployment = d
+		}
+	}
+
+	if s.deploymentID == "" && primary != nil {
+		s.deploymentID = aws.StringValue(primary.Id)
+		deployment = primary
+	}
+
+	if deployment == nil {
+		s.done = true
+		s.failed = true
+		s.rollout = "REPLACED"
+		if primary != nil {
+			s.reason = "deployment replaced by " + aws.StringValue(primary.Id) + " (" + aws.StringValue(primary.TaskDefinition) + ")"
+		}
+		return
+	}
+
+	family, revision := splitTaskDefinitionArn(aws.StringValue(deployment.TaskDefinition))
+	s.revision = fmt.Sprintf("%s:%d", family, revision)
+	s.running = aws.Int64Value(deployment.RunningCount)
+	s.desired = aws.Int64Value(deployment.DesiredCount)
+	s.rollout = aws.StringValue(deployment.RolloutState)
+
+	switch {
+	case s.rollout == ecs.DeploymentRolloutStateFailed:
+		s.done = true
+		s.failed = true
+		s.reason = aws.StringValue(deployment.RolloutStateReason)
+	case s.rollout == ecs.DeploymentRolloutStateCompleted:
+		s.done = true
+	case s.rollout == "" && deployment == primary && len(service.Deployments) == 1 && s.running == s.desired:
+		// services without the deployment circuit breaker do not report the rollout state
+		s.rollout = ecs.DeploymentRolloutStateCompleted
+		s.done = true
+	case s.rollout == "":
+		s.rollout = ecs.DeploymentRolloutStateInProgress
+	}
+
+	// Scaling does not start a deployment, so a completed one is only done once it runs the desired count again
+	if s.done && !s.failed && (s.running != s.desired || aws.Int64Value(deployment.PendingCount) > 0) {
+		s.done = false
+		s.rollout = "SCALING"
+	}
+
+	if s.failed && len(service.Events) > 0 {
+		s.reason = s.reason + "\n\t\tlast event: " + aws.StringValue(service.Events[0].Message)
+	}
+}
+
+func printServicesWaitTable(statuses []*serviceWaitStatus, healthy bool) {
+	if quiet {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if healthy {
+		fmt.Fprintln(w, "SERVICE\tREVISION\tRUNNING/DESIRED\tROLLOUT\tHEALTHY/DESIRED")
+	} else {
+		fmt.Fprintln(w, "SERVICE\tREVISION\tRUNNING/DESIRED\tROLLOUT")
+	}
+	for _, s := range statuses {
+		fmt.Fprintln(w, s.row())
+	}
+	w.Flush()
+}
+
+// waitServices polls all the services together, at most 10 per DescribeServices request,
+// until every deployment is completed, running its desired count, or failed. The failed services are returned.
+// With healthy, a completed deployment is only done when as many of its tasks as desired are HEALTHY.
+func waitServices(cluster string, services []string, timeout time.Duration, failFast, healthy bool) (failed []*serviceWaitStatus, err error) {
+	condition := "the deployments to complete"
+	if healthy {
+		condition = "the deployments to complete and their tasks to be HEALTHY"
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "waiting for %s\n", condition)
+	}
+
+	statuses := make(map[string]*serviceWaitStatus)
+	var ordered []*serviceWaitStatus
+	for _, service := range services {
+		s := &serviceWaitStatus{service: service, waitHealthy: healthy, since: time.Now(), seenEvents: make(map[string]bool)}
+		statuses[service] = s
+		ordered = append(ordered, s)
+	}
+
+	// services are informed by name or ARN, so both are matched against the response
+	lookup := func(arn, name *string) *serviceWaitStatus {
+		if s, ok := statuses[aws.StringValue(arn)]; ok {
+			return s
+		}
+
+		n := aws.StringValue(name)
+		if n == "" {
+			n = aws.StringValue(arn)[strings.LastIndex(aws.StringValue(arn), "/")+1:]
+		}
+
+		return statuses[n]
+	}
+
+	ctx := interruptContext()
+	deadline := time.Now().Add(timeout)
+	lastTable := ""
+	for {
+		var pending []*string
+		for _, s := range ordered {
+			if !s.done {
+				pending = append(pending, aws.String(s.service))
+			}
+		}
+
+		described, err := ecsxI.DescribeAllServices(cluster, pending)
+
+		// The services that could not be described fail alone, the others keep being waited
+		var failures *ecsx.FailuresError
+		if errors.As(err, &failures) {
+			for _, f := range failures.Failures {
+				if s := lookup(f.Arn, nil); s != nil {
+					s.done = true
+					s.failed = true
+					s.rollout = "MISSING"
+					s.reason = aws.StringValue(f.Reason)
+				}
+			}
+		} else if err != nil {
+			return failed, err
+		}
+
+		for _, service := range described {
+			if s := lookup(service.ServiceArn, service.ServiceName); s != nil {
+				s.printEvents(service)
+				s.update(service)
+				if err := s.updateHealth(cluster, service); err != nil {
+					return failed, err
+				}
+			}
+		}
+
+		var rows []string
+		allDone := true
+		anyFailed := false
+		for _, s := range ordered {
+			rows = append(rows, s.row())
+			allDone = allDone && s.done
+			anyFailed = anyFailed || s.failed
+		}
+
+		if table := strings.Join(rows, "\n"); table != lastTable {
+			printServicesWaitTable(ordered, healthy)
+			lastTable = table
+		}
+
+		if allDone || (failFast && anyFailed) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			err = fmt.Errorf("timed out after %s waiting for %s", timeout, condition)
+			break
+		}
+
+		// The state reached so far is printed again, as the last table may be far up among the events
+		if !sleepContext(ctx, 5*time.Second) {
+			printServicesWaitTable(ordered, healthy)
+			err = fmt.Errorf("Interrupted while waiting for %s", condition)
+			break
+		}
+	}
+
+	for _, s := range ordered {
+		if s.failed {
+			failed = append(failed, s)
+		}
+	}
+
+	return
+}
+
+// reportServicesWait prints the failures and exits non-zero when the wait did not succeed,
+// with the exit code of a rollback or placement failure when that is the cause
+func reportServicesWait(failed []*serviceWaitStatus, err error) {
+	for _, s := range failed {
+		fmt.Fprintf(os.Stderr, "%s failed: %s\n", s.service, s.reason)
+	}
+
+	must(err)
+
+	if len(failed) > 0 {
+		fmt.Fprintln(os.Stderr, "One or more deployments failed")
+		exit(deploymentsExitCode(failed))
+	}
+}
+
+func servicesWaitRun(cmd *cobra.Command, services []string) {
+	opts := &servicesWaitOpts
+
+	failed, err := waitServices(opts.cluster, services, opts.timeout, opts.failFast, opts.healthy)
+	reportServicesWait(failed, err)
+}
+
+var servicesWaitCmd = &cobra.Command{
+	Use:   "wait [services...]",
+	Short: "Wait until the deployments of the services are completed",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   servicesWaitRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesWaitCmd)
+
+	flags := servicesWaitCmd.Flags()
+
+	flags.StringVarP(&servicesWaitOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.DurationVar(&servicesWaitOpts.timeout, "timeout", 10*time.Minute, timeoutSpec)
+	flags.BoolVar(&servicesWaitOpts.failFast, "fail-fast", false, failFastSpec)
+	flags.BoolVar(&servicesWaitOpts.healthy, "healthy", false, healthySpec)
+
+	servicesWaitCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+func TestServiceWaitStatusUpdate(t *testing.T) {
+	deployment := func(rollout string, running, desired, pending int64) *ecs.Service {
+		return &ecs.Service{Deployments: []*ecs.Deployment{{
+			Id:             aws.String("ecs-svc/1"),
+			Status:         aws.String("PRIMARY"),
+			TaskDefinition: aws.String("arn:aws:ecs:eu-west-1:123456789012:task-definition/web:3"),
+			RolloutState:   aws.String(rollout),
+			RunningCount:   aws.Int64(running),
+			DesiredCount:   aws.Int64(desired),
+			PendingCount:   aws.Int64(pending),
+		}}}
+	}
+
+	tests := []struct {
+		name    string
+		service *ecs.Service
+		done    bool
+		rollout string
+	}{
+		{"completed", deployment(ecs.DeploymentRolloutStateCompleted, 2, 2, 0), true, ecs.DeploymentRolloutStateCompleted},
+		{"scaled up", deployment(ecs.DeploymentRolloutStateCompleted, 2, 4, 0), false, "SCALING"},
+		{"scaled up pending", deployment(ecs.DeploymentRolloutStateCompleted, 4, 4, 1), false, "SCALING"},
+		{"scaled down", deployment(ecs.DeploymentRolloutStateCompleted, 2, 0, 0), false, "SCALING"},
+		{"in progress", deployment(ecs.DeploymentRolloutStateInProgress, 1, 2, 1), false, ecs.DeploymentRolloutStateInProgress},
+		{"failed", deployment(ecs.DeploymentRolloutStateFailed, 0, 2, 0), true, ecs.DeploymentRolloutStateFailed},
+	}
+
+	for _, test := range tests {
+		s := &serviceWaitStatus{service: "web"}
+		s.update(test.service)
+
+		if s.done != test.done || s.rollout != test.rollout {
+			t.Errorf("%s: got done %t rollout %s, want %t %s", test.name, s.done, s.rollout, test.done, test.rollout)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type errorWatchOptions struct {
+	pattern        string
+	window         time.Duration
+	baselineWindow time.Duration
+	threshold      float64
+}
+
+type servicesWatchErrorsOptions struct {
+	cluster string
+	since   string
+	errorWatchOptions
+}
+
+var servicesWatchErrorsOpts servicesWatchErrorsOptions
+
+// errorWatch is the count of matching events before and after the deploy marker
+type errorWatch struct {
+	marker   time.Time
+	baseline int64
+	watched  int64
+	ratio    float64
+}
+
+// deploymentMarker is when the primary deployment of the service was created
+func deploymentMarker(s *ecs.Service) (marker time.Time, err error) {
+	for _, d := range s.Deployments {
+		if aws.StringValue(d.Status) == "PRIMARY" {
+			return aws.TimeValue(d.CreatedAt), nil
+		}
+	}
+
+	err = errors.New(aws.StringValue(s.ServiceName) + " has no primary deployment, inform --since")
+	return
+}
+
+// countLogEvents counts the events of the streams matching the pattern within [start, end)
+func countLogEvents(streams []logStream, start, end time.Time, filterPattern string) (count int64, err error) {
+	byGroup := make(map[string][]*string)
+	for _, stream := range streams {
+		byGroup[stream.group] = append(byGroup[stream.group], aws.String(stream.name))
+	}
+
+	for group, names := range byGroup {
+		// FilterLogEvents accepts up to 100 stream names per request
+		for from := 0; from < len(names); from += 100 {
+			to := from + 100
+			if to > len(names) {
+				to = len(names)
+			}
+
+			input := &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName:   aws.String(group),
+				LogStreamNames: names[from:to],
+				StartTime:      aws.Int64(aws.TimeUnixMilli(start)),
+				EndTime:        aws.Int64(aws.TimeUnixMilli(end) - 1),
+				FilterPattern:  aws.String(filterPattern),
+			}
+
+			err = cwlI.FilterLogEventsPages(input, func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+				count += int64(len(page.Events))
+				return !lastPage
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// errorRatio compares the rates per minute of both windows. A baseline without events counts as one,
+// otherwise any event after a quiet baseline would be an infinite ratio.
+func errorRatio(baseline, watched int64, baselineWindow, window time.Duration) float64 {
+	if watched == 0 {
+		return 0
+	}
+
+	if baseline == 0 {
+		baseline = 1
+	}
+
+	return (float64(watched) / window.Minutes()) / (float64(baseline) / baselineWindow.Minutes())
+}
+
+// watchErrors waits until the watch window after the marker is over and compares its matching events with
+// the ones of the baseline window before the marker. Deploy markers in the future are not accepted.
+func watchErrors(cluster, service string, marker time.Time, opts errorWatchOptions) (w errorWatch, err error) {
+	if opts.window <= 0 || opts.baselineWindow <= 0 {
+		err = errors.New("--window and --baseline-window must be greater than zero")
+		return
+	}
+
+	s, err := describeService(cluster, service)
+	if err != nil {
+		return
+	}
+
+	if marker.IsZero() {
+		if marker, err = deploymentMarker(s); err != nil {
+			return
+		}
+	}
+
+	if marker.After(time.Now()) {
+		err = errors.New("The deploy marker " + marker.Format(time.RFC3339) + " is in the future")
+		return
+	}
+
+	w.marker = marker
+	since := marker.Add(-opts.baselineWindow)
+	until := marker.Add(opts.window)
+
+	if wait := time.Until(until); wait > 0 {
+		typist.Printf("watching %s for %q until %s\n", service, opts.pattern, until.Format(time.RFC3339))
+		if !sleepContext(interruptContext(), wait) {
+			err = errors.New("Interrupted before the end of the watch window")
+			return
+		}
+	}
+
+	// Described again, the tasks and deployments started during the window are known now
+	if s, err = describeService(cluster, service); err != nil {
+		return
+	}
+
+	prefixes, err := serviceStreamPrefixes(s, containerFilter{})
+	if err != nil {
+		return
+	}
+
+	if len(prefixes) == 0 {
+		err = errors.New(service + " has no container using the awslogs log driver")
+		return
+	}
+
+	streams, err := discoverStreams(prefixes, since, until)
+	if err != nil {
+		return
+	}
+
+	if w.baseline, err = countLogEvents(streams, since, marker, opts.pattern); err != nil {
+		return
+	}
+
+	if w.watched, err = countLogEvents(streams, marker, until, opts.pattern); err != nil {
+		return
+	}
+
+	w.ratio = errorRatio(w.baseline, w.watched, opts.baselineWindow, opts.window)
+	return
+}
+
+// reportErrorWatch prints both windows and fails when the ratio is above the threshold
+func reportErrorWatch(service string, w errorWatch, opts errorWatchOptions) {
+	typist.Printf("baseline  %-8s %6d events  %.2f/min\n", opts.baselineWindow, w.baseline, float64(w.baseline)/opts.baselineWindow.Minutes())
+	typist.Printf("watch     %-8s %6d events  %.2f/min\n", opts.window, w.watched, float64(w.watched)/opts.window.Minutes())
+	typist.Printf("ratio     %.2f (threshold %.2f)\n", w.ratio, opts.threshold)
+
+	if w.ratio > opts.threshold {
+		must(fmt.Errorf("The error rate of %s since %s is %.2f times the baseline, above the threshold of %.2f", service, w.marker.Format(time.RFC3339), w.ratio, opts.threshold))
+	}
+}
+
+func servicesWatchErrorsRun(cmd *cobra.Command, args []string) {
+	opts := &servicesWatchErrorsOpts
+	service := args[0]
+
+	var marker time.Time
+	if opts.since != "" {
+		var err error
+		marker, err = parseSince(opts.since)
+		must(err)
+	}
+
+	w, err := watchErrors(opts.cluster, service, marker, opts.errorWatchOptions)
+	must(err)
+
+	reportErrorWatch(service, w, opts.errorWatchOptions)
+}
+
+var servicesWatchErrorsCmd = &cobra.Command{
+	Use:   "watch-errors [service]",
+	Short: "Compare the rate of error logs of a service after its last deploy with the one before",
+	Args:  cobra.ExactArgs(1),
+	Run:   servicesWatchErrorsRun,
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesWatchErrorsCmd)
+
+	flags := servicesWatchErrorsCmd.Flags()
+
+	flags.StringVarP(&servicesWatchErrorsOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&servicesWatchErrorsOpts.since, "since", "", deployMarkerSpec)
+	flags.StringVar(&servicesWatchErrorsOpts.pattern, "pattern", `"ERROR"`, errorPatternSpec)
+	flags.DurationVar(&servicesWatchErrorsOpts.window, "window", 5*time.Minute, errorWindowSpec)
+	flags.DurationVar(&servicesWatchErrorsOpts.baselineWindow, "baseline-window", 30*time.Minute, baselineWindowSpec)
+	flags.Float64Var(&servicesWatchErrorsOpts.threshold, "threshold", 2.0, errorThresholdSpec)
+
+	servicesWatchErrorsCmd.MarkFlagRequired("cluster")
+}
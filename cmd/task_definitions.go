@@ -1,9 +1,156 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
 )
 
+// taskDefinitionsCache is shared by the goroutines of the commands following many services, such as logs tail
+var taskDefinitionsCache = struct {
+	sync.Mutex
+	byArn map[string]*ecs.TaskDefinition
+}{byArn: make(map[string]*ecs.TaskDefinition)}
+
+// describeTaskDefinition describes a Task Definition by its ARN only once per execution
+func describeTaskDefinition(arn string) (td *ecs.TaskDefinition, err error) {
+	taskDefinitionsCache.Lock()
+	td, ok := taskDefinitionsCache.byArn[arn]
+	taskDefinitionsCache.Unlock()
+	if ok {
+		return td, nil
+	}
+
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(arn),
+	})
+	if err != nil {
+		return
+	}
+
+	td = tdDescription.TaskDefinition
+	taskDefinitionsCache.Lock()
+	taskDefinitionsCache.byArn[arn] = td
+	taskDefinitionsCache.Unlock()
+	return
+}
+
+// splitTaskDefinitionArn extracts the family and the revision from a Task Definition ARN
+// E.g. arn:aws:ecs:us-east-1:123456789012:task-definition/sample:7
+func splitTaskDefinitionArn(arn string) (family string, revision int64) {
+	resource := arn[strings.LastIndex(arn, "/")+1:]
+
+	i := strings.LastIndex(resource, ":")
+	if i < 0 {
+		family = resource
+		return
+	}
+
+	family = resource[:i]
+	revision, _ = strconv.ParseInt(resource[i+1:], 10, 64)
+	return
+}
+
+// taskDefinitionReference accepts a bare family (the latest ACTIVE revision),
+// family:revision or a full Task Definition ARN, and returns the form expected by the ECS API.
+// The revision flag is only accepted together with a bare family.
+func taskDefinitionReference(arg, revision string) (reference string, err error) {
+	if strings.HasPrefix(arg, "arn:") {
+		if !strings.Contains(arg, ":task-definition/") {
+			err = fmt.Errorf("%s is not a Task Definition ARN", arg)
+			return
+		}
+	}
+
+	resource := arg[strings.LastIndex(arg, "/")+1:]
+	if i := strings.LastIndex(resource, ":"); i >= 0 {
+		if _, err = strconv.ParseInt(resource[i+1:], 10, 64); err != nil {
+			err = fmt.Errorf("Invalid revision on %s", arg)
+			return
+		}
+
+		if revision != "" {
+			err = fmt.Errorf("%s already informs the revision, --revision can not be used with it", arg)
+			return
+		}
+
+		reference = arg
+		return
+	}
+
+	if strings.HasPrefix(arg, "arn:") {
+		err = fmt.Errorf("Task Definition ARN %s has no revision", arg)
+		return
+	}
+
+	if revision == "" {
+		reference = arg
+		return
+	}
+
+	if _, err = strconv.ParseInt(revision, 10, 64); err != nil {
+		err = fmt.Errorf("Invalid revision %s", revision)
+		return
+	}
+
+	reference = arg + ":" + revision
+	return
+}
+
+// containerDefinition finds the container by name, or the first one when no name is informed
+func containerDefinition(td *ecs.TaskDefinition, name string) (*ecs.ContainerDefinition, error) {
+	if name == "" {
+		return td.ContainerDefinitions[0], nil
+	}
+
+	var names []string
+	for _, cd := range td.ContainerDefinitions {
+		if aws.StringValue(cd.Name) == name {
+			return cd, nil
+		}
+		names = append(names, aws.StringValue(cd.Name))
+	}
+
+	return nil, fmt.Errorf("No container %s on %s, the containers are: %s", name, familyRevision(td.Family, td.Revision), strings.Join(names, ", "))
+}
+
+func familyRevision(family *string, revision *int64) string {
+	return aws.StringValue(family) + ":" + strconv.FormatInt(aws.Int64Value(revision), 10)
+}
+
+// formatTags joins the tags as 'key=value' sorted by key
+func formatTags(tags []*ecs.Tag) string {
+	var pairs []string
+	for _, tag := range tags {
+		pairs = append(pairs, aws.StringValue(tag.Key)+"="+aws.StringValue(tag.Value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// tagRevision applies the revision tags to a newly registered Task Definition.
+// The revision already exists at this point, so a failure is only reported.
+func tagRevision(arn string, tags []*ecs.Tag) {
+	if len(tags) == 0 {
+		return
+	}
+
+	_, err := ecsI.TagResource(&ecs.TagResourceInput{
+		ResourceArn: aws.String(arn),
+		Tags:        tags,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s was registered but could not be tagged: %s\n", arn, err.Error())
+	}
+}
+
 func taskDefinitionsRun(cmd *cobra.Command, args []string) {
 	cmd.Help()
 }
@@ -11,7 +158,7 @@ func taskDefinitionsRun(cmd *cobra.Command, args []string) {
 var taskDefinitionsCmd = &cobra.Command{
 	Use:     "task-definitions [command]",
 	Short:   "Commands to manage Task Definitions",
-	Aliases: []string{"task-definition", "tasks", "task", "t"},
+	Aliases: []string{"task-definition", "td", "t"},
 	Run:     taskDefinitionsRun,
 }
 
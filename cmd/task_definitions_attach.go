@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsAttachOptions struct {
+	cluster       string
+	since         string
+	exit          bool
+	heartbeat     time.Duration
+	stallTimeout  time.Duration
+	stopOnStall   bool
+	pollInterval  time.Duration
+	filterPattern string
+	maxLogRate    int
+	requireLogs   bool
+	containers    containerFilter
+	logLocation   logLocation
+	output        outputConfiguration
+}
+
+var taskDefinitionsAttachOpts taskDefinitionsAttachOptions
+
+func taskDefinitionsAttachRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsAttachOpts
+
+	if opts.stopOnStall && opts.stallTimeout == 0 {
+		must(errors.New("--stop-on-stall requires --stall-timeout"))
+	}
+
+	if opts.pollInterval <= 0 {
+		must(errors.New("--poll-interval must be greater than zero"))
+	}
+
+	var since time.Time
+	if opts.since != "" {
+		var err error
+		since, err = parseSince(opts.since)
+		must(err)
+	}
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(args[0])})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	td, err := describeTaskDefinition(aws.StringValue(tasks[0].TaskDefinitionArn))
+	must(err)
+
+	must(opts.containers.validate([]*ecs.TaskDefinition{td}))
+
+	// The exit code is the one of the single container followed, or of the first one
+	container := ""
+	if len(opts.containers.include) == 1 {
+		container = opts.containers.include[0]
+	}
+
+	followTask(opts.cluster, tasks[0], td, followOptions{
+		exit:          opts.exit,
+		heartbeat:     opts.heartbeat,
+		stallTimeout:  opts.stallTimeout,
+		stopOnStall:   opts.stopOnStall,
+		pollInterval:  opts.pollInterval,
+		filterPattern: opts.filterPattern,
+		maxLogRate:    opts.maxLogRate,
+		requireLogs:   opts.requireLogs,
+		logLocation:   opts.logLocation,
+		output:        opts.output,
+		since:         since,
+		container:     container,
+		containers:    opts.containers,
+	})
+}
+
+var taskDefinitionsAttachCmd = &cobra.Command{
+	Use:   "attach [task]",
+	Short: "Follow the logs and status of a running task, as run --follow does",
+	Args:  cobra.ExactArgs(1),
+	Run:   taskDefinitionsAttachRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsAttachCmd)
+
+	flags := taskDefinitionsAttachCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsAttachOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&taskDefinitionsAttachOpts.since, "since", "", sinceSpec)
+	flags.BoolVar(&taskDefinitionsAttachOpts.exit, "exit", false, exitSpec)
+	flags.DurationVar(&taskDefinitionsAttachOpts.heartbeat, "heartbeat", 0, heartbeatSpec)
+	flags.DurationVar(&taskDefinitionsAttachOpts.stallTimeout, "stall-timeout", 0, stallTimeoutSpec)
+	flags.BoolVar(&taskDefinitionsAttachOpts.stopOnStall, "stop-on-stall", false, stopOnStallSpec)
+	flags.DurationVar(&taskDefinitionsAttachOpts.pollInterval, "poll-interval", time.Second, pollIntervalSpec)
+	flags.StringVar(&taskDefinitionsAttachOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	flags.IntVar(&taskDefinitionsAttachOpts.maxLogRate, "max-log-rate", 0, maxLogRateSpec)
+	flags.BoolVar(&taskDefinitionsAttachOpts.requireLogs, "require-logs", false, requireLogsSpec)
+	flags.StringArrayVar(&taskDefinitionsAttachOpts.containers.include, "container", []string{}, logsContainerSpec)
+	addLogOutputFlags(flags, &taskDefinitionsAttachOpts.output)
+	addLogLocationFlags(flags, &taskDefinitionsAttachOpts.logLocation)
+
+	taskDefinitionsAttachCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsDeregisterOptions struct {
+	stdin       bool
+	yes         bool
+	concurrency int
+}
+
+var taskDefinitionsDeregisterOpts taskDefinitionsDeregisterOptions
+
+func taskDefinitionsDeregisterRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsDeregisterOpts
+
+	revisions, err := batchItems(args, opts.stdin, taskDefinitionRevisionPattern, "task definition revisions")
+	must(err)
+
+	if !opts.yes {
+		typist.Printf("%d task definition revisions to be deregistered\n", len(revisions))
+
+		if !confirmBatch("Do you really want to deregister these revisions?", opts.stdin) {
+			return
+		}
+	}
+
+	failures := runBatch(revisions, opts.concurrency, func(revision string) error {
+		_, err := ecsI.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{
+			TaskDefinition: aws.String(revision),
+		})
+		return err
+	})
+
+	reportBatch(len(revisions), failures)
+}
+
+var taskDefinitionsDeregisterCmd = &cobra.Command{
+	Use:   "deregister [family:revision...]",
+	Short: "Deregister Task Definition revisions",
+	Run:   taskDefinitionsDeregisterRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsDeregisterCmd)
+
+	flags := taskDefinitionsDeregisterCmd.Flags()
+
+	flags.BoolVar(&taskDefinitionsDeregisterOpts.stdin, "stdin", false, stdinSpec)
+	flags.BoolVarP(&taskDefinitionsDeregisterOpts.yes, "yes", "y", false, yesSpec)
+	flags.IntVar(&taskDefinitionsDeregisterOpts.concurrency, "concurrency", 5, concurrencySpec)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+func taskDefinitionsDescribeRun(cmd *cobra.Command, args []string) {
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(args[0]),
+		Include:        []*string{aws.String(ecs.TaskDefinitionFieldTags)},
+	})
+	must(err)
+
+	td := tdDescription.TaskDefinition
+
+	typist.Printf("Task Definition: %s\n", familyRevision(td.Family, td.Revision))
+	typist.Printf("ARN:             %s\n", aws.StringValue(td.TaskDefinitionArn))
+	typist.Printf("Status:          %s\n", aws.StringValue(td.Status))
+	typist.Printf("Registered At:   %s\n", aws.TimeValue(td.RegisteredAt).Format(time.RFC3339))
+	typist.Printf("Registered By:   %s\n", aws.StringValue(td.RegisteredBy))
+
+	if td.DeregisteredAt != nil {
+		typist.Printf("Deregistered At: %s\n", aws.TimeValue(td.DeregisteredAt).Format(time.RFC3339))
+	}
+
+	typist.Printf("Network Mode:    %s\n", aws.StringValue(td.NetworkMode))
+	typist.Printf("CPU / Memory:    %s / %s\n", aws.StringValue(td.Cpu), aws.StringValue(td.Memory))
+
+	if len(tdDescription.Tags) > 0 {
+		typist.Printf("Tags:            %s\n", formatTags(tdDescription.Tags))
+	}
+
+	typist.Println("Containers:")
+	for _, cd := range td.ContainerDefinitions {
+		typist.Printf("  %s\t%s\n", aws.StringValue(cd.Name), aws.StringValue(cd.Image))
+	}
+}
+
+var taskDefinitionsDescribeCmd = &cobra.Command{
+	Use:         "describe [task-definition]",
+	Short:       "Describe a Task Definition, including who registered it and when",
+	Args:        cobra.ExactArgs(1),
+	Run:         taskDefinitionsDescribeRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsDescribeCmd)
+}
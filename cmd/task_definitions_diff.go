@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsDiffOptions struct {
+	cluster string
+	service string
+	context int
+}
+
+var taskDefinitionsDiffOpts taskDefinitionsDiffOptions
+
+// diffLine is a line of the unified diff: ' ' kept, '-' removed, '+' added
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// normalizedTaskDefinition is the revision in the shrunk form, without the fields that differ on every revision
+func normalizedTaskDefinition(td *ecs.TaskDefinition) ([]string, error) {
+	content, err := json.Marshal(td)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err = json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	content, err = json.MarshalIndent(shrinkTaskDefinition(document), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
+
+// diffLines compares the lines by their longest common subsequence, which is cheap for documents of this size
+func diffLines(a, b []string) (lines []diffLine) {
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return
+}
+
+// unifiedDiff formats the changes in hunks with the lines of context around them, as diff -u does
+func unifiedDiff(lines []diffLine, context int) (hunks []string) {
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+
+		// The hunk goes on while the next change is close enough for the contexts to overlap
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+
+		end := start
+		for next := start; next < len(lines) && next-end <= 2*context; next++ {
+			if lines[next].op != ' ' {
+				end = next
+			}
+		}
+
+		last := end + context
+		if last >= len(lines) {
+			last = len(lines) - 1
+		}
+
+		// The line numbers of the hunk on both sides count the lines before it
+		fromLine, toLine := 1, 1
+		for _, l := range lines[:first] {
+			if l.op != '+' {
+				fromLine++
+			}
+			if l.op != '-' {
+				toLine++
+			}
+		}
+
+		var fromCount, toCount int
+		var body []string
+		for _, l := range lines[first : last+1] {
+			switch l.op {
+			case '-':
+				fromCount++
+				body = append(body, red("-"+l.text))
+			case '+':
+				toCount++
+				body = append(body, green("+"+l.text))
+			default:
+				fromCount++
+				toCount++
+				body = append(body, " "+l.text)
+			}
+		}
+
+		header := cyan(fmt.Sprintf("@@ -%d,%d +%d,%d @@", fromLine, fromCount, toLine, toCount))
+		hunks = append(hunks, header+"\n"+strings.Join(body, "\n"))
+		start = last + 1
+	}
+	return
+}
+
+// diffRevisions resolves the revisions to compare: those informed, where the second defaults to the latest
+// of the family of the first, or the one the service runs against the latest of its family
+func diffRevisions(opts *taskDefinitionsDiffOptions, args []string) (from, to string, err error) {
+	if opts.service != "" {
+		if len(args) > 0 {
+			err = errors.New("Inform either the revisions or --service, not both")
+			return
+		}
+
+		if opts.cluster == "" {
+			err = errors.New("--cluster is required with --service")
+			return
+		}
+
+		var s *ecs.Service
+		s, err = describeService(opts.cluster, opts.service)
+		if err != nil {
+			return
+		}
+
+		from = aws.StringValue(s.TaskDefinition)
+		to, _ = splitTaskDefinitionArn(from)
+		return
+	}
+
+	if len(args) == 0 {
+		err = errors.New("Inform the revisions to compare, or --service")
+		return
+	}
+
+	if from, err = taskDefinitionReference(args[0], ""); err != nil {
+		return
+	}
+
+	if len(args) > 1 {
+		to, err = taskDefinitionReference(args[1], "")
+		return
+	}
+
+	to, _ = splitTaskDefinitionArn(from)
+	return
+}
+
+func taskDefinitionsDiffRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsDiffOpts
+
+	from, to, err := diffRevisions(opts, args)
+	must(err)
+
+	fromTD, err := describeTaskDefinition(from)
+	must(err)
+
+	toTD, err := describeTaskDefinition(to)
+	must(err)
+
+	fromLines, err := normalizedTaskDefinition(fromTD)
+	must(err)
+
+	toLines, err := normalizedTaskDefinition(toTD)
+	must(err)
+
+	fromName := familyRevision(fromTD.Family, fromTD.Revision)
+	toName := familyRevision(toTD.Family, toTD.Revision)
+
+	hunks := unifiedDiff(diffLines(fromLines, toLines), opts.context)
+	if len(hunks) == 0 {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "%s and %s are identical\n", fromName, toName)
+		}
+		return
+	}
+
+	if !quiet {
+		typist.Printf("--- %s\n+++ %s\n", fromName, toName)
+		for _, hunk := range hunks {
+			typist.Println(hunk)
+		}
+	}
+
+	exit(1)
+}
+
+var taskDefinitionsDiffCmd = &cobra.Command{
+	Use:   "diff [task-definition] [task-definition]",
+	Short: "Show what changed between two Task Definition revisions, exiting 1 when they differ",
+	Long: `Show what changed between two Task Definition revisions, as a unified diff of their documents
+without the fields set by ECS on every revision. The second revision defaults to the latest of the family of the first.
+With --service, the revision the service runs is compared with the latest of its family.
+Exits 0 when the revisions are identical and 1 when they differ.`,
+	Args:        cobra.MaximumNArgs(2),
+	Run:         taskDefinitionsDiffRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsDiffCmd)
+
+	flags := taskDefinitionsDiffCmd.Flags()
+	flags.StringVarP(&taskDefinitionsDiffOpts.cluster, "cluster", "c", "", clusterSpec)
+	flags.StringVarP(&taskDefinitionsDiffOpts.service, "service", "s", "", diffServiceSpec)
+	flags.IntVar(&taskDefinitionsDiffOpts.context, "context", 3, diffContextSpec)
+}
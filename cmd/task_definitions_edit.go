@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"os"
 	"strconv"
 
@@ -13,21 +12,33 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type taskDefinitionsEditOptions struct {
+	editorCommand string
+	revisionTags  []string
+}
+
+var taskDefinitionsEditOpts taskDefinitionsEditOptions
+
 func taskDefinitionsEditRun(cmd *cobra.Command, args []string) {
 	taskDefinition := args[0]
 
+	revisionTags, err := parseResourceTags(taskDefinitionsEditOpts.revisionTags)
+	must(err)
+
+	editorCommand := taskDefinitionsEditOpts.editorCommand
 	if editorCommand == "" {
 		editorCommand = os.Getenv("EDITOR")
 	}
 
 	if editorCommand == "" {
-		typist.Must(errors.New("no editor defined"))
+		must(errors.New("no editor defined"))
 	}
 
 	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
 		TaskDefinition: aws.String(taskDefinition),
+		Include:        []*string{aws.String(ecs.TaskDefinitionFieldTags)},
 	})
-	typist.Must(err)
+	must(err)
 
 	td := tdDescription.TaskDefinition
 
@@ -42,39 +53,42 @@ func taskDefinitionsEditRun(cmd *cobra.Command, args []string) {
 		RequiresCompatibilities: td.RequiresCompatibilities,
 		TaskRoleArn:             td.TaskRoleArn,
 		Volumes:                 td.Volumes,
+		Tags:                    resourceTags(tdDescription.Tags),
 	}
 
 	jsonTdDescription, err := json.MarshalIndent(newTD, "", "  ")
-	typist.Must(err)
+	must(err)
 
 	editor := oie.Editor{Command: editorCommand}
-	typist.Must(editor.OpenTempFile(&oie.File{
+	must(editor.OpenTempFile(&oie.File{
 		FileName: taskDefinition + ".json",
 		Content:  jsonTdDescription,
 	}))
 
 	file, err := editor.LastFile()
-	typist.Must(err)
+	must(err)
 
 	if string(file.Content) == (string(jsonTdDescription) + "\n") {
 		return
 	}
 
 	var editedTD *ecs.RegisterTaskDefinitionInput
-	typist.Must(json.Unmarshal(file.Content, &editedTD))
+	must(json.Unmarshal(file.Content, &editedTD))
 
 	newTDDescription, err := ecsI.RegisterTaskDefinition(editedTD)
-	typist.Must(err)
+	must(err)
+
+	tagRevision(aws.StringValue(newTDDescription.TaskDefinition.TaskDefinitionArn), revisionTags)
 
 	newFamilyRevision := aws.StringValue(newTDDescription.TaskDefinition.Family) + ":" + strconv.FormatInt(aws.Int64Value(newTDDescription.TaskDefinition.Revision), 10)
 
-	fmt.Println(newFamilyRevision)
+	printAffected(aws.StringValue(newTDDescription.TaskDefinition.TaskDefinitionArn), newFamilyRevision)
 
 	oldFamilyRevision := aws.StringValue(td.Family) + ":" + strconv.FormatInt(aws.Int64Value(td.Revision), 10)
 	_, err = ecsI.DeregisterTaskDefinition(&ecs.DeregisterTaskDefinitionInput{
 		TaskDefinition: aws.String(oldFamilyRevision),
 	})
-	typist.Must(err)
+	must(err)
 }
 
 var taskDefinitionsEditCmd = &cobra.Command{
@@ -87,5 +101,6 @@ var taskDefinitionsEditCmd = &cobra.Command{
 func init() {
 	taskDefinitionsCmd.AddCommand(taskDefinitionsEditCmd)
 
-	taskDefinitionsEditCmd.Flags().StringVar(&editorCommand, "editor", "", editorCommandSpec)
+	taskDefinitionsEditCmd.Flags().StringVar(&taskDefinitionsEditOpts.editorCommand, "editor", "", editorCommandSpec)
+	taskDefinitionsEditCmd.Flags().StringArrayVar(&taskDefinitionsEditOpts.revisionTags, "revision-tag", []string{}, revisionTagSpec)
 }
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsImagesOptions struct {
+	cluster         string
+	allClusters     bool
+	activeOnly      bool
+	standaloneTasks bool
+}
+
+var taskDefinitionsImagesOpts taskDefinitionsImagesOptions
+
+type imageUsage struct {
+	Cluster string `json:"cluster"`
+	Service string `json:"service,omitempty"`
+	Task    string `json:"task,omitempty"`
+}
+
+type imageInventoryItem struct {
+	Image   string       `json:"image"`
+	Digests []string     `json:"digests"`
+	UsedBy  []imageUsage `json:"usedBy"`
+
+	digests map[string]bool
+	usages  map[imageUsage]bool
+}
+
+type imageInventory map[string]*imageInventoryItem
+
+func (inventory imageInventory) add(image, digest string, usage imageUsage) {
+	item, ok := inventory[image]
+	if !ok {
+		item = &imageInventoryItem{
+			Image:   image,
+			Digests: []string{},
+			digests: make(map[string]bool),
+			usages:  make(map[imageUsage]bool),
+		}
+		inventory[image] = item
+	}
+
+	if digest != "" && !item.digests[digest] {
+		item.digests[digest] = true
+		item.Digests = append(item.Digests, digest)
+	}
+
+	if !item.usages[usage] {
+		item.usages[usage] = true
+		item.UsedBy = append(item.UsedBy, usage)
+	}
+}
+
+func (inventory imageInventory) sorted() (items []*imageInventoryItem) {
+	for _, item := range inventory {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Image < items[j].Image })
+	return
+}
+
+func (u imageUsage) String() string {
+	if u.Service != "" {
+		return u.Cluster + "/" + u.Service
+	}
+	return u.Cluster + "/task/" + u.Task
+}
+
+func inventoryCluster(inventory imageInventory, cluster string, opts *taskDefinitionsImagesOptions) (err error) {
+	clusterName := cluster[strings.LastIndex(cluster, "/")+1:]
+
+	serviceArns, err := listServices(cluster)
+	if err != nil {
+		return
+	}
+
+	services, err := describeServices(cluster, serviceArns)
+	if err != nil {
+		return
+	}
+
+	for _, s := range services {
+		if opts.activeOnly && aws.Int64Value(s.DesiredCount) == 0 {
+			continue
+		}
+
+		// during a deployment the service runs more than one Task Definition
+		for _, d := range s.Deployments {
+			td, err := describeTaskDefinition(aws.StringValue(d.TaskDefinition))
+			if err != nil {
+				return err
+			}
+
+			for _, cd := range td.ContainerDefinitions {
+				inventory.add(aws.StringValue(cd.Image), "", imageUsage{Cluster: clusterName, Service: aws.StringValue(s.ServiceName)})
+			}
+		}
+	}
+
+	taskArns, err := ecsxI.ListAllTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	if err != nil {
+		return
+	}
+
+	tasks, err := describeTasks(cluster, taskArns)
+	if err != nil {
+		return
+	}
+
+	// the running containers are the only source of the image digests
+	for _, t := range tasks {
+		usage := imageUsage{Cluster: clusterName}
+
+		group := aws.StringValue(t.Group)
+		if strings.HasPrefix(group, "service:") {
+			usage.Service = strings.TrimPrefix(group, "service:")
+		} else if opts.standaloneTasks {
+			usage.Task = taskID(aws.StringValue(t.TaskArn))
+		} else {
+			continue
+		}
+
+		for _, c := range t.Containers {
+			inventory.add(aws.StringValue(c.Image), aws.StringValue(c.ImageDigest), usage)
+		}
+	}
+
+	return
+}
+
+func taskDefinitionsImagesRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsImagesOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json", "csv"))
+
+	if !opts.allClusters && opts.cluster == "" {
+		must(errors.New("Inform a --cluster or use --all-clusters"))
+	}
+
+	clusters := []*string{aws.String(opts.cluster)}
+	if opts.allClusters {
+		var err error
+		clusters, err = listClusters()
+		must(err)
+	}
+
+	inventory := make(imageInventory)
+	for _, cluster := range clusters {
+		must(inventoryCluster(inventory, aws.StringValue(cluster), opts))
+	}
+
+	items := inventory.sorted()
+
+	switch outputFormat {
+	case "json":
+		if items == nil {
+			items = []*imageInventoryItem{}
+		}
+
+		must(printVersionedJSON(items))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"image", "digest", "cluster", "service", "task"})
+		for _, item := range items {
+			digests := strings.Join(item.Digests, " ")
+			for _, u := range item.UsedBy {
+				w.Write([]string{item.Image, digests, u.Cluster, u.Service, u.Task})
+			}
+		}
+		w.Flush()
+		must(w.Error())
+	case "text", "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "IMAGE\tDIGESTS\tUSED BY")
+		for _, item := range items {
+			var usedBy []string
+			for _, u := range item.UsedBy {
+				usedBy = append(usedBy, u.String())
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", item.Image, strings.Join(item.Digests, ","), strings.Join(usedBy, ","))
+		}
+		w.Flush()
+	}
+}
+
+var taskDefinitionsImagesCmd = &cobra.Command{
+	Use:         "images",
+	Short:       "List every image used by services and running tasks",
+	Args:        cobra.NoArgs,
+	Run:         taskDefinitionsImagesRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsImagesCmd)
+
+	registerOutputSchema(taskDefinitionsImagesCmd, "task-definitions images", []*imageInventoryItem{})
+
+	flags := taskDefinitionsImagesCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsImagesOpts.cluster, "cluster", "c", "", clusterSpec)
+	flags.BoolVar(&taskDefinitionsImagesOpts.allClusters, "all-clusters", false, allClustersSpec)
+	flags.BoolVar(&taskDefinitionsImagesOpts.activeOnly, "active-only", false, activeOnlySpec)
+	flags.BoolVar(&taskDefinitionsImagesOpts.standaloneTasks, "tasks", false, standaloneTasksSpec)
+}
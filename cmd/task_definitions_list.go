@@ -1,21 +1,60 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"errors"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/spf13/cobra"
 )
 
+type taskDefinitionsListOptions struct {
+	showTags bool
+	detail   bool
+	status   string
+	prefix   string
+	max      int
+}
+
+var taskDefinitionsListOpts taskDefinitionsListOptions
+
 func taskDefinitionsListRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsListOpts
 	input := &ecs.ListTaskDefinitionFamiliesInput{}
 
+	if len(args) > 0 && opts.prefix != "" {
+		must(errors.New("Inform the prefix filter either as argument or with --prefix"))
+	}
+
+	prefix := opts.prefix
 	if len(args) > 0 {
-		input.FamilyPrefix = aws.String(args[0])
+		prefix = args[0]
 	}
 
+	if prefix != "" {
+		input.FamilyPrefix = aws.String(prefix)
+	}
+
+	status := strings.ToUpper(opts.status)
+	switch status {
+	case ecs.TaskDefinitionFamilyStatusActive, ecs.TaskDefinitionFamilyStatusInactive, ecs.TaskDefinitionFamilyStatusAll:
+	default:
+		must(errors.New("Invalid --status '" + opts.status + "', expected ACTIVE, INACTIVE or ALL"))
+	}
+
+	// Families with no ACTIVE revision have no latest revision to be described
+	describe := opts.showTags || opts.detail
+	if describe && status != ecs.TaskDefinitionFamilyStatusActive {
+		if status == ecs.TaskDefinitionFamilyStatusInactive {
+			must(errors.New("--show-tags and --detail describe the latest ACTIVE revision, they can not be used with --status INACTIVE"))
+		}
+		status = ecs.TaskDefinitionFamilyStatusActive
+	}
+	input.Status = aws.String(status)
+
+	listed := 0
+
 	var nextToken *string
 	for {
 		if nextToken != nil {
@@ -23,14 +62,35 @@ func taskDefinitionsListRun(cmd *cobra.Command, args []string) {
 		}
 
 		result, err := ecsI.ListTaskDefinitionFamilies(input)
-
-		if err != nil {
-			fmt.Println(err.Error())
-			os.Exit(1)
-		}
+		must(err)
 
 		for _, f := range result.Families {
-			fmt.Println(aws.StringValue(f))
+			if opts.max > 0 && listed == opts.max {
+				return
+			}
+			listed++
+
+			if !describe || quiet {
+				printID(aws.StringValue(f))
+				continue
+			}
+
+			// Without a revision the latest ACTIVE one is described
+			tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: f,
+				Include:        []*string{aws.String(ecs.TaskDefinitionFieldTags)},
+			})
+			must(err)
+
+			td := tdDescription.TaskDefinition
+			columns := []string{familyRevision(td.Family, td.Revision)}
+			if opts.detail {
+				columns = append(columns, humanizeTime(td.RegisteredAt), firstSetting(aws.StringValue(td.RegisteredBy), "-"))
+			}
+			if opts.showTags {
+				columns = append(columns, formatTags(tdDescription.Tags))
+			}
+			typist.Println(strings.Join(columns, "\t"))
 		}
 
 		if result.NextToken == nil {
@@ -42,12 +102,23 @@ func taskDefinitionsListRun(cmd *cobra.Command, args []string) {
 }
 
 var taskDefinitionsListCmd = &cobra.Command{
-	Use:   "list [prefix filter]",
-	Short: "List all Task Definition Families",
-	Args:  cobra.MaximumNArgs(1),
-	Run:   taskDefinitionsListRun,
+	Use:         "list [prefix filter]",
+	Short:       "List all Task Definition Families",
+	Args:        cobra.MaximumNArgs(1),
+	Run:         taskDefinitionsListRun,
+	Annotations: pagedOutput,
 }
 
 func init() {
 	taskDefinitionsCmd.AddCommand(taskDefinitionsListCmd)
+
+	flags := taskDefinitionsListCmd.Flags()
+
+	flags.BoolVar(&taskDefinitionsListOpts.showTags, "show-tags", false, showTagsSpec)
+	flags.BoolVar(&taskDefinitionsListOpts.detail, "detail", false, familyDetailSpec)
+	flags.StringVar(&taskDefinitionsListOpts.status, "status", ecs.TaskDefinitionFamilyStatusAll, familyStatusSpec)
+	flags.StringVar(&taskDefinitionsListOpts.prefix, "prefix", "", familyPrefixSpec)
+	flags.IntVar(&taskDefinitionsListOpts.max, "max", 0, maxListedSpec)
+
+	getAlias(taskDefinitionsListCmd, "taskdefinitions [prefix filter]", "taskdefinition", "task-definitions", "td")
 }
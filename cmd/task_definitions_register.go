@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsRegisterOptions struct {
+	file         string
+	family       string
+	images       []string
+	revisionTags []string
+}
+
+var taskDefinitionsRegisterOpts taskDefinitionsRegisterOptions
+
+// parseImageSubstitutions reads the CONTAINER=IMAGE pairs of --image
+func parseImageSubstitutions(values []string) (images map[string]string, err error) {
+	images = make(map[string]string)
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			err = errors.New("Invalid --image '" + value + "', expected 'CONTAINER=IMAGE'")
+			return
+		}
+
+		images[kv[0]] = kv[1]
+	}
+	return
+}
+
+// unwrapTaskDefinition returns the Task Definition of a described document, wrapped in taskDefinition,
+// with the tags described along with it. Other documents are returned as they are.
+func unwrapTaskDefinition(document map[string]interface{}) map[string]interface{} {
+	wrapped, ok := document["taskDefinition"].(map[string]interface{})
+	if !ok {
+		return document
+	}
+
+	if tags, ok := document["tags"]; ok && wrapped["tags"] == nil {
+		wrapped["tags"] = tags
+	}
+	return wrapped
+}
+
+// decodeRegistration decodes a Task Definition document, as registered or as described
+// (wrapped in taskDefinition, with the read-only fields set by ECS), into the input of RegisterTaskDefinition.
+// Unknown fields are refused, so a typo is not silently registered without the setting.
+func decodeRegistration(location string, document map[string]interface{}) (input *ecs.RegisterTaskDefinitionInput, err error) {
+	document = unwrapTaskDefinition(document)
+
+	for _, field := range taskDefinitionReadOnlyFields {
+		delete(document, field)
+	}
+
+	content, err := json.Marshal(document)
+	if err != nil {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	input = &ecs.RegisterTaskDefinitionInput{}
+	err = decoder.Decode(input)
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		err = fmt.Errorf("Unable to parse %s: %s must be %s, not %s", location, typeErr.Field, typeErr.Type, typeErr.Value)
+	case err != nil:
+		err = fmt.Errorf("Unable to parse %s: %s", location, strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return
+}
+
+// substituteImages replaces the image of the named containers, failing on a container not in the document
+func substituteImages(input *ecs.RegisterTaskDefinitionInput, images map[string]string) error {
+	var names []string
+	found := make(map[string]bool)
+	for _, cd := range input.ContainerDefinitions {
+		name := aws.StringValue(cd.Name)
+		names = append(names, name)
+
+		if image, ok := images[name]; ok {
+			cd.Image = aws.String(image)
+			found[name] = true
+		}
+	}
+
+	for name := range images {
+		if !found[name] {
+			return fmt.Errorf("No container %s on the Task Definition, the containers are: %s", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+func taskDefinitionsRegisterRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsRegisterOpts
+
+	images, err := parseImageSubstitutions(opts.images)
+	must(err)
+
+	revisionTags, err := parseResourceTags(opts.revisionTags)
+	must(err)
+
+	var document map[string]interface{}
+	must(decodeInput(opts.file, &document))
+
+	input, err := decodeRegistration(opts.file, document)
+	must(err)
+
+	if opts.family != "" {
+		input.Family = aws.String(opts.family)
+	}
+
+	if aws.StringValue(input.Family) == "" {
+		must(errors.New("The document has no family, inform --family"))
+	}
+
+	if len(input.ContainerDefinitions) == 0 {
+		must(errors.New("The document has no containerDefinitions"))
+	}
+
+	must(substituteImages(input, images))
+
+	input.Tags = resourceTags(input.Tags)
+
+	registered, err := ecsI.RegisterTaskDefinition(input)
+	must(err)
+
+	td := registered.TaskDefinition
+	tagRevision(aws.StringValue(td.TaskDefinitionArn), revisionTags)
+
+	printAffected(aws.StringValue(td.TaskDefinitionArn), familyRevision(td.Family, td.Revision))
+}
+
+var taskDefinitionsRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register a Task Definition from a document, optionally replacing the images of its containers",
+	Args:  cobra.NoArgs,
+	Run:   taskDefinitionsRegisterRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsRegisterCmd)
+
+	flags := taskDefinitionsRegisterCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsRegisterOpts.file, "file", "f", "", requiredSpec+taskDefinitionFileSpec)
+	flags.StringVar(&taskDefinitionsRegisterOpts.family, "family", "", registerFamilySpec)
+	flags.StringArrayVar(&taskDefinitionsRegisterOpts.images, "image", []string{}, registerImageSpec)
+	flags.StringArrayVar(&taskDefinitionsRegisterOpts.revisionTags, "revision-tag", []string{}, revisionTagSpec)
+
+	taskDefinitionsRegisterCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+// keepWarmStartedBy marks the tasks run with --keep-warm, the ones rerun accepts
+const keepWarmStartedBy = "ecsctl-keep-warm"
+
+type taskDefinitionsRerunOptions struct {
+	cluster string
+	task    string
+}
+
+var taskDefinitionsRerunOpts taskDefinitionsRerunOptions
+
+// keepWarmContainer finds the container kept sleeping by --keep-warm and until when it sleeps
+func keepWarmContainer(t *ecs.Task) (container string, until time.Time, err error) {
+	if aws.StringValue(t.StartedBy) != keepWarmStartedBy || t.Overrides == nil {
+		return "", until, fmt.Errorf("Task %s was not run with --keep-warm", taskID(aws.StringValue(t.TaskArn)))
+	}
+
+	for _, o := range t.Overrides.ContainerOverrides {
+		command := aws.StringValueSlice(o.Command)
+		if len(command) != 2 || command[0] != "sleep" {
+			continue
+		}
+
+		seconds, _ := strconv.ParseInt(command[1], 10, 64)
+		started := t.StartedAt
+		if started == nil {
+			started = t.CreatedAt
+		}
+		return aws.StringValue(o.Name), aws.TimeValue(started).Add(time.Duration(seconds) * time.Second), nil
+	}
+
+	return "", until, fmt.Errorf("Task %s has no container kept warm", taskID(aws.StringValue(t.TaskArn)))
+}
+
+// execKeepWarm runs the command in the task kept warm with ECS Exec, leaving the task running,
+// and exits with the exit status of the command
+func execKeepWarm(cluster string, t *ecs.Task, container string, command []string) {
+	task := aws.StringValue(t.TaskArn)
+
+	must(waitExecAgent(cluster, task, container, 5*time.Minute))
+
+	exitCode, err := attachECSExec(cluster, task, container, command, os.Stdin)
+	must(err)
+
+	// The task described before it started has no StartedAt, so it is described again for the deadline
+	if tasks, err := describeTasks(cluster, []*string{aws.String(task)}); err == nil && len(tasks) > 0 {
+		if _, until, err := keepWarmContainer(tasks[0]); err == nil && !quiet {
+			fmt.Fprintf(os.Stderr, "task %s kept warm until %s, run again with: ecsctl task-definitions rerun -c %s --task %s -- %s\n",
+				taskID(task), until.Local().Format(time.Kitchen), cluster, taskID(task), strings.Join(command, " "))
+		}
+	}
+
+	exit(exitCode)
+}
+
+func taskDefinitionsRerunRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsRerunOpts
+
+	if len(args) > 0 && cmd.ArgsLenAtDash() != 0 {
+		must(errors.New("Inform the command to be run after --, e.g. rerun --task TASK -- bin/test"))
+	}
+
+	must(checkECSExecPrerequisites())
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(opts.task)})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	t := tasks[0]
+	container, until, err := keepWarmContainer(t)
+	must(err)
+
+	if aws.StringValue(t.LastStatus) == ecs.DesiredStatusStopped || time.Now().After(until) {
+		must(fmt.Errorf("Task %s is no longer kept warm, run it again with --keep-warm", taskID(opts.task)))
+	}
+
+	command := args
+	if len(command) == 0 {
+		td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+		must(err)
+
+		cd, err := containerDefinition(td, container)
+		must(err)
+
+		command = aws.StringValueSlice(cd.Command)
+		if len(command) == 0 {
+			must(errors.New("Inform the command to be run after --, the container has none on its Task Definition"))
+		}
+	}
+
+	execKeepWarm(opts.cluster, t, container, command)
+}
+
+var taskDefinitionsRerunCmd = &cobra.Command{
+	Use:   "rerun [-- command...]",
+	Short: "Run a command again in a task kept warm by run --keep-warm, without running a new task",
+	Run:   taskDefinitionsRerunRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsRerunCmd)
+
+	flags := taskDefinitionsRerunCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsRerunOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&taskDefinitionsRerunOpts.task, "task", "", requiredSpec+rerunTaskSpec)
+
+	taskDefinitionsRerunCmd.MarkFlagRequired("cluster")
+	taskDefinitionsRerunCmd.MarkFlagRequired("task")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsRevisionsOptions struct {
+	registeredBy string
+	detail       bool
+	max          int
+}
+
+var taskDefinitionsRevisionsOpts taskDefinitionsRevisionsOptions
+
+func taskDefinitionsRevisionsRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsRevisionsOpts
+	family := args[0]
+
+	// Newest first, so --max keeps the latest revisions
+	input := &ecs.ListTaskDefinitionsInput{
+		FamilyPrefix: aws.String(family),
+		Sort:         aws.String(ecs.SortOrderDesc),
+	}
+
+	// Filtering by the registering principal needs every revision described, --max applies to the ones kept
+	limit := opts.max
+	if opts.registeredBy != "" {
+		limit = 0
+	}
+
+	var arns []string
+	var nextToken *string
+	for limit == 0 || len(arns) < limit {
+		if nextToken != nil {
+			input.NextToken = nextToken
+		}
+
+		result, err := ecsI.ListTaskDefinitions(input)
+		must(err)
+
+		for _, arn := range result.TaskDefinitionArns {
+			// FamilyPrefix also matches other families starting with the same name
+			if f, _ := splitTaskDefinitionArn(aws.StringValue(arn)); f == family && (limit == 0 || len(arns) < limit) {
+				arns = append(arns, aws.StringValue(arn))
+			}
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+
+		nextToken = result.NextToken
+	}
+
+	listed := 0
+	for _, arn := range arns {
+		if opts.max > 0 && listed == opts.max {
+			break
+		}
+
+		if opts.registeredBy == "" && !opts.detail {
+			printID(arn)
+			listed++
+			continue
+		}
+
+		// The API has no filter by the registering principal, so it is done client-side
+		tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(arn),
+		})
+		must(err)
+
+		td := tdDescription.TaskDefinition
+		if !strings.Contains(aws.StringValue(td.RegisteredBy), opts.registeredBy) {
+			continue
+		}
+		listed++
+
+		if quiet {
+			printID(arn)
+			continue
+		}
+
+		typist.Printf("%s\t%s\t%s\t%s\n",
+			familyRevision(td.Family, td.Revision),
+			aws.StringValue(td.Status),
+			humanizeTime(td.RegisteredAt),
+			aws.StringValue(td.RegisteredBy),
+		)
+	}
+}
+
+var taskDefinitionsRevisionsCmd = &cobra.Command{
+	Use:         "revisions [family]",
+	Short:       "List the revisions of a Task Definition Family",
+	Args:        cobra.ExactArgs(1),
+	Run:         taskDefinitionsRevisionsRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsRevisionsCmd)
+
+	flags := taskDefinitionsRevisionsCmd.Flags()
+
+	flags.StringVar(&taskDefinitionsRevisionsOpts.registeredBy, "registered-by", "", registeredBySpec)
+	flags.BoolVar(&taskDefinitionsRevisionsOpts.detail, "detail", false, revisionDetailSpec)
+	flags.IntVar(&taskDefinitionsRevisionsOpts.max, "max", 0, maxListedSpec)
+
+	getAlias(taskDefinitionsRevisionsCmd, "revisions [family]", "revision", "rev")
+}
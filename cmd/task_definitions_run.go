@@ -1,14 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/TylerBrock/colorjson"
@@ -17,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -28,12 +28,30 @@ type outputConfiguration struct {
 	HideDate       bool
 	Invert         bool
 	NoColor        bool
+	Grep           logPattern
+	GrepV          logPattern
+	JSONL          bool
+	formatter      *colorjson.Formatter
+	writer         *bufio.Writer
+}
+
+// addLogOutputFlags registers the flags of the commands printing log events.
+// --expand and --no-color are persistent flags, they apply to every output.
+func addLogOutputFlags(flags *pflag.FlagSet, c *outputConfiguration) {
+	flags.BoolVar(&c.Raw, "raw", false, rawSpec)
+	flags.BoolVar(&c.RawString, "raw-string", false, rawStringSpec)
+	flags.BoolVar(&c.HideStreamName, "hide-stream-name", false, hideStreamNameSpec)
+	flags.BoolVar(&c.HideDate, "hide-date", false, hideDateSpec)
+	flags.BoolVar(&c.Invert, "invert", false, invertSpec)
+	flags.Var(&c.Grep, "grep", grepSpec)
+	flags.Var(&c.GrepV, "grep-v", grepVSpec)
+	flags.BoolVar(&c.JSONL, "jsonl", false, jsonlSpec)
 }
 
 func (c *outputConfiguration) Formatter() *colorjson.Formatter {
 	formatter := colorjson.NewFormatter()
 
-	if c.Expand {
+	if c.Expand || expandJSON {
 		formatter.Indent = 4
 	}
 
@@ -45,173 +63,516 @@ func (c *outputConfiguration) Formatter() *colorjson.Formatter {
 		formatter.KeyColor = color.New(color.FgBlack)
 	}
 
-	if c.NoColor {
+	if c.NoColor || noColor {
 		color.NoColor = true
 	}
 
 	return formatter
 }
 
-func printEvent(formatter *colorjson.Formatter, event *cloudwatchlogs.FilteredLogEvent) {
+// printEvent prints the event prefixed by its date and stream, with JSON messages formatted unless Raw.
+// The events left out by --grep or --grep-v are not printed, they were still seen.
+func printEvent(c *outputConfiguration, event *cloudwatchlogs.FilteredLogEvent) {
+	printLabeledEvent(c, "", event)
+}
+
+// printLabeledEvent prints the event after the label of the service or container it comes from, left out with --jsonl
+func printLabeledEvent(c *outputConfiguration, label string, event *cloudwatchlogs.FilteredLogEvent) {
+	if !c.shows(event) {
+		return
+	}
+
+	line := formatEvent(c, event)
+	if label != "" && !c.JSONL {
+		line = label + " " + line
+	}
+
+	// Flushed at every event, so what reads the output gets it as it comes and nothing is lost if killed
+	if c.writer == nil {
+		c.writer = bufio.NewWriter(os.Stdout)
+	}
+	c.writer.WriteString(line + "\n")
+	c.writer.Flush()
+}
+
+func formatEvent(c *outputConfiguration, event *cloudwatchlogs.FilteredLogEvent) string {
 	red := color.New(color.FgRed).SprintFunc()
 	white := color.New(color.FgWhite).SprintFunc()
 
-	str := aws.StringValue(event.Message)
-	bytes := []byte(str)
-	date := aws.MillisecondsTimeValue(event.Timestamp)
-	dateStr := date.Format(time.RFC3339)
-	streamStr := aws.StringValue(event.LogStreamName)
-	jl := map[string]interface{}{}
-	if err := json.Unmarshal(bytes, &jl); err != nil {
-		fmt.Printf("[%s] (%s) %s\n", red(dateStr), white(streamStr), str)
-	} else {
-		output, _ := formatter.Marshal(jl)
-		fmt.Printf("[%s] (%s) %s\n", red(dateStr), white(streamStr), output)
+	if c.formatter == nil {
+		c.formatter = c.Formatter()
+	}
+
+	if c.JSONL {
+		return formatEventJSONL(event)
+	}
+
+	message := aws.StringValue(event.Message)
+	if !c.Raw {
+		jl := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(message), &jl); err == nil {
+			output, _ := c.formatter.Marshal(jl)
+			message = string(output)
+		}
+	}
+
+	if !c.HideStreamName {
+		message = fmt.Sprintf("(%s) %s", white(aws.StringValue(event.LogStreamName)), message)
+	}
+
+	if !c.HideDate {
+		date := aws.MillisecondsTimeValue(event.Timestamp)
+		message = fmt.Sprintf("[%s] %s", red(date.Format(time.RFC3339)), message)
 	}
+
+	return message
 }
 
-func taskDefinitionsRunRun(cmd *cobra.Command, args []string) {
-	taskDefinitionFamily := args[0]
+type taskDefinitionsRunOptions struct {
+	cluster        string
+	revision       string
+	follow         bool
+	wait           bool
+	timeout        time.Duration
+	exit           bool
+	heartbeat      time.Duration
+	stallTimeout   time.Duration
+	stopOnStall    bool
+	pollInterval   time.Duration
+	filterPattern  string
+	maxLogRate     int
+	requireLogs    bool
+	logLocation    logLocation
+	output         outputConfiguration
+	explain        bool
+	preflight      bool
+	container      string
+	command        []string
+	env            []string
+	launchType     string
+	subnets        []string
+	securityGroups []string
+	assignPublicIP bool
+	attachStdin    bool
+	keepWarm       time.Duration
+	gpus           int64
+	count          int64
+	group          string
+	startedBy      string
+	tags           []string
+}
 
-	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
-		TaskDefinition: aws.String(taskDefinitionFamily),
-	})
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+var taskDefinitionsRunOpts taskDefinitionsRunOptions
+
+// parseEnvironment reads the KEY=VALUE pairs of --env
+func parseEnvironment(values []string) (env []*ecs.KeyValuePair, err error) {
+	for _, kv := range values {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 || kvs[0] == "" {
+			err = errors.New("Invalid environment variable '" + kv + "', expected 'KEY=VALUE'")
+			return
+		}
+
+		env = append(env, &ecs.KeyValuePair{
+			Name:  aws.String(kvs[0]),
+			Value: aws.String(kvs[1]),
+		})
 	}
+	return
+}
 
-	td := tdDescription.TaskDefinition
+// runNetworkConfiguration resolves the awsvpc configuration of the task, from the flags or the defaults of the config file.
+// Tasks on Fargate or with the awsvpc network mode can not run without subnets, so it fails before sending a doomed request.
+func runNetworkConfiguration(td *ecs.TaskDefinition, launchType string, subnets, securityGroups []string, assignPublicIP bool) (config *ecs.NetworkConfiguration, err error) {
+	if launchType != ecs.LaunchTypeFargate && aws.StringValue(td.NetworkMode) != ecs.NetworkModeAwsvpc {
+		if len(subnets) > 0 || len(securityGroups) > 0 {
+			err = errors.New("--subnet and --security-group only apply to the awsvpc network mode, " + familyRevision(td.Family, td.Revision) + " uses " + aws.StringValue(td.NetworkMode))
+		}
+		return
+	}
+
+	if len(subnets) == 0 {
+		subnets = viper.GetStringSlice("default_subnets")
+	}
+
+	if len(securityGroups) == 0 {
+		securityGroups = viper.GetStringSlice("default_security_groups")
+	}
 
-	if revision == "" {
-		revision = strconv.FormatInt(aws.Int64Value(td.Revision), 10)
+	if len(subnets) == 0 {
+		err = errors.New("Tasks on Fargate or with the awsvpc network mode need --subnet, or default_subnets on the config file")
+		return
 	}
 
-	taskResult, err := ecsI.RunTask(&ecs.RunTaskInput{
-		Cluster:        aws.String(cluster),
-		TaskDefinition: aws.String(taskDefinitionFamily + ":" + revision),
-		StartedBy:      aws.String("ecsctl"),
+	awsvpc := &ecs.AwsVpcConfiguration{AssignPublicIp: aws.String(ecs.AssignPublicIpDisabled)}
+	if assignPublicIP {
+		awsvpc.AssignPublicIp = aws.String(ecs.AssignPublicIpEnabled)
+	}
+
+	for _, s := range subnets {
+		subnet, err := findSubnet(s)
+		if err != nil {
+			return nil, err
+		}
+		awsvpc.Subnets = append(awsvpc.Subnets, subnet.SubnetId)
+	}
+
+	for _, s := range securityGroups {
+		sg, err := findSecurityGroup(s)
+		if err != nil {
+			return nil, err
+		}
+		awsvpc.SecurityGroups = append(awsvpc.SecurityGroups, sg.GroupId)
+	}
+
+	config = &ecs.NetworkConfiguration{AwsvpcConfiguration: awsvpc}
+	return
+}
+
+// pluralNetworkFlags accepts --subnets and --security-groups for the --subnet and --security-group flags,
+// as both take a list and the plural is what the AWS CLI and clusters add-instance use
+func pluralNetworkFlags(flags *pflag.FlagSet) {
+	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		switch name {
+		case "subnets":
+			name = "subnet"
+		case "security-groups":
+			name = "security-group"
+		}
+		return pflag.NormalizedName(name)
 	})
+}
+
+// runOverrides translates --command and --env into the overrides of the container, nil when there is none
+func runOverrides(cd *ecs.ContainerDefinition, command []string, env []*ecs.KeyValuePair, gpus int64) *ecs.TaskOverride {
+	if len(command) == 0 && len(env) == 0 && gpus == 0 {
+		return nil
+	}
+
+	override := &ecs.ContainerOverride{Name: cd.Name, Environment: env}
+	if len(command) > 0 {
+		override.Command = aws.StringSlice(command)
+	}
+
+	if gpus > 0 {
+		override.ResourceRequirements = []*ecs.ResourceRequirement{{
+			Type:  aws.String(ecs.ResourceTypeGpu),
+			Value: aws.String(strconv.FormatInt(gpus, 10)),
+		}}
+	}
+
+	return &ecs.TaskOverride{ContainerOverrides: []*ecs.ContainerOverride{override}}
+}
+
+// checkGPUCapacity fails when no container instance of the cluster has the GPU driver and as many GPUs available,
+// which RunTask only reports as a generic attribute error
+func checkGPUCapacity(cluster string, gpus int64) error {
+	instances, err := describeContainerInstances(cluster)
 	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
 
-	if len(taskResult.Tasks) == 0 {
-		fmt.Println(errors.New("task failed to run"))
-		os.Exit(1)
+	var withDriver int
+	var mostAvailable int64
+	for _, ci := range instances {
+		if _, found := instanceAttribute(ci, gpuDriverAttribute); !found {
+			continue
+		}
+
+		withDriver++
+		if available := gpuCount(ci.RemainingResources); available > mostAvailable {
+			mostAvailable = available
+		}
 	}
 
-	if !follow {
-		os.Exit(0)
+	switch {
+	case withDriver == 0:
+		return fmt.Errorf("The task needs %d GPUs, but no container instance of %s advertises %s. Add GPU instances (e.g. with the ECS GPU-optimized AMI) to the cluster", gpus, cluster, gpuDriverAttribute)
+	case mostAvailable < gpus:
+		return fmt.Errorf("The task needs %d GPUs, but the %d GPU instances of %s have at most %d available", gpus, withDriver, cluster, mostAvailable)
 	}
+	return nil
+}
 
-	if exit {
-		var gracefulStop = make(chan os.Signal)
-		signal.Notify(gracefulStop, syscall.SIGTERM)
-		signal.Notify(gracefulStop, syscall.SIGINT)
-		go func() {
-			<-gracefulStop
+func taskDefinitionsRunRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsRunOpts
 
-			ecsI.StopTask(&ecs.StopTaskInput{
-				Cluster: aws.String(cluster),
-				Task:    taskResult.Tasks[0].TaskArn,
-			})
+	if !opts.follow && (opts.heartbeat > 0 || opts.stallTimeout > 0 || opts.maxLogRate > 0 || opts.filterPattern != "" || opts.requireLogs) {
+		must(errors.New("--heartbeat, --stall-timeout, --max-log-rate, --filter-pattern and --require-logs require --follow"))
+	}
 
-			os.Exit(0)
-		}()
+	if opts.timeout > 0 && !opts.follow && !opts.wait {
+		must(errors.New("--timeout requires --wait or --follow"))
 	}
 
-	tSplited := strings.Split(aws.StringValue(taskResult.Tasks[0].TaskArn), "/")
-	taskID := tSplited[1]
+	if opts.stopOnStall && opts.stallTimeout == 0 {
+		must(errors.New("--stop-on-stall requires --stall-timeout"))
+	}
 
-	logDriver := td.ContainerDefinitions[0].LogConfiguration.LogDriver
-	if aws.StringValue(logDriver) != "awslogs" {
-		os.Exit(0)
+	if opts.pollInterval <= 0 {
+		must(errors.New("--poll-interval must be greater than zero"))
 	}
 
-	logPrefix := td.ContainerDefinitions[0].LogConfiguration.Options["awslogs-stream-prefix"]
-	logGroup := td.ContainerDefinitions[0].LogConfiguration.Options["awslogs-group"]
+	if opts.gpus < 0 {
+		must(errors.New("--gpus can not be negative"))
+	}
 
-	cName := td.ContainerDefinitions[0].Name
-	logStreamName := aws.StringValue(logPrefix) + "/" + aws.StringValue(cName) + "/" + taskID
+	if dash := cmd.ArgsLenAtDash(); dash == 1 {
+		if opts.keepWarm == 0 {
+			must(errors.New("The command after -- is only run with --keep-warm, use --command to override the one of the container"))
+		}
+		opts.command = args[dash:]
+	} else if len(args) > 1 {
+		must(errors.New("Inform the command to be run after --, e.g. run app --keep-warm 30m -- bin/test"))
+	}
+
+	if opts.keepWarm != 0 && opts.keepWarm < time.Minute {
+		must(errors.New("--keep-warm must be at least 1m"))
+	}
 
-	var lastSeenTime *int64
-	var seenEventIDs map[string]bool
-	output := outputConfiguration{}
-	formatter := output.Formatter()
+	if opts.count < 1 || opts.count > 10 {
+		must(errors.New("--count must be between 1 and 10"))
+	}
 
-	clearSeenEventIds := func() {
-		seenEventIDs = make(map[string]bool, 0)
+	// Only one task can be followed, waited for or attached to
+	if opts.count > 1 && (opts.follow || opts.wait || opts.attachStdin || opts.keepWarm > 0) {
+		must(errors.New("--count greater than 1 can not be used with --follow, --wait, --attach-stdin or --keep-warm, follow each task with task-definitions attach"))
 	}
 
-	addSeenEventIDs := func(id *string) {
-		seenEventIDs[*id] = true
+	if opts.startedBy != "" && opts.keepWarm > 0 {
+		must(errors.New("--started-by can not be used with --keep-warm, its tasks are found by their startedBy"))
 	}
 
-	updateLastSeenTime := func(ts *int64) {
-		if lastSeenTime == nil || *ts > *lastSeenTime {
-			lastSeenTime = ts
-			clearSeenEventIds()
+	tags, err := parseResourceTags(opts.tags)
+	must(err)
+
+	reference, err := taskDefinitionReference(args[0], opts.revision)
+	must(err)
+
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(reference),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		exit(1)
+	}
+
+	td := tdDescription.TaskDefinition
+
+	cd, err := containerDefinition(td, opts.container)
+	must(err)
+
+	env, err := parseEnvironment(opts.env)
+	must(err)
+
+	// With --attach-stdin or --keep-warm the task only sleeps, the real command is run through ECS Exec
+	command := opts.command
+	var attachedCommand []string
+	if opts.attachStdin || opts.keepWarm > 0 {
+		mode := "--attach-stdin"
+		if opts.keepWarm > 0 {
+			mode = "--keep-warm"
+		}
+
+		if opts.attachStdin && opts.keepWarm > 0 {
+			must(errors.New("--attach-stdin and --keep-warm can not be used together"))
+		}
+
+		if opts.follow || opts.wait {
+			must(errors.New(mode + " can not be used with --follow or --wait, the output comes through the session"))
+		}
+
+		must(checkECSExecPrerequisites())
+
+		if len(cd.EntryPoint) > 0 {
+			must(fmt.Errorf("%s overrides the command of %s with sleep, which does not work with its entryPoint", mode, aws.StringValue(cd.Name)))
+		}
+
+		attachedCommand = command
+		if len(attachedCommand) == 0 {
+			attachedCommand = aws.StringValueSlice(cd.Command)
+		}
+
+		if len(attachedCommand) == 0 {
+			must(errors.New(mode + " needs --command, or a command on the container of the Task Definition"))
+		}
+
+		command = []string{"sleep", "86400"}
+		if opts.keepWarm > 0 {
+			command = []string{"sleep", strconv.FormatInt(int64(opts.keepWarm.Seconds()), 10)}
 		}
 	}
 
-	cwInput := cloudwatchlogs.FilterLogEventsInput{
-		LogGroupName:   logGroup,
-		LogStreamNames: []*string{aws.String(logStreamName)},
+	launchType := strings.ToUpper(opts.launchType)
+	switch launchType {
+	case "", ecs.LaunchTypeEc2, ecs.LaunchTypeFargate, ecs.LaunchTypeExternal:
+	default:
+		must(errors.New("Invalid --launch-type '" + opts.launchType + "', expected EC2, FARGATE or EXTERNAL"))
 	}
 
-	handlePage := func(page *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
-		for _, event := range page.Events {
-			updateLastSeenTime(event.Timestamp)
-			if _, seen := seenEventIDs[*event.EventId]; !seen {
-				printEvent(formatter, event)
-				addSeenEventIDs(event.EventId)
-			}
+	networkConfiguration, err := runNetworkConfiguration(td, launchType, opts.subnets, opts.securityGroups, opts.assignPublicIP)
+	must(err)
+
+	// The placement checks see the task as it is run, with the GPUs of the override
+	placed := td
+	if opts.gpus > 0 {
+		placed = withContainerGPUs(td, aws.StringValue(cd.Name), opts.gpus)
+	}
+
+	if gpus := requirementsOf(placed).gpus; gpus > 0 {
+		if launchType == ecs.LaunchTypeFargate {
+			must(errors.New("FARGATE does not support GPUs, use the EC2 launch type"))
 		}
-		return !lastPage
+		must(checkGPUCapacity(opts.cluster, gpus))
 	}
 
-	retryCount := 0
-	retryLimit := 50
-	for {
-		err := cwlI.FilterLogEventsPages(&cwInput, handlePage)
-		if err != nil {
-			retryCount = retryCount + 1
+	if opts.preflight {
+		checks, err := preflightTaskDefinition(opts.cluster, placed, launchType)
+		must(err)
+		must(printPreflight(checks))
+	}
+
+	input := &ecs.RunTaskInput{
+		Cluster:              aws.String(opts.cluster),
+		TaskDefinition:       td.TaskDefinitionArn,
+		Count:                aws.Int64(opts.count),
+		StartedBy:            aws.String(firstSetting(opts.startedBy, "ecsctl")),
+		Overrides:            runOverrides(cd, command, env, opts.gpus),
+		NetworkConfiguration: networkConfiguration,
+		EnableExecuteCommand: aws.Bool(opts.attachStdin || opts.keepWarm > 0),
+		Tags:                 resourceTags(tags),
+	}
+
+	if opts.group != "" {
+		input.Group = aws.String(opts.group)
+	}
+
+	if opts.keepWarm > 0 {
+		input.StartedBy = aws.String(keepWarmStartedBy)
+	}
+
+	if launchType != "" {
+		input.LaunchType = aws.String(launchType)
+	}
+
+	taskResult, err := ecsI.RunTask(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		exit(1)
+	}
+
+	// With --count some tasks may start while others could not be placed
+	var reasons []string
+	for _, failure := range taskResult.Failures {
+		reason := aws.StringValue(failure.Reason)
+		if detail := aws.StringValue(failure.Detail); detail != "" {
+			reason += " (" + detail + ")"
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %s\n", aws.StringValue(failure.Arn), reason)
+		reasons = append(reasons, reason)
+	}
+
+	if len(taskResult.Tasks) == 0 {
+		// The explanation goes with the failures on the standard error, it is not an identifier
+		if opts.explain {
+			lines, err := explainPlacement(opts.cluster, placed)
+			must(err)
 
-			if retryCount >= retryLimit {
-				fmt.Println(err.Error())
-				os.Exit(1)
+			for _, line := range lines {
+				fmt.Fprintln(os.Stderr, line)
 			}
 		}
 
-		if lastSeenTime != nil {
-			cwInput.SetStartTime(*lastSeenTime)
+		message := "task failed to run"
+		if len(reasons) > 0 {
+			message += ": " + strings.Join(reasons, ", ")
 		}
+		fmt.Fprintln(os.Stderr, message)
+		exit(exitPlacementFailure)
+	}
+
+	for _, t := range taskResult.Tasks {
+		printTaskStarted(t, td, opts.logLocation)
+	}
+
+	if started := int64(len(taskResult.Tasks)); started < opts.count {
+		fmt.Fprintf(os.Stderr, "warning: only %d of %d tasks started\n", started, opts.count)
+	}
+
+	if opts.attachStdin {
+		attachStdin(opts.cluster, aws.StringValue(taskResult.Tasks[0].TaskArn), aws.StringValue(cd.Name), attachedCommand)
+	}
+
+	if opts.keepWarm > 0 {
+		execKeepWarm(opts.cluster, taskResult.Tasks[0], aws.StringValue(cd.Name), attachedCommand)
+	}
 
-		tasksStatus, err := ecsI.DescribeTasks(&ecs.DescribeTasksInput{
-			Cluster: aws.String(cluster),
-			Tasks:   []*string{aws.String(taskID)},
+	if !opts.follow && !opts.wait {
+		exit(0)
+	}
+
+	if !opts.follow {
+		waitTask(opts.cluster, taskResult.Tasks[0], td, followOptions{
+			exit:          opts.exit,
+			pollInterval:  opts.pollInterval,
+			timeout:       opts.timeout,
+			stopOnTimeout: true,
+			container:     aws.StringValue(cd.Name),
 		})
+	}
 
-		if err != nil {
-			fmt.Println(err.Error())
-			os.Exit(1)
-		}
+	// Every container is followed, unless one is chosen for the overrides
+	var containers containerFilter
+	if opts.container != "" {
+		containers.include = []string{opts.container}
+	}
 
-		status := aws.StringValue(tasksStatus.Tasks[0].LastStatus)
-		if status == "STOPPED" {
-			os.Exit(0)
-		}
+	followTask(opts.cluster, taskResult.Tasks[0], td, followOptions{
+		exit:          opts.exit,
+		heartbeat:     opts.heartbeat,
+		stallTimeout:  opts.stallTimeout,
+		stopOnStall:   opts.stopOnStall,
+		pollInterval:  opts.pollInterval,
+		timeout:       opts.timeout,
+		stopOnTimeout: true,
+		filterPattern: opts.filterPattern,
+		maxLogRate:    opts.maxLogRate,
+		requireLogs:   opts.requireLogs,
+		logLocation:   opts.logLocation,
+		output:        opts.output,
+		container:     aws.StringValue(cd.Name),
+		containers:    containers,
+	})
+}
 
-		time.Sleep(1 * time.Second)
+// attachStdin runs the command in the sleeping task with the standard input, then stops the task
+// and exits with the exit status of the command
+func attachStdin(cluster, task, container string, command []string) {
+	err := waitExecAgent(cluster, task, container, 5*time.Minute)
+
+	exitCode := 1
+	if err == nil {
+		exitCode, err = attachECSExec(cluster, task, container, command, os.Stdin)
 	}
+
+	_, stopErr := ecsI.StopTask(&ecs.StopTaskInput{
+		Cluster: aws.String(cluster),
+		Task:    aws.String(task),
+		Reason:  aws.String("Attached command finished"),
+	})
+	if stopErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to stop task %s: %s\n", taskID(task), stopErr.Error())
+	}
+
+	must(err)
+	exit(exitCode)
 }
 
 var taskDefinitionsRunCmd = &cobra.Command{
-	Use:   "run [task-definition]",
+	Use:   "run [family | family:revision | task-definition-arn] [-- command...]",
 	Short: "Run a Task Definition",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MinimumNArgs(1),
 	Run:   taskDefinitionsRunRun,
 }
 
@@ -220,15 +581,45 @@ func init() {
 
 	flags := taskDefinitionsRunCmd.Flags()
 
-	flags.BoolVar(&exit, "exit", false, exitSpec)
-
-	flags.BoolVarP(&follow, "follow", "f", false, followSpec)
-
-	flags.StringVar(&revision, "revision", "", revisionSpec)
-
-	flags.StringVarP(&cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.exit, "exit", false, exitSpec)
+
+	flags.BoolVarP(&taskDefinitionsRunOpts.follow, "follow", "f", false, followSpec)
+	flags.BoolVarP(&taskDefinitionsRunOpts.wait, "wait", "w", false, runWaitSpec)
+	flags.DurationVar(&taskDefinitionsRunOpts.timeout, "timeout", 0, runTimeoutSpec)
+
+	flags.DurationVar(&taskDefinitionsRunOpts.heartbeat, "heartbeat", 0, heartbeatSpec)
+	flags.DurationVar(&taskDefinitionsRunOpts.stallTimeout, "stall-timeout", 0, stallTimeoutSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.stopOnStall, "stop-on-stall", false, stopOnStallSpec)
+	flags.DurationVar(&taskDefinitionsRunOpts.pollInterval, "poll-interval", time.Second, pollIntervalSpec)
+	flags.StringVar(&taskDefinitionsRunOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	flags.IntVar(&taskDefinitionsRunOpts.maxLogRate, "max-log-rate", 0, maxLogRateSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.requireLogs, "require-logs", false, requireLogsSpec)
+	addLogOutputFlags(flags, &taskDefinitionsRunOpts.output)
+	addLogLocationFlags(flags, &taskDefinitionsRunOpts.logLocation)
+	flags.Int64Var(&taskDefinitionsRunOpts.gpus, "gpus", 0, gpusSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.explain, "explain", false, explainSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.preflight, "preflight", false, preflightSpec)
+
+	flags.StringVar(&taskDefinitionsRunOpts.revision, "revision", "", revisionSpec)
+
+	flags.StringVar(&taskDefinitionsRunOpts.container, "container", "", runContainerSpec)
+	flags.StringSliceVar(&taskDefinitionsRunOpts.command, "command", []string{}, runCommandSpec)
+	flags.StringArrayVar(&taskDefinitionsRunOpts.env, "env", []string{}, runEnvSpec)
+
+	flags.StringVar(&taskDefinitionsRunOpts.launchType, "launch-type", "", runLaunchTypeSpec)
+	flags.StringSliceVarP(&taskDefinitionsRunOpts.subnets, "subnet", "n", []string{}, runSubnetsSpec)
+	flags.StringSliceVarP(&taskDefinitionsRunOpts.securityGroups, "security-group", "g", []string{}, runSecurityGroupsSpec)
+	pluralNetworkFlags(flags)
+	flags.BoolVar(&taskDefinitionsRunOpts.assignPublicIP, "assign-public-ip", false, assignPublicIPSpec)
+	flags.BoolVar(&taskDefinitionsRunOpts.attachStdin, "attach-stdin", false, attachStdinSpec)
+	flags.DurationVar(&taskDefinitionsRunOpts.keepWarm, "keep-warm", 0, keepWarmSpec)
+
+	flags.Int64Var(&taskDefinitionsRunOpts.count, "count", 1, runCountSpec)
+	flags.StringVar(&taskDefinitionsRunOpts.group, "group", "", runGroupSpec)
+	flags.StringVar(&taskDefinitionsRunOpts.startedBy, "started-by", "", runStartedBySpec)
+	flags.StringSliceVarP(&taskDefinitionsRunOpts.tags, "tag", "t", []string{}, resourceTagsSpec)
+
+	flags.StringVarP(&taskDefinitionsRunOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
 
 	taskDefinitionsRunCmd.MarkFlagRequired("cluster")
-
-	viper.BindPFlag("cluster", taskDefinitionsRunCmd.Flags().Lookup("cluster"))
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/fatih/color"
+)
+
+func TestPrintEvent(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	at := time.Date(2026, 10, 15, 12, 30, 0, 250e6, time.UTC)
+	date := aws.MillisecondsTimeValue(aws.Int64(aws.TimeUnixMilli(at))).Format(time.RFC3339)
+	nanoDate := aws.MillisecondsTimeValue(aws.Int64(aws.TimeUnixMilli(at))).Format(time.RFC3339Nano)
+
+	event := func(message string) *cloudwatchlogs.FilteredLogEvent {
+		return &cloudwatchlogs.FilteredLogEvent{
+			Timestamp:     aws.Int64(aws.TimeUnixMilli(at)),
+			LogStreamName: aws.String("ecs/app/0a1b2c3d"),
+			Message:       aws.String(message),
+		}
+	}
+
+	grep := func(expression string) (p logPattern) {
+		if err := p.Set(expression); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	tests := []struct {
+		name   string
+		config outputConfiguration
+		label  string
+		event  *cloudwatchlogs.FilteredLogEvent
+		output string
+	}{
+		{
+			name:   "date and stream",
+			event:  event("listening on :8080"),
+			output: "[" + date + "] (ecs/app/0a1b2c3d) listening on :8080\n",
+		},
+		{
+			name:   "hide date",
+			config: outputConfiguration{HideDate: true},
+			event:  event("listening on :8080"),
+			output: "(ecs/app/0a1b2c3d) listening on :8080\n",
+		},
+		{
+			name:   "hide stream name",
+			config: outputConfiguration{HideStreamName: true},
+			event:  event("listening on :8080"),
+			output: "[" + date + "] listening on :8080\n",
+		},
+		{
+			name:   "message only",
+			config: outputConfiguration{HideDate: true, HideStreamName: true},
+			event:  event("listening on :8080"),
+			output: "listening on :8080\n",
+		},
+		{
+			name:   "raw JSON left as it is",
+			config: outputConfiguration{Raw: true, HideDate: true, HideStreamName: true},
+			event:  event(`{"level":"info", "msg":"ready"}`),
+			output: `{"level":"info", "msg":"ready"}` + "\n",
+		},
+		{
+			name:   "label",
+			config: outputConfiguration{HideDate: true},
+			label:  "[web]",
+			event:  event("listening on :8080"),
+			output: "[web] (ecs/app/0a1b2c3d) listening on :8080\n",
+		},
+		{
+			name:   "jsonl parses the JSON message",
+			config: outputConfiguration{JSONL: true},
+			event:  event(`{"level":"info","msg":"ready"}`),
+			output: `{"timestamp":"` + nanoDate + `","stream":"ecs/app/0a1b2c3d","container":"app","message":{"level":"info","msg":"ready"}}` + "\n",
+		},
+		{
+			name:   "jsonl leaves out the label",
+			config: outputConfiguration{JSONL: true, HideDate: true},
+			label:  "[web]",
+			event:  event("listening on :8080"),
+			output: `{"timestamp":"` + nanoDate + `","stream":"ecs/app/0a1b2c3d","container":"app","message":"listening on :8080"}` + "\n",
+		},
+		{
+			name:   "grep",
+			config: outputConfiguration{Grep: grep("ERROR"), HideDate: true, HideStreamName: true},
+			event:  event("INFO ready"),
+		},
+		{
+			name:   "grep-v",
+			config: outputConfiguration{GrepV: grep("health"), HideDate: true, HideStreamName: true},
+			label:  "[web]",
+			event:  event("GET /health 200"),
+		},
+		{
+			name:   "grep and grep-v",
+			config: outputConfiguration{Grep: grep("GET"), GrepV: grep("health"), HideDate: true, HideStreamName: true},
+			event:  event("GET /orders 200"),
+			output: "GET /orders 200\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			output := captureStdout(t, func() { printLabeledEvent(&config, test.label, test.event) })
+			if output != test.output {
+				t.Errorf("got %q, want %q", output, test.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsShrinkOptions struct {
+	file string
+}
+
+var taskDefinitionsShrinkOpts taskDefinitionsShrinkOptions
+
+// taskDefinitionReadOnlyFields are set by ECS on the described revision and rejected on registering
+var taskDefinitionReadOnlyFields = []string{
+	"taskDefinitionArn", "revision", "status", "requiresAttributes", "compatibilities",
+	"registeredAt", "registeredBy", "deregisteredAt",
+}
+
+// containerDefaults are the values ECS assumes for a container definition when they are not informed
+var containerDefaults = map[string]interface{}{
+	"essential":              true,
+	"cpu":                    float64(0),
+	"privileged":             false,
+	"readonlyRootFilesystem": false,
+	"disableNetworking":      false,
+	"interactive":            false,
+	"pseudoTerminal":         false,
+}
+
+// dropEmpty removes nulls and empty collections, recursively
+func dropEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			item = dropEmpty(item)
+			if isEmptyValue(item) {
+				delete(v, key)
+				continue
+			}
+			v[key] = item
+		}
+	case []interface{}:
+		var kept []interface{}
+		for _, item := range v {
+			if item = dropEmpty(item); !isEmptyValue(item) {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	}
+	return value
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// sortByName orders a list of objects (e.g. environment, secrets) by their name
+func sortByName(list interface{}) {
+	items, ok := list.([]interface{})
+	if !ok {
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := items[i].(map[string]interface{})
+		b, _ := items[j].(map[string]interface{})
+		return fmt.Sprint(a["name"]) < fmt.Sprint(b["name"])
+	})
+}
+
+// shrinkTaskDefinition reduces a Task Definition document, as registered or as described, to its minimal form.
+// The keys are sorted when encoded, as every object is a map.
+func shrinkTaskDefinition(document map[string]interface{}) map[string]interface{} {
+	document = unwrapTaskDefinition(document)
+
+	for _, field := range taskDefinitionReadOnlyFields {
+		delete(document, field)
+	}
+
+	containers, _ := document["containerDefinitions"].([]interface{})
+	for _, item := range containers {
+		cd, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for key, defaultValue := range containerDefaults {
+			if value, ok := cd[key]; ok && reflect.DeepEqual(value, defaultValue) {
+				delete(cd, key)
+			}
+		}
+
+		sortByName(cd["environment"])
+		sortByName(cd["secrets"])
+
+		mappings, _ := cd["portMappings"].([]interface{})
+		for _, m := range mappings {
+			if mapping, ok := m.(map[string]interface{}); ok && mapping["protocol"] == ecs.TransportProtocolTcp {
+				delete(mapping, "protocol")
+			}
+		}
+	}
+
+	return dropEmpty(document).(map[string]interface{})
+}
+
+// registrationInput decodes a document as the API would receive it, with the container defaults applied,
+// so two documents registering the same revision decode to the same input
+func registrationInput(document map[string]interface{}) (input *ecs.RegisterTaskDefinitionInput, err error) {
+	content, err := json.Marshal(unwrapTaskDefinition(document))
+	if err != nil {
+		return
+	}
+
+	var td struct {
+		ecs.TaskDefinition
+		Tags []*ecs.Tag
+	}
+	if err = json.Unmarshal(content, &td); err != nil {
+		return
+	}
+
+	input = &ecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions:    td.ContainerDefinitions,
+		Cpu:                     td.Cpu,
+		EphemeralStorage:        td.EphemeralStorage,
+		ExecutionRoleArn:        td.ExecutionRoleArn,
+		Family:                  td.Family,
+		InferenceAccelerators:   td.InferenceAccelerators,
+		IpcMode:                 td.IpcMode,
+		Memory:                  td.Memory,
+		NetworkMode:             td.NetworkMode,
+		PidMode:                 td.PidMode,
+		PlacementConstraints:    td.PlacementConstraints,
+		ProxyConfiguration:      td.ProxyConfiguration,
+		RequiresCompatibilities: td.RequiresCompatibilities,
+		RuntimePlatform:         td.RuntimePlatform,
+		Tags:                    td.Tags,
+		TaskRoleArn:             td.TaskRoleArn,
+		Volumes:                 td.Volumes,
+	}
+
+	for _, cd := range input.ContainerDefinitions {
+		if cd.Essential == nil {
+			cd.Essential = aws.Bool(true)
+		}
+
+		for _, b := range []**bool{&cd.Privileged, &cd.ReadonlyRootFilesystem, &cd.DisableNetworking, &cd.Interactive, &cd.PseudoTerminal} {
+			if *b == nil {
+				*b = aws.Bool(false)
+			}
+		}
+
+		if cd.Cpu == nil {
+			cd.Cpu = aws.Int64(0)
+		}
+
+		for _, m := range cd.PortMappings {
+			if m.Protocol == nil {
+				m.Protocol = aws.String(ecs.TransportProtocolTcp)
+			}
+		}
+
+		sort.SliceStable(cd.Environment, func(i, j int) bool {
+			return aws.StringValue(cd.Environment[i].Name) < aws.StringValue(cd.Environment[j].Name)
+		})
+		sort.SliceStable(cd.Secrets, func(i, j int) bool {
+			return aws.StringValue(cd.Secrets[i].Name) < aws.StringValue(cd.Secrets[j].Name)
+		})
+	}
+
+	// Empty collections and nulls decode the same way once encoded again
+	content, err = json.Marshal(input)
+	if err != nil {
+		return
+	}
+
+	var generic map[string]interface{}
+	if err = json.Unmarshal(content, &generic); err != nil {
+		return
+	}
+
+	content, err = json.Marshal(dropEmpty(generic))
+	if err != nil {
+		return
+	}
+
+	input = &ecs.RegisterTaskDefinitionInput{}
+	err = json.Unmarshal(content, input)
+	return
+}
+
+func taskDefinitionsShrinkRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsShrinkOpts
+
+	content, format, err := readInput(opts.file)
+	must(err)
+
+	// The original is decoded twice, shrinking changes the maps in place
+	var original, document map[string]interface{}
+	must(decodeInputContent(opts.file, content, format, &original))
+	must(decodeInputContent(opts.file, content, format, &document))
+
+	shrunk := shrinkTaskDefinition(document)
+
+	before, err := registrationInput(original)
+	must(err)
+
+	after, err := registrationInput(shrunk)
+	must(err)
+
+	if !reflect.DeepEqual(before, after) {
+		must(errors.New("The shrunk Task Definition would not register the same revision, please report it along with the document"))
+	}
+
+	output, err := json.MarshalIndent(shrunk, "", "  ")
+	must(err)
+	fmt.Println(string(output))
+}
+
+var taskDefinitionsShrinkCmd = &cobra.Command{
+	Use:   "shrink",
+	Short: "Print a Task Definition document in a minimal canonical form, without nulls, empty values and defaults",
+	Args:  cobra.NoArgs,
+	Run:   taskDefinitionsShrinkRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsShrinkCmd)
+
+	flags := taskDefinitionsShrinkCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsShrinkOpts.file, "file", "f", "", requiredSpec+taskDefinitionFileSpec)
+
+	taskDefinitionsShrinkCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+func readDocument(t *testing.T, path string) (document map[string]interface{}) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestShrinkTaskDefinition(t *testing.T) {
+	shrunk := shrinkTaskDefinition(readDocument(t, filepath.Join("testdata", "task_definition_described.json")))
+
+	content, err := json.MarshalIndent(shrunk, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "task_definition_shrunk.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content)+"\n" != string(want) {
+		t.Errorf("got:\n%s\nwant:\n%s", content, want)
+	}
+
+	// Shrinking is stable, the canonical form is its own shrunk form
+	var again map[string]interface{}
+	json.Unmarshal(content, &again)
+	if !reflect.DeepEqual(shrinkTaskDefinition(again), shrunk) {
+		t.Error("shrinking the shrunk document changed it")
+	}
+}
+
+// TestShrinkRegistersIdentically registers the described and the shrunk documents through the register command,
+// checking ECS receives the same revision once its defaults are applied
+func TestShrinkRegistersIdentically(t *testing.T) {
+	var registered []map[string]interface{}
+	fakeAWS(t, func(operation string, decode func(interface{})) (interface{}, error) {
+		if operation != "RegisterTaskDefinition" {
+			return nil, &fakeError{"InvalidParameterException", operation + " not expected"}
+		}
+
+		input := &ecs.RegisterTaskDefinitionInput{}
+		decode(input)
+
+		content, _ := json.Marshal(input)
+		var document map[string]interface{}
+		json.Unmarshal(content, &document)
+		registered = append(registered, document)
+
+		return &ecs.RegisterTaskDefinitionOutput{TaskDefinition: &ecs.TaskDefinition{
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:8"),
+			Family:            input.Family,
+			Revision:          aws.Int64(8),
+		}}, nil
+	})
+
+	defer func(opts taskDefinitionsRegisterOptions) { taskDefinitionsRegisterOpts = opts }(taskDefinitionsRegisterOpts)
+
+	// Shrunk here rather than by the command, which refuses to print a document failing this very check
+	described := filepath.Join("testdata", "task_definition_described.json")
+	shrunk, err := json.Marshal(shrinkTaskDefinition(readDocument(t, described)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shrunkFile := filepath.Join(t.TempDir(), "shrunk.json")
+	if err := os.WriteFile(shrunkFile, shrunk, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{described, shrunkFile} {
+		taskDefinitionsRegisterOpts = taskDefinitionsRegisterOptions{file: file}
+		captureStdout(t, func() { taskDefinitionsRegisterRun(taskDefinitionsRegisterCmd, nil) })
+	}
+
+	if len(registered) != 2 {
+		t.Fatalf("got %d revisions registered, want 2", len(registered))
+	}
+
+	before, err := registrationInput(registered[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := registrationInput(registered[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("the shrunk document registers\n%s\nthe described one\n%s", after, before)
+	}
+}
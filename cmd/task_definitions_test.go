@@ -0,0 +1,55 @@
+package cmd
+
+import "testing"
+
+func TestTaskDefinitionReference(t *testing.T) {
+	arn := "arn:aws:ecs:us-east-1:123456789012:task-definition/web:7"
+
+	tests := []struct {
+		arg       string
+		revision  string
+		reference string
+		fails     bool
+	}{
+		{arg: "web", reference: "web"},
+		{arg: "web", revision: "7", reference: "web:7"},
+		{arg: "web:7", reference: "web:7"},
+		{arg: arn, reference: arn},
+		{arg: "web:7", revision: "8", fails: true},
+		{arg: arn, revision: "8", fails: true},
+		{arg: "web:latest", fails: true},
+		{arg: "web", revision: "latest", fails: true},
+		{arg: "arn:aws:ecs:us-east-1:123456789012:task-definition/web", fails: true},
+		{arg: "arn:aws:ecs:us-east-1:123456789012:service/staging/web", fails: true},
+	}
+
+	for _, test := range tests {
+		reference, err := taskDefinitionReference(test.arg, test.revision)
+		switch {
+		case test.fails && err == nil:
+			t.Errorf("%s --revision %q: expected an error, got %s", test.arg, test.revision, reference)
+		case !test.fails && err != nil:
+			t.Errorf("%s --revision %q: %s", test.arg, test.revision, err.Error())
+		case reference != test.reference:
+			t.Errorf("%s --revision %q: got %s, want %s", test.arg, test.revision, reference, test.reference)
+		}
+	}
+}
+
+func TestSplitTaskDefinitionArn(t *testing.T) {
+	tests := []struct {
+		arn      string
+		family   string
+		revision int64
+	}{
+		{"arn:aws:ecs:us-east-1:123456789012:task-definition/web:7", "web", 7},
+		{"web:12", "web", 12},
+		{"web", "web", 0},
+	}
+
+	for _, test := range tests {
+		if family, revision := splitTaskDefinitionArn(test.arn); family != test.family || revision != test.revision {
+			t.Errorf("%s: got %s and %d, want %s and %d", test.arn, family, revision, test.family, test.revision)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type taskDefinitionsValidateOptions struct {
+	cluster    string
+	launchType string
+}
+
+var taskDefinitionsValidateOpts taskDefinitionsValidateOptions
+
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+type preflightCheck struct {
+	name   string
+	status string
+	detail string
+}
+
+func requiresCompatibility(td *ecs.TaskDefinition, compatibility string) bool {
+	for _, c := range td.RequiresCompatibilities {
+		if aws.StringValue(c) == compatibility {
+			return true
+		}
+	}
+	return false
+}
+
+func registeredResource(ci *ecs.ContainerInstance, name string) int64 {
+	for _, resource := range ci.RegisteredResources {
+		if aws.StringValue(resource.Name) == name {
+			return aws.Int64Value(resource.IntegerValue)
+		}
+	}
+	return 0
+}
+
+// preflightTaskDefinition checks if the Task Definition can be placed on the cluster at all.
+// The launch type is chosen from the compatibilities of the Task Definition when not informed.
+func preflightTaskDefinition(cluster string, td *ecs.TaskDefinition, launchType string) (checks []preflightCheck, err error) {
+	capabilities, err := probeCluster(cluster)
+	if err != nil {
+		return
+	}
+
+	if launchType == "" {
+		launchType = ecs.LaunchTypeEc2
+		if requiresCompatibility(td, ecs.LaunchTypeFargate) && (!requiresCompatibility(td, ecs.LaunchTypeEc2) || capabilities.instances == 0) {
+			launchType = ecs.LaunchTypeFargate
+		}
+	}
+
+	check := func(name, status, format string, a ...interface{}) {
+		checks = append(checks, preflightCheck{name, status, fmt.Sprintf(format, a...)})
+	}
+
+	if len(td.RequiresCompatibilities) > 0 && !requiresCompatibility(td, launchType) {
+		check("compatibility", checkFail, "requires %s, not %s", aws.StringValueSlice(td.RequiresCompatibilities), launchType)
+	} else {
+		check("compatibility", checkPass, "%s", launchType)
+	}
+
+	r := requirementsOf(td)
+	networkMode := aws.StringValue(td.NetworkMode)
+
+	if launchType == ecs.LaunchTypeFargate {
+		if err := capabilities.checkLaunchType(launchType); err != nil {
+			check("fargate", checkFail, "%s", err.Error())
+		} else if len(capabilities.capacityProviders) > 0 && !hasFargateCapacityProvider(capabilities.capacityProviders) {
+			check("fargate", checkWarn, "cluster has no FARGATE capacity provider, the FARGATE launch type must be informed explicitly")
+		} else {
+			check("fargate", checkPass, "available")
+		}
+
+		if networkMode != ecs.NetworkModeAwsvpc {
+			check("network mode", checkFail, "FARGATE requires awsvpc, got %s", networkMode)
+		} else {
+			check("network mode", checkPass, "%s", networkMode)
+		}
+
+		if td.Cpu == nil || td.Memory == nil {
+			check("size", checkFail, "FARGATE requires cpu and memory at the task level")
+		} else {
+			check("size", checkPass, "%d cpu, %d memory", r.cpu, r.memory)
+		}
+
+		return
+	}
+
+	instances, err := describeContainerInstances(cluster)
+	if err != nil {
+		return
+	}
+
+	if len(instances) == 0 {
+		check("container instances", checkFail, "cluster has no container instances")
+		return
+	}
+	check("container instances", checkPass, "%d registered", len(instances))
+
+	var largeEnough, withAttributes, withENI, withGPUs, availableNow int
+	var largestCPU, largestMemory int64
+	for _, ci := range instances {
+		cpu, memory := registeredResource(ci, "CPU"), registeredResource(ci, "MEMORY")
+		if cpu > largestCPU {
+			largestCPU = cpu
+		}
+		if memory > largestMemory {
+			largestMemory = memory
+		}
+		if cpu >= r.cpu && memory >= r.memory {
+			largeEnough++
+		}
+
+		missing := false
+		for _, attribute := range r.attributes {
+			value, found := instanceAttribute(ci, aws.StringValue(attribute.Name))
+			if !found || (attribute.Value != nil && value != aws.StringValue(attribute.Value)) {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			withAttributes++
+		}
+
+		if _, found := instanceAttribute(ci, "ecs.capability.task-eni"); found {
+			withENI++
+		}
+
+		if _, found := instanceAttribute(ci, gpuDriverAttribute); found && gpuCount(ci.RegisteredResources) >= r.gpus {
+			withGPUs++
+		}
+
+		if len(placementProblems(ci, r)) == 0 {
+			availableNow++
+		}
+	}
+
+	if largeEnough == 0 {
+		check("size", checkFail, "needs %d cpu and %d memory, the largest instance has %d cpu and %d memory", r.cpu, r.memory, largestCPU, largestMemory)
+	} else {
+		check("size", checkPass, "%d of %d instances are large enough", largeEnough, len(instances))
+	}
+
+	if withAttributes == 0 {
+		check("attributes", checkFail, "no instance has all the %d required attributes", len(r.attributes))
+	} else {
+		check("attributes", checkPass, "%d of %d instances have the required attributes", withAttributes, len(instances))
+	}
+
+	if r.gpus > 0 {
+		if withGPUs == 0 {
+			check("gpus", checkFail, "needs %d gpus, no instance advertises %s with as many", r.gpus, gpuDriverAttribute)
+		} else {
+			check("gpus", checkPass, "%d of %d instances have %d gpus", withGPUs, len(instances), r.gpus)
+		}
+	}
+
+	switch {
+	case networkMode == ecs.NetworkModeAwsvpc && withENI == 0:
+		check("network mode", checkFail, "awsvpc requires instances with the task-eni capability, none has it")
+	case networkMode == "":
+		check("network mode", checkPass, "%s", ecs.NetworkModeBridge)
+	default:
+		check("network mode", checkPass, "%s", networkMode)
+	}
+
+	if availableNow == 0 {
+		check("free capacity", checkWarn, "no instance can take the task right now, see --explain of run")
+	} else {
+		check("free capacity", checkPass, "%d instances can take the task right now", availableNow)
+	}
+
+	return
+}
+
+func hasFargateCapacityProvider(providers []string) bool {
+	for _, provider := range providers {
+		if provider == "FARGATE" || provider == "FARGATE_SPOT" {
+			return true
+		}
+	}
+	return false
+}
+
+// printPreflight prints a line per check and fails when any of them failed
+func printPreflight(checks []preflightCheck) (err error) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range checks {
+		if !quiet {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", c.status, c.name, c.detail)
+		}
+
+		if c.status == checkFail {
+			err = errors.New("The Task Definition can not be placed on the cluster")
+		}
+	}
+	w.Flush()
+	return
+}
+
+func taskDefinitionsValidateRun(cmd *cobra.Command, args []string) {
+	opts := &taskDefinitionsValidateOpts
+
+	reference, err := taskDefinitionReference(args[0], "")
+	must(err)
+
+	tdDescription, err := ecsI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(reference),
+	})
+	must(err)
+
+	checks, err := preflightTaskDefinition(opts.cluster, tdDescription.TaskDefinition, opts.launchType)
+	must(err)
+
+	must(printPreflight(checks))
+}
+
+var taskDefinitionsValidateCmd = &cobra.Command{
+	Use:     "validate [family[:revision]]",
+	Short:   "Check if a Task Definition can be placed on a cluster",
+	Aliases: []string{"validate-against-cluster"},
+	Args:    cobra.ExactArgs(1),
+	Run:     taskDefinitionsValidateRun,
+}
+
+func init() {
+	taskDefinitionsCmd.AddCommand(taskDefinitionsValidateCmd)
+
+	flags := taskDefinitionsValidateCmd.Flags()
+
+	flags.StringVarP(&taskDefinitionsValidateOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&taskDefinitionsValidateOpts.launchType, "launch-type", "", validateLaunchTypeSpec)
+
+	taskDefinitionsValidateCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+// defaultStopTimeout is the time ECS waits for containers without stopTimeout to exit after SIGTERM
+const defaultStopTimeout = 30 * time.Second
+
+// largestStopTimeout returns the longest graceful period of the containers of a Task Definition
+func largestStopTimeout(td *ecs.TaskDefinition) (largest time.Duration) {
+	largest = defaultStopTimeout
+	for _, cd := range td.ContainerDefinitions {
+		if cd.StopTimeout == nil {
+			continue
+		}
+
+		if t := time.Duration(aws.Int64Value(cd.StopTimeout)) * time.Second; t > largest {
+			largest = t
+		}
+	}
+	return
+}
+
+func printContainersExitCode(t *ecs.Task) {
+	for _, c := range t.Containers {
+		exitCode := "-"
+		if c.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", aws.Int64Value(c.ExitCode))
+		}
+
+		line := fmt.Sprintf("%s\t%s\texit code %s", taskID(aws.StringValue(t.TaskArn)), aws.StringValue(c.Name), exitCode)
+		if c.Reason != nil {
+			line = line + "\t" + aws.StringValue(c.Reason)
+		}
+
+		typist.Println(line)
+	}
+}
+
+// stopWaitMargin is how long ECS is given, past the graceful period, to deprovision the stopped tasks
+const stopWaitMargin = 2 * time.Minute
+
+// waitTasksStopped polls the tasks until all of them are STOPPED, counting down how long the graceful period can still take.
+// It gives up after forceAfter, or the graceful period and stopWaitMargin without it, or once interrupted,
+// returning the tasks still stopping.
+func waitTasksStopped(ctx context.Context, cluster string, taskArns []*string, forceAfter time.Duration) (stopping []*ecs.Task, err error) {
+	// The countdown is updated on a single line of a terminal, printed every 10s otherwise
+	terminal := isTerminal(os.Stderr) && !quiet
+	var printedAt time.Time
+	clearLine := func() {
+		if terminal && !printedAt.IsZero() {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	}
+	defer clearLine()
+
+	var gracefulPeriod time.Duration
+	started := time.Now()
+	for {
+		tasks, err := describeTasks(cluster, taskArns)
+		if err != nil {
+			return nil, err
+		}
+
+		stopping = nil
+		taskArns = nil
+		for _, t := range tasks {
+			if aws.StringValue(t.LastStatus) == ecs.DesiredStatusStopped {
+				clearLine()
+				printContainersExitCode(t)
+				continue
+			}
+
+			td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+			if err != nil {
+				return nil, err
+			}
+
+			if period := largestStopTimeout(td); period > gracefulPeriod {
+				gracefulPeriod = period
+			}
+
+			stopping = append(stopping, t)
+			taskArns = append(taskArns, t.TaskArn)
+		}
+
+		if len(stopping) == 0 {
+			return nil, nil
+		}
+
+		limit := forceAfter
+		if limit <= 0 {
+			limit = gracefulPeriod + stopWaitMargin
+		}
+
+		elapsed := time.Since(started)
+		if elapsed >= limit {
+			return stopping, nil
+		}
+
+		remaining := gracefulPeriod - elapsed
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		line := fmt.Sprintf("waiting for %d task(s) to stop, graceful period up to %s (%s left)",
+			len(stopping), gracefulPeriod, remaining.Round(time.Second))
+
+		switch {
+		case terminal:
+			fmt.Fprint(os.Stderr, "\r\033[K"+line)
+			printedAt = time.Now()
+		case time.Since(printedAt) >= 10*time.Second:
+			fmt.Fprintln(os.Stderr, line)
+			printedAt = time.Now()
+		}
+
+		if !sleepContext(ctx, 2*time.Second) {
+			return stopping, nil
+		}
+	}
+}
+
+func tasksRun(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+var tasksCmd = &cobra.Command{
+	Use:     "tasks [command]",
+	Short:   "Commands to manage tasks",
+	Aliases: []string{"task"},
+	Run:     tasksRun,
+}
+
+func init() {
+	rootCmd.AddCommand(tasksCmd)
+}
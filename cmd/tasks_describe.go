@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type tasksDescribeOptions struct {
+	cluster      string
+	classifyOnly bool
+}
+
+var tasksDescribeOpts tasksDescribeOptions
+
+func formatTaskTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	if absoluteTimes {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(time.RFC3339) + " (" + humanizeTime(t) + ")"
+}
+
+// printTaskOutcome prints the classification of a stopped task
+func printTaskOutcome(t *ecs.Task) {
+	if aws.StringValue(t.LastStatus) != ecs.DesiredStatusStopped {
+		must(fmt.Errorf("Task %s is %s, only stopped tasks are classified", taskID(aws.StringValue(t.TaskArn)), aws.StringValue(t.LastStatus)))
+	}
+
+	td, err := describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+	must(err)
+
+	// The first container is the one classified, as by run and wait without --container
+	cd, err := containerDefinition(td, "")
+	must(err)
+
+	outcome := classifyTask(t, td, aws.StringValue(cd.Name))
+
+	switch {
+	case outputFormat == "json":
+		must(printJSON(outcome))
+	case quiet:
+		printID(outcome.Class)
+	default:
+		typist.Println(outcome.String())
+	}
+}
+
+func tasksDescribeRun(cmd *cobra.Command, args []string) {
+	opts := &tasksDescribeOpts
+
+	must(checkOutputFormat(outputFormat, "text", "json"))
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(args[0])})
+	must(err)
+
+	if len(tasks) == 0 {
+		must(errors.New("Task " + args[0] + " not found on " + opts.cluster))
+	}
+
+	t := tasks[0]
+
+	if opts.classifyOnly {
+		printTaskOutcome(t)
+		return
+	}
+
+	if outputFormat == "json" {
+		must(printJSON(t))
+		return
+	}
+
+	if quiet {
+		printID(aws.StringValue(t.TaskArn))
+		return
+	}
+
+	row := newTaskRow(t)
+
+	typist.Printf("Task:               %s\n", row.TaskID)
+	typist.Printf("ARN:                %s\n", row.TaskArn)
+	typist.Printf("Task Definition:    %s\n", row.TaskDefinition)
+	typist.Printf("Status:             %s (desired %s)\n", row.LastStatus, row.DesiredStatus)
+	typist.Printf("Started By:         %s\n", aws.StringValue(t.StartedBy))
+	typist.Printf("Placement:          %s\n", row.placedOn())
+	typist.Printf("Created:            %s\n", formatTaskTime(t.CreatedAt))
+	typist.Printf("Started:            %s\n", formatTaskTime(t.StartedAt))
+
+	if t.StoppedAt != nil || t.StoppingAt != nil {
+		typist.Printf("Stopped:            %s\n", formatTaskTime(t.StoppedAt))
+		typist.Printf("Stop Code:          %s\n", aws.StringValue(t.StopCode))
+		typist.Printf("Stopped Reason:     %s\n", aws.StringValue(t.StoppedReason))
+	}
+
+	typist.Println("Containers:")
+	w := newTable()
+	fmt.Fprintln(w, "  NAME\tSTATUS\tHEALTH\tEXIT CODE\tIMAGE\tREASON")
+	for _, c := range t.Containers {
+		exitCode := "-"
+		if c.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", aws.Int64Value(c.ExitCode))
+		}
+
+		reason := aws.StringValue(c.Reason)
+		if reason == "" {
+			reason = "-"
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", aws.StringValue(c.Name), aws.StringValue(c.LastStatus),
+			aws.StringValue(c.HealthStatus), exitCode, aws.StringValue(c.Image), reason)
+	}
+	w.Flush()
+}
+
+var tasksDescribeCmd = &cobra.Command{
+	Use:   "describe [task]",
+	Short: "Describe a task: status, placement, stopped reason and the exit code of its containers",
+	Args:  cobra.ExactArgs(1),
+	Run:   tasksDescribeRun,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksDescribeCmd)
+
+	registerOutputSchema(tasksDescribeCmd, "tasks describe", ecs.Task{})
+
+	flags := tasksDescribeCmd.Flags()
+
+	flags.StringVarP(&tasksDescribeOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.BoolVar(&tasksDescribeOpts.classifyOnly, "classify-only", false, classifyOnlySpec)
+
+	tasksDescribeCmd.MarkFlagRequired("cluster")
+}
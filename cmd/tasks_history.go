@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type tasksHistoryOptions struct {
+	cluster string
+}
+
+var tasksHistoryOpts tasksHistoryOptions
+
+// Phases taking longer than these are flagged on the timeline
+const (
+	slowPullThreshold    = 2 * time.Minute
+	slowPendingThreshold = 5 * time.Minute
+)
+
+type taskPhase struct {
+	Phase    string    `json:"phase"`
+	At       time.Time `json:"at"`
+	Duration string    `json:"durationSincePrevious,omitempty"`
+	Slow     bool      `json:"slow,omitempty"`
+}
+
+type taskHistory struct {
+	Task            string      `json:"task"`
+	TaskDefinition  string      `json:"taskDefinition"`
+	LastStatus      string      `json:"lastStatus"`
+	Phases          []taskPhase `json:"phases"`
+	PullDuration    string      `json:"pullDuration,omitempty"`
+	PendingDuration string      `json:"pendingDuration,omitempty"`
+	TotalDuration   string      `json:"totalDuration,omitempty"`
+	StopCode        string      `json:"stopCode,omitempty"`
+	StoppedReason   string      `json:"stoppedReason,omitempty"`
+}
+
+// buildTaskHistory orders the lifecycle timestamps of the task, skipping the ones it did not reach
+func buildTaskHistory(t *ecs.Task) (h taskHistory) {
+	h = taskHistory{
+		Task:           taskID(aws.StringValue(t.TaskArn)),
+		TaskDefinition: aws.StringValue(t.TaskDefinitionArn),
+		LastStatus:     aws.StringValue(t.LastStatus),
+		StopCode:       aws.StringValue(t.StopCode),
+		StoppedReason:  aws.StringValue(t.StoppedReason),
+	}
+
+	candidates := []struct {
+		phase string
+		at    *time.Time
+	}{
+		{"created", t.CreatedAt},
+		{"connectivity established", t.ConnectivityAt},
+		{"pull started", t.PullStartedAt},
+		{"pull stopped", t.PullStoppedAt},
+		{"started", t.StartedAt},
+		{"stopping", t.StoppingAt},
+		{"execution stopped", t.ExecutionStoppedAt},
+		{"stopped", t.StoppedAt},
+	}
+
+	var previous time.Time
+	for _, c := range candidates {
+		if c.at == nil || c.at.IsZero() {
+			continue
+		}
+
+		phase := taskPhase{Phase: c.phase, At: *c.at}
+		if !previous.IsZero() {
+			phase.Duration = c.at.Sub(previous).Round(time.Millisecond).String()
+		}
+		previous = *c.at
+
+		h.Phases = append(h.Phases, phase)
+	}
+
+	if t.PullStartedAt != nil && t.PullStoppedAt != nil {
+		pull := t.PullStoppedAt.Sub(*t.PullStartedAt)
+		h.PullDuration = pull.Round(time.Millisecond).String()
+		if pull > slowPullThreshold {
+			h.markSlow("pull stopped")
+		}
+	}
+
+	if t.CreatedAt != nil && t.StartedAt != nil {
+		pending := t.StartedAt.Sub(*t.CreatedAt)
+		h.PendingDuration = pending.Round(time.Millisecond).String()
+		if pending > slowPendingThreshold {
+			h.markSlow("started")
+		}
+	}
+
+	if len(h.Phases) > 1 {
+		h.TotalDuration = h.Phases[len(h.Phases)-1].At.Sub(h.Phases[0].At).Round(time.Millisecond).String()
+	}
+	return
+}
+
+func (h *taskHistory) markSlow(phase string) {
+	for i := range h.Phases {
+		if h.Phases[i].Phase == phase {
+			h.Phases[i].Slow = true
+		}
+	}
+}
+
+func printTaskHistory(h taskHistory) {
+	typist.Printf("%s (%s) %s\n", h.Task, h.TaskDefinition, h.LastStatus)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, p := range h.Phases {
+		duration := p.Duration
+		if duration != "" {
+			duration = "+" + duration
+		}
+
+		line := fmt.Sprintf("  %s\t%s\t%s", p.At.Format(time.RFC3339Nano), p.Phase, duration)
+		if p.Slow {
+			line = color.YellowString(line + "\t(slow)")
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+
+	if h.PullDuration != "" {
+		pull := "image pull took " + h.PullDuration
+		if p, _ := time.ParseDuration(h.PullDuration); p > slowPullThreshold {
+			pull = color.YellowString(pull)
+		}
+		typist.Println(pull)
+	}
+
+	if h.PendingDuration != "" {
+		pending := "PENDING for " + h.PendingDuration
+		if p, _ := time.ParseDuration(h.PendingDuration); p > slowPendingThreshold {
+			pending = color.YellowString(pending)
+		}
+		typist.Println(pending)
+	}
+
+	if h.TotalDuration != "" {
+		typist.Println("total " + h.TotalDuration)
+	}
+
+	if h.StopCode != "" || h.StoppedReason != "" {
+		typist.Printf("stopped: %s %s\n", h.StopCode, h.StoppedReason)
+	}
+}
+
+func tasksHistoryRun(cmd *cobra.Command, args []string) {
+	opts := &tasksHistoryOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(args[0])})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	h := buildTaskHistory(tasks[0])
+
+	switch outputFormat {
+	case "json":
+		must(printVersionedJSON(h))
+	case "text", "table":
+		printTaskHistory(h)
+	}
+}
+
+var tasksHistoryCmd = &cobra.Command{
+	Use:         "history [task]",
+	Short:       "Show the lifecycle timeline of a task",
+	Args:        cobra.ExactArgs(1),
+	Run:         tasksHistoryRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksHistoryCmd)
+
+	registerOutputSchema(tasksHistoryCmd, "tasks history", taskHistory{})
+
+	flags := tasksHistoryCmd.Flags()
+
+	flags.StringVarP(&tasksHistoryOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+
+	tasksHistoryCmd.MarkFlagRequired("cluster")
+}
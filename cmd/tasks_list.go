@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type tasksListOptions struct {
+	cluster string
+	service string
+	status  string
+}
+
+var tasksListOpts tasksListOptions
+
+type taskRow struct {
+	TaskArn           string     `json:"taskArn"`
+	TaskID            string     `json:"taskId"`
+	TaskDefinition    string     `json:"taskDefinition"`
+	LastStatus        string     `json:"lastStatus"`
+	DesiredStatus     string     `json:"desiredStatus"`
+	LaunchType        string     `json:"launchType"`
+	ContainerInstance string     `json:"containerInstance,omitempty"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	StoppedReason     string     `json:"stoppedReason,omitempty"`
+}
+
+func newTaskRow(t *ecs.Task) *taskRow {
+	family, revision := splitTaskDefinitionArn(aws.StringValue(t.TaskDefinitionArn))
+
+	row := &taskRow{
+		TaskArn:        aws.StringValue(t.TaskArn),
+		TaskID:         taskID(aws.StringValue(t.TaskArn)),
+		TaskDefinition: fmt.Sprintf("%s:%d", family, revision),
+		LastStatus:     aws.StringValue(t.LastStatus),
+		DesiredStatus:  aws.StringValue(t.DesiredStatus),
+		LaunchType:     aws.StringValue(t.LaunchType),
+		StartedAt:      t.StartedAt,
+		StoppedReason:  aws.StringValue(t.StoppedReason),
+	}
+
+	if arn := aws.StringValue(t.ContainerInstanceArn); arn != "" {
+		row.ContainerInstance = arn[strings.LastIndex(arn, "/")+1:]
+	}
+	return row
+}
+
+// placedOn is where the task runs: its container instance, or FARGATE
+func (row *taskRow) placedOn() string {
+	if row.ContainerInstance != "" {
+		return row.ContainerInstance
+	}
+
+	if row.LaunchType != "" {
+		return row.LaunchType
+	}
+	return "-"
+}
+
+func tasksListRun(cmd *cobra.Command, args []string) {
+	opts := &tasksListOpts
+
+	must(checkOutputFormat(outputFormat, "text", "table", "json"))
+
+	if opts.status != ecs.DesiredStatusRunning && opts.status != ecs.DesiredStatusStopped {
+		must(fmt.Errorf("Invalid status %s, valid statuses are: %s, %s", opts.status, ecs.DesiredStatusRunning, ecs.DesiredStatusStopped))
+	}
+
+	input := &ecs.ListTasksInput{
+		Cluster:       aws.String(opts.cluster),
+		DesiredStatus: aws.String(opts.status),
+	}
+
+	if opts.service != "" {
+		input.ServiceName = aws.String(opts.service)
+	}
+
+	taskArns, err := ecsxI.ListAllTasks(input)
+	must(err)
+
+	if quiet {
+		for _, arn := range taskArns {
+			printID(aws.StringValue(arn))
+		}
+		return
+	}
+
+	tasks, err := describeTasks(opts.cluster, taskArns)
+	must(err)
+
+	rows := []*taskRow{}
+	for _, t := range tasks {
+		rows = append(rows, newTaskRow(t))
+	}
+
+	if outputFormat == "json" {
+		must(printJSON(rows))
+		return
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "ID\tTASK DEFINITION\tSTATUS\tSTARTED\tCONTAINER INSTANCE")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", row.TaskID, row.TaskDefinition, row.LastStatus, humanizeTime(row.StartedAt), row.placedOn())
+	}
+	w.Flush()
+}
+
+var tasksListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the tasks of a cluster, or of a service",
+	Args:        cobra.NoArgs,
+	Run:         tasksListRun,
+	Annotations: pagedOutput,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksListCmd)
+
+	registerOutputSchema(tasksListCmd, "tasks list", []*taskRow{})
+
+	flags := tasksListCmd.Flags()
+
+	flags.StringVarP(&tasksListOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVarP(&tasksListOpts.service, "service", "s", "", tasksServiceSpec)
+	flags.StringVar(&tasksListOpts.status, "status", ecs.DesiredStatusRunning, taskStatusSpec)
+
+	tasksListCmd.MarkFlagRequired("cluster")
+
+	getAlias(tasksListCmd, "tasks", "task")
+}
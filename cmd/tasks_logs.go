@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/spf13/cobra"
+)
+
+type tasksLogsOptions struct {
+	cluster       string
+	since         string
+	filterPattern string
+	replay        bool
+	speed         string
+	noDelay       bool
+	logLocation   logLocation
+	output        outputConfiguration
+}
+
+var tasksLogsOpts tasksLogsOptions
+
+// parseSpeed reads a replay speed factor as 10x or 10, 0.5x slows it down
+func parseSpeed(speed string) (factor float64, err error) {
+	factor, err = strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(speed), "x"), 64)
+	if err != nil || factor <= 0 {
+		err = errors.New("Invalid --speed '" + speed + "', e.g. --speed 10x")
+	}
+	return
+}
+
+// formatTimeline shows the position on the timeline as [h:]mm:ss
+func formatTimeline(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// replayEvents prints the events spaced as they originally happened, divided by the speed factor.
+// It returns false when interrupted before the end.
+func replayEvents(output *outputConfiguration, events []*cloudwatchlogs.FilteredLogEvent, speed float64) bool {
+	if len(events) == 0 {
+		return true
+	}
+
+	ctx := interruptContext()
+
+	first := aws.MillisecondsTimeValue(events[0].Timestamp)
+	total := aws.MillisecondsTimeValue(events[len(events)-1].Timestamp).Sub(first)
+
+	progress := func(at time.Duration) {
+		if quiet {
+			return
+		}
+
+		percent := 100
+		if total > 0 {
+			percent = int(100 * at / total)
+		}
+		fmt.Fprintf(os.Stderr, "\r\033[K[%3d%%] %s / %s", percent, formatTimeline(at), formatTimeline(total))
+	}
+
+	previous := first
+	for _, event := range events {
+		at := aws.MillisecondsTimeValue(event.Timestamp)
+		delay := time.Duration(float64(at.Sub(previous)) / speed)
+		previous = at
+
+		if delay > 0 && !sleepContext(ctx, delay) {
+			fmt.Fprintf(os.Stderr, "\nreplay interrupted at %s of %s\n", formatTimeline(at.Sub(first)), formatTimeline(total))
+			return false
+		}
+
+		if !quiet {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+		printEvent(output, event)
+		progress(at.Sub(first))
+	}
+
+	if !quiet {
+		fmt.Fprintln(os.Stderr)
+	}
+	return true
+}
+
+func tasksLogsRun(cmd *cobra.Command, args []string) {
+	opts := &tasksLogsOpts
+
+	if opts.replay && opts.noDelay {
+		must(errors.New("--replay and --no-delay can not be used together"))
+	}
+
+	speed, err := parseSpeed(opts.speed)
+	must(err)
+
+	var startTime time.Time
+	if opts.since != "" {
+		startTime, err = parseSince(opts.since)
+		must(err)
+	}
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(args[0])})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	td, err := describeTaskDefinition(aws.StringValue(tasks[0].TaskDefinitionArn))
+	must(err)
+
+	streams := opts.logLocation.resolve(taskLogStreams(td, taskID(aws.StringValue(tasks[0].TaskArn))))
+	if len(streams) == 0 {
+		must(errors.New("No container of the task logs with the awslogs driver"))
+	}
+
+	events, err := fetchLogEvents(streams, startTime, opts.filterPattern)
+	must(err)
+
+	if opts.replay {
+		if !replayEvents(&opts.output, events, speed) {
+			exit(130)
+		}
+		return
+	}
+
+	for _, event := range events {
+		printEvent(&opts.output, event)
+	}
+}
+
+var tasksLogsCmd = &cobra.Command{
+	Use:   "logs [task]",
+	Short: "Show the CloudWatch logs of a task, optionally replayed with their original pacing",
+	Args:  cobra.ExactArgs(1),
+	Run:   tasksLogsRun,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksLogsCmd)
+
+	flags := tasksLogsCmd.Flags()
+
+	flags.StringVarP(&tasksLogsOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&tasksLogsOpts.since, "since", "", sinceSpec)
+	flags.StringVar(&tasksLogsOpts.filterPattern, "filter-pattern", "", filterPatternSpec)
+	flags.BoolVar(&tasksLogsOpts.replay, "replay", false, replaySpec)
+	flags.StringVar(&tasksLogsOpts.speed, "speed", "1x", replaySpeedSpec)
+	flags.BoolVar(&tasksLogsOpts.noDelay, "no-delay", false, noDelaySpec)
+	addLogOutputFlags(flags, &tasksLogsOpts.output)
+	addLogLocationFlags(flags, &tasksLogsOpts.logLocation)
+
+	tasksLogsCmd.MarkFlagRequired("cluster")
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/spf13/cobra"
+)
+
+type tasksProtectOptions struct {
+	cluster   string
+	expiresIn int64
+	release   bool
+	list      bool
+}
+
+var tasksProtectOpts tasksProtectOptions
+
+// explainProtectionFailure turns the documented failure reasons of the task protection API into something actionable,
+// other reasons are passed through as they are
+func explainProtectionFailure(reason, detail string) string {
+	var explained string
+	switch reason {
+	case "TASK_NOT_VALID":
+		explained = "only running tasks started by a service can be protected, standalone tasks are never scaled in"
+	case "MISSING":
+		explained = "task not found on the cluster"
+	default:
+		explained = reason
+	}
+
+	if detail != "" {
+		return explained + ": " + detail
+	}
+	return explained
+}
+
+// explainProtectionError does the same for the errors of the whole request
+func explainProtectionError(err error) error {
+	if awsErrorCode(err) == "AccessDeniedException" {
+		return errors.New("Access denied changing the task protection, ecs:UpdateTaskProtection is needed on the tasks")
+	}
+	return err
+}
+
+func printProtectedTask(t *ecs.ProtectedTask) {
+	id := taskID(aws.StringValue(t.TaskArn))
+
+	if !aws.BoolValue(t.ProtectionEnabled) {
+		typist.Printf("%s\tnot protected\n", id)
+		return
+	}
+
+	expiration := aws.TimeValue(t.ExpirationDate)
+	typist.Printf("%s\tprotected until %s (%s left)\n", id, expiration.Format(time.RFC3339), humanizeDuration(time.Until(expiration)))
+}
+
+func listProtectedTasks(cluster string) {
+	taskArns, err := ecsxI.ListAllTasks(&ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	must(err)
+
+	tasks, err := describeTasks(cluster, taskArns)
+	must(err)
+
+	var serviceTaskArns []*string
+	for _, t := range tasks {
+		if strings.HasPrefix(aws.StringValue(t.Group), "service:") {
+			serviceTaskArns = append(serviceTaskArns, t.TaskArn)
+		}
+	}
+
+	// GetTaskProtection accepts up to 10 tasks per request
+	for start := 0; start < len(serviceTaskArns); start += 10 {
+		end := start + 10
+		if end > len(serviceTaskArns) {
+			end = len(serviceTaskArns)
+		}
+
+		result, err := ecsI.GetTaskProtection(&ecs.GetTaskProtectionInput{
+			Cluster: aws.String(cluster),
+			Tasks:   serviceTaskArns[start:end],
+		})
+		must(err)
+
+		for _, t := range result.ProtectedTasks {
+			if aws.BoolValue(t.ProtectionEnabled) {
+				printProtectedTask(t)
+			}
+		}
+	}
+}
+
+func tasksProtectRun(cmd *cobra.Command, tasks []string) {
+	opts := &tasksProtectOpts
+
+	if opts.list {
+		listProtectedTasks(opts.cluster)
+		return
+	}
+
+	if len(tasks) == 0 {
+		must(errors.New("No tasks informed"))
+	}
+
+	if opts.expiresIn < 1 || opts.expiresIn > 2880 {
+		must(errors.New("--expires-in must be between 1 and 2880 minutes"))
+	}
+
+	var failed int
+	for start := 0; start < len(tasks); start += 10 {
+		end := start + 10
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+
+		input := &ecs.UpdateTaskProtectionInput{
+			Cluster:           aws.String(opts.cluster),
+			Tasks:             aws.StringSlice(tasks[start:end]),
+			ProtectionEnabled: aws.Bool(!opts.release),
+		}
+
+		if !opts.release {
+			input.ExpiresInMinutes = aws.Int64(opts.expiresIn)
+		}
+
+		result, err := ecsI.UpdateTaskProtection(input)
+		must(explainProtectionError(err))
+
+		for _, t := range result.ProtectedTasks {
+			printProtectedTask(t)
+		}
+
+		for _, f := range result.Failures {
+			failed = failed + 1
+			fmt.Fprintf(os.Stderr, "%s\t%s\n", taskID(aws.StringValue(f.Arn)), explainProtectionFailure(aws.StringValue(f.Reason), aws.StringValue(f.Detail)))
+		}
+	}
+
+	if failed > 0 {
+		must(fmt.Errorf("%d of %d tasks failed", failed, len(tasks)))
+	}
+}
+
+var tasksProtectCmd = &cobra.Command{
+	Use:   "protect [tasks...]",
+	Short: "Protect tasks of services from being stopped by scale-in events",
+	Run:   tasksProtectRun,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksProtectCmd)
+
+	flags := tasksProtectCmd.Flags()
+
+	flags.StringVarP(&tasksProtectOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.Int64Var(&tasksProtectOpts.expiresIn, "expires-in", 120, expiresInSpec)
+	flags.BoolVar(&tasksProtectOpts.release, "release", false, releaseSpec)
+	flags.BoolVar(&tasksProtectOpts.list, "list", false, listProtectedSpec)
+
+	tasksProtectCmd.MarkFlagRequired("cluster")
+}
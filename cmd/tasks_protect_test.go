@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestExplainProtectionFailure(t *testing.T) {
+	tests := []struct {
+		reason string
+		detail string
+		want   string
+	}{
+		{"TASK_NOT_VALID", "", "only running tasks started by a service can be protected, standalone tasks are never scaled in"},
+		{"TASK_NOT_VALID", "The task is not part of a service", "only running tasks started by a service can be protected, standalone tasks are never scaled in: The task is not part of a service"},
+		{"MISSING", "", "task not found on the cluster"},
+		{"SERVICE_UNAVAILABLE", "Try again later", "SERVICE_UNAVAILABLE: Try again later"},
+		{"INTERNAL_ERROR", "", "INTERNAL_ERROR"},
+	}
+
+	for _, test := range tests {
+		if got := explainProtectionFailure(test.reason, test.detail); got != test.want {
+			t.Errorf("explainProtectionFailure(%q, %q) = %q, want %q", test.reason, test.detail, got, test.want)
+		}
+	}
+}
+
+func TestExplainProtectionError(t *testing.T) {
+	denied := awserr.New("AccessDeniedException", "not authorized to perform ecs:UpdateTaskProtection", nil)
+	if got := explainProtectionError(denied); got == denied {
+		t.Errorf("got %v, want the access denied explained", got)
+	}
+
+	// Unrecognised errors, even mentioning a service, keep their own message
+	invalid := awserr.New("InvalidParameterException", "The service could not be found", nil)
+	if got := explainProtectionError(invalid); got != invalid {
+		t.Errorf("got %v, want %v", got, invalid)
+	}
+
+	if got := explainProtectionError(nil); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+
+	other := errors.New("connection reset")
+	if got := explainProtectionError(other); got != other {
+		t.Errorf("got %v, want %v", got, other)
+	}
+}
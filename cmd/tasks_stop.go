@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+)
+
+type tasksStopOptions struct {
+	cluster     string
+	reason      string
+	wait        bool
+	forceAfter  time.Duration
+	stdin       bool
+	yes         bool
+	concurrency int
+}
+
+var tasksStopOpts tasksStopOptions
+
+func tasksStopRun(cmd *cobra.Command, args []string) {
+	opts := &tasksStopOpts
+
+	tasks, err := batchItems(args, opts.stdin, taskPattern, "tasks")
+	must(err)
+
+	if opts.forceAfter > 0 {
+		fmt.Fprintln(os.Stderr, "warning: ECS has no API to kill containers before their stopTimeout, --force-after only limits how long ecsctl waits")
+	}
+
+	// A single task named on the command line is stopped without asking
+	if (opts.stdin || len(tasks) > 1) && !opts.yes {
+		typist.Printf("%d tasks to be stopped\n", len(tasks))
+
+		if !confirmBatch("Do you really want to stop these tasks?", opts.stdin) {
+			return
+		}
+	}
+
+	results := bulkStopTasks(interruptContext(), ecsI, opts.cluster, tasks, opts.reason, opts.concurrency)
+
+	var stopped []*string
+	for _, result := range results {
+		if result.err == nil {
+			stopped = append(stopped, aws.String(result.task))
+		}
+	}
+
+	failed := reportBulkStop(results)
+
+	if !opts.wait || len(stopped) == 0 {
+		if failed > 0 {
+			must(fmt.Errorf("%d of %d failed", failed, len(tasks)))
+		}
+		return
+	}
+
+	ctx := interruptContext()
+	stopping, err := waitTasksStopped(ctx, opts.cluster, stopped, opts.forceAfter)
+	must(err)
+
+	if len(stopping) > 0 {
+		var ids []string
+		for _, t := range stopping {
+			ids = append(ids, taskID(aws.StringValue(t.TaskArn)))
+		}
+
+		message := "Gave up waiting for tasks still stopping"
+		if ctx.Err() != nil {
+			message = "Interrupted while waiting for tasks still stopping"
+		}
+		must(errors.New(message + ":\n\t" + strings.Join(ids, "\n\t")))
+	}
+
+	if failed > 0 {
+		must(fmt.Errorf("%d of %d failed", failed, len(tasks)))
+	}
+}
+
+var tasksStopCmd = &cobra.Command{
+	Use:   "stop [tasks...]",
+	Short: "Stop running tasks",
+	Run:   tasksStopRun,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksStopCmd)
+
+	flags := tasksStopCmd.Flags()
+
+	flags.StringVarP(&tasksStopOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&tasksStopOpts.reason, "reason", "Stopped by ecsctl", stopReasonSpec)
+	flags.BoolVarP(&tasksStopOpts.wait, "wait", "w", false, waitStopSpec)
+	flags.DurationVar(&tasksStopOpts.forceAfter, "force-after", 0, forceAfterSpec)
+	flags.BoolVar(&tasksStopOpts.stdin, "stdin", false, stdinSpec)
+	flags.BoolVarP(&tasksStopOpts.yes, "yes", "y", false, yesSpec)
+	flags.IntVar(&tasksStopOpts.concurrency, "concurrency", 5, concurrencySpec)
+
+	tasksStopCmd.MarkFlagRequired("cluster")
+
+	protectClusters(tasksStopCmd, "stop tasks", func(args []string) []string {
+		return []string{tasksStopOpts.cluster}
+	})
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+)
+
+type tasksWaitOptions struct {
+	cluster       string
+	container     string
+	timeout       time.Duration
+	stopOnTimeout bool
+	pollInterval  time.Duration
+}
+
+var tasksWaitOpts tasksWaitOptions
+
+func tasksWaitRun(cmd *cobra.Command, args []string) {
+	opts := &tasksWaitOpts
+
+	if opts.pollInterval <= 0 {
+		must(errors.New("--poll-interval must be greater than zero"))
+	}
+
+	if opts.stopOnTimeout && opts.timeout == 0 {
+		must(errors.New("--stop-on-timeout requires --timeout"))
+	}
+
+	tasks, err := describeTasks(opts.cluster, []*string{aws.String(args[0])})
+	if err != nil || len(tasks) == 0 {
+		must(errors.New("Task informed not found"))
+	}
+
+	td, err := describeTaskDefinition(aws.StringValue(tasks[0].TaskDefinitionArn))
+	must(err)
+
+	waitTask(opts.cluster, tasks[0], td, followOptions{
+		pollInterval:  opts.pollInterval,
+		timeout:       opts.timeout,
+		stopOnTimeout: opts.stopOnTimeout,
+		container:     opts.container,
+	})
+}
+
+var tasksWaitCmd = &cobra.Command{
+	Use:   "wait [task]",
+	Short: "Wait until a task stops and exit with the exit code of its container, or the one of the AWS failure",
+	Args:  cobra.ExactArgs(1),
+	Run:   tasksWaitRun,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksWaitCmd)
+
+	flags := tasksWaitCmd.Flags()
+
+	flags.StringVarP(&tasksWaitOpts.cluster, "cluster", "c", "", requiredSpec+clusterSpec)
+	flags.StringVar(&tasksWaitOpts.container, "container", "", waitContainerSpec)
+	flags.DurationVar(&tasksWaitOpts.timeout, "timeout", 0, tasksWaitTimeoutSpec)
+	flags.BoolVar(&tasksWaitOpts.stopOnTimeout, "stop-on-timeout", false, stopOnTimeoutSpec)
+	flags.DurationVar(&tasksWaitOpts.pollInterval, "poll-interval", 5*time.Second, waitPollIntervalSpec)
+
+	tasksWaitCmd.MarkFlagRequired("cluster")
+
+	// Waiting is only destructive when the task is stopped on timeout
+	protectClusters(tasksWaitCmd, "stop tasks", func(args []string) []string {
+		if !tasksWaitOpts.stopOnTimeout || tasksWaitOpts.timeout == 0 {
+			return nil
+		}
+		return []string{tasksWaitOpts.cluster}
+	})
+}
@@ -66,13 +66,19 @@ func uname() (uname string, err error) {
 	return
 }
 
+type upgradeOptions struct {
+	yes bool
+}
+
+var upgradeOpts upgradeOptions
+
 func upgradeRun(cmd *cobra.Command, args []string) {
 	available, err := getVersionsFromGithub()
-	typist.Must(err)
+	must(err)
 	latest := available[len(available)-1]
 
 	current, err := version.NewVersion(VERSION)
-	typist.Must(err)
+	must(err)
 
 	if !current.LessThan(latest) {
 		typist.Println("You are using the latest version")
@@ -80,59 +86,59 @@ func upgradeRun(cmd *cobra.Command, args []string) {
 	}
 
 	typist.Printf("There's a new version available. (current: %s - available: %s)\n", current, latest)
-	if !yes && !typist.Confirm("Do you want to upgrade?") {
+	if !upgradeOpts.yes && !typist.Confirm("Do you want to upgrade?") {
 		return
 	}
 
 	selfPath, err := os.Executable()
-	typist.Must(err)
+	must(err)
 
 	selfDir := filepath.Dir(selfPath)
 
 	actualFile, err := os.Open(selfPath)
-	typist.Must(err)
+	must(err)
 
 	fileStat, err := actualFile.Stat()
-	typist.Must(err)
+	must(err)
 
 	newFileName := "temp_" + filepath.Base(selfPath)
 	newFilePath := filepath.Join(selfDir, newFileName)
 	newFile, err := os.Create(newFilePath)
-	typist.Must(err)
+	must(err)
 	defer os.Remove(newFilePath)
 	newFile.Chmod(fileStat.Mode())
 
 	u, err := uname()
-	typist.Must(err)
+	must(err)
 
 	url := "https://github.com/gumieri/ecsctl/releases/download/v" + latest.String() + "/" + u
 
 	request, err := http.NewRequest("GET", url, nil)
-	typist.Must(err)
+	must(err)
 
 	client := &http.Client{}
 	response, err := client.Do(request)
 	if response.StatusCode != 200 {
 		err = fmt.Errorf("failed to download binary from GitHub. HTTP Status: %d", response.StatusCode)
 	}
-	typist.Must(err)
+	must(err)
 	defer response.Body.Close()
 
 	var proxyBody io.ReadCloser
 	if quiet {
 		_, err = io.Copy(newFile, response.Body)
-		typist.Must(err)
+		must(err)
 	} else {
 		bar := pb.New(int(response.ContentLength)).SetUnits(pb.U_BYTES)
 		proxyBody = bar.NewProxyReader(response.Body)
 
 		bar.Start()
 		_, err = io.Copy(newFile, proxyBody)
-		typist.Must(err)
+		must(err)
 		bar.Finish()
 	}
 
-	typist.Must(os.Rename(newFilePath, selfPath))
+	must(os.Rename(newFilePath, selfPath))
 }
 
 var upgradeCmd = &cobra.Command{
@@ -144,5 +150,5 @@ var upgradeCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
 	flags := upgradeCmd.Flags()
-	flags.BoolVarP(&yes, "yes", "y", false, yesSpec)
+	flags.BoolVarP(&upgradeOpts.yes, "yes", "y", false, yesSpec)
 }
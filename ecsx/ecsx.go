@@ -0,0 +1,206 @@
+// Package ecsx wraps the ECS client so every List call is paginated and every
+// Describe call is chunked to the API limits, with its Failures surfaced as an error.
+package ecsx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+// Client is the entry point of the commands to list and describe ECS resources
+type Client struct {
+	api ecsiface.ECSAPI
+}
+
+// New wraps an ECS client
+func New(api ecsiface.ECSAPI) *Client {
+	return &Client{api: api}
+}
+
+// FailuresError carries the Failures returned by Describe calls.
+// The resources described successfully are still returned along with it.
+type FailuresError struct {
+	Failures []*ecs.Failure
+}
+
+func (e *FailuresError) Error() string {
+	var failures []string
+	for _, f := range e.Failures {
+		failure := aws.StringValue(f.Arn) + " (" + aws.StringValue(f.Reason)
+		if f.Detail != nil {
+			failure = failure + ": " + aws.StringValue(f.Detail)
+		}
+		failures = append(failures, failure+")")
+	}
+
+	return fmt.Sprintf("%d resources failed to be described: %s", len(e.Failures), strings.Join(failures, ", "))
+}
+
+// Missing is true when every failure is of a resource that does not exist
+func (e *FailuresError) Missing() bool {
+	for _, f := range e.Failures {
+		if aws.StringValue(f.Reason) != "MISSING" {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMissing tells if the error only reports resources that do not exist
+func IsMissing(err error) bool {
+	if ferr, ok := err.(*FailuresError); ok {
+		return ferr.Missing()
+	}
+	return false
+}
+
+func failuresError(failures []*ecs.Failure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &FailuresError{Failures: failures}
+}
+
+func chunks(items []*string, size int) (chunked [][]*string) {
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunked = append(chunked, items[start:end])
+	}
+	return
+}
+
+// ListAllClusters returns the ARN of every cluster of the account in the region
+func (c *Client) ListAllClusters() (arns []*string, err error) {
+	err = c.api.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		arns = append(arns, page.ClusterArns...)
+		return !lastPage
+	})
+	return
+}
+
+// ListAllServices returns the ARN of every service of the cluster
+func (c *Client) ListAllServices(cluster string) (arns []*string, err error) {
+	err = c.api.ListServicesPages(&ecs.ListServicesInput{
+		Cluster: aws.String(cluster),
+	}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		arns = append(arns, page.ServiceArns...)
+		return !lastPage
+	})
+	return
+}
+
+// ListAllTasks returns the ARN of every task matching the input
+func (c *Client) ListAllTasks(input *ecs.ListTasksInput) (arns []*string, err error) {
+	err = c.api.ListTasksPages(input, func(page *ecs.ListTasksOutput, lastPage bool) bool {
+		arns = append(arns, page.TaskArns...)
+		return !lastPage
+	})
+	return
+}
+
+// ListAllContainerInstances returns the ARN of every container instance of the cluster
+func (c *Client) ListAllContainerInstances(cluster string) (arns []*string, err error) {
+	err = c.api.ListContainerInstancesPages(&ecs.ListContainerInstancesInput{
+		Cluster: aws.String(cluster),
+	}, func(page *ecs.ListContainerInstancesOutput, lastPage bool) bool {
+		arns = append(arns, page.ContainerInstanceArns...)
+		return !lastPage
+	})
+	return
+}
+
+// DescribeAllClusters describes the clusters in chunks of 100, the limit of the API
+func (c *Client) DescribeAllClusters(clusters []*string, include ...string) (described []*ecs.Cluster, err error) {
+	var failures []*ecs.Failure
+	for _, chunk := range chunks(clusters, 100) {
+		input := &ecs.DescribeClustersInput{Clusters: chunk}
+		if len(include) > 0 {
+			input.Include = aws.StringSlice(include)
+		}
+
+		result, err := c.api.DescribeClusters(input)
+		if err != nil {
+			return described, err
+		}
+
+		described = append(described, result.Clusters...)
+		failures = append(failures, result.Failures...)
+	}
+
+	return described, failuresError(failures)
+}
+
+// DescribeAllServices describes the services in chunks of 10, the limit of the API
+func (c *Client) DescribeAllServices(cluster string, services []*string, include ...string) (described []*ecs.Service, err error) {
+	var failures []*ecs.Failure
+	for _, chunk := range chunks(services, 10) {
+		input := &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: chunk,
+		}
+		if len(include) > 0 {
+			input.Include = aws.StringSlice(include)
+		}
+
+		result, err := c.api.DescribeServices(input)
+		if err != nil {
+			return described, err
+		}
+
+		described = append(described, result.Services...)
+		failures = append(failures, result.Failures...)
+	}
+
+	return described, failuresError(failures)
+}
+
+// DescribeAllTasks describes the tasks in chunks of 100, the limit of the API
+func (c *Client) DescribeAllTasks(cluster string, tasks []*string, include ...string) (described []*ecs.Task, err error) {
+	var failures []*ecs.Failure
+	for _, chunk := range chunks(tasks, 100) {
+		input := &ecs.DescribeTasksInput{
+			Cluster: aws.String(cluster),
+			Tasks:   chunk,
+		}
+		if len(include) > 0 {
+			input.Include = aws.StringSlice(include)
+		}
+
+		result, err := c.api.DescribeTasks(input)
+		if err != nil {
+			return described, err
+		}
+
+		described = append(described, result.Tasks...)
+		failures = append(failures, result.Failures...)
+	}
+
+	return described, failuresError(failures)
+}
+
+// DescribeAllContainerInstances describes the container instances in chunks of 100, the limit of the API
+func (c *Client) DescribeAllContainerInstances(cluster string, containerInstances []*string) (described []*ecs.ContainerInstance, err error) {
+	var failures []*ecs.Failure
+	for _, chunk := range chunks(containerInstances, 100) {
+		result, err := c.api.DescribeContainerInstances(&ecs.DescribeContainerInstancesInput{
+			Cluster:            aws.String(cluster),
+			ContainerInstances: chunk,
+		})
+		if err != nil {
+			return described, err
+		}
+
+		described = append(described, result.ContainerInstances...)
+		failures = append(failures, result.Failures...)
+	}
+
+	return described, failuresError(failures)
+}
@@ -0,0 +1,142 @@
+package ecsx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+// fakeECS answers the calls of the client from fixed pages and resources, the other calls panic
+type fakeECS struct {
+	ecsiface.ECSAPI
+
+	servicePages [][]string
+	services     map[string]bool
+	describeErr  error
+	chunkSizes   []int
+}
+
+func (f *fakeECS) ListServicesPages(input *ecs.ListServicesInput, fn func(*ecs.ListServicesOutput, bool) bool) error {
+	for i, page := range f.servicePages {
+		if !fn(&ecs.ListServicesOutput{ServiceArns: aws.StringSlice(page)}, i == len(f.servicePages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeECS) DescribeServices(input *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+	f.chunkSizes = append(f.chunkSizes, len(input.Services))
+	if f.describeErr != nil && len(f.chunkSizes) > 1 {
+		return nil, f.describeErr
+	}
+
+	output := &ecs.DescribeServicesOutput{}
+	for _, name := range aws.StringValueSlice(input.Services) {
+		if !f.services[name] {
+			output.Failures = append(output.Failures, &ecs.Failure{Arn: aws.String(name), Reason: aws.String("MISSING")})
+			continue
+		}
+		output.Services = append(output.Services, &ecs.Service{ServiceName: aws.String(name)})
+	}
+	return output, nil
+}
+
+func (f *fakeECS) DescribeTasks(input *ecs.DescribeTasksInput) (*ecs.DescribeTasksOutput, error) {
+	output := &ecs.DescribeTasksOutput{}
+	for _, arn := range input.Tasks {
+		output.Failures = append(output.Failures, &ecs.Failure{
+			Arn:    arn,
+			Reason: aws.String("ACCESS_DENIED"),
+			Detail: aws.String("not authorized"),
+		})
+	}
+	return output, nil
+}
+
+func names(prefix string, count int) (names []string) {
+	for i := 0; i < count; i++ {
+		names = append(names, fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return
+}
+
+func TestListAllServicesPages(t *testing.T) {
+	fake := &fakeECS{servicePages: [][]string{names("a", 10), names("b", 10), names("c", 3)}}
+
+	arns, err := New(fake).ListAllServices("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(arns) != 23 || aws.StringValue(arns[22]) != "c-2" {
+		t.Errorf("got %d services, want every page listed", len(arns))
+	}
+}
+
+func TestDescribeAllServicesChunks(t *testing.T) {
+	services := names("web", 25)
+	fake := &fakeECS{services: make(map[string]bool)}
+	for _, s := range services {
+		fake.services[s] = true
+	}
+
+	described, err := New(fake).DescribeAllServices("staging", aws.StringSlice(services))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(described) != 25 {
+		t.Errorf("got %d services described, want 25", len(described))
+	}
+	if fmt.Sprint(fake.chunkSizes) != "[10 10 5]" {
+		t.Errorf("got chunks of %v, want [10 10 5]", fake.chunkSizes)
+	}
+}
+
+func TestDescribeAllServicesFailures(t *testing.T) {
+	fake := &fakeECS{services: map[string]bool{"web": true}}
+
+	described, err := New(fake).DescribeAllServices("staging", aws.StringSlice([]string{"web", "gone"}))
+	if len(described) != 1 || aws.StringValue(described[0].ServiceName) != "web" {
+		t.Errorf("got %v, want the existing service along with the failure", described)
+	}
+
+	ferr, ok := err.(*FailuresError)
+	if !ok || len(ferr.Failures) != 1 || aws.StringValue(ferr.Failures[0].Arn) != "gone" {
+		t.Fatalf("got %v, want the failure of gone", err)
+	}
+	if !IsMissing(err) {
+		t.Error("a MISSING failure should be reported as missing")
+	}
+}
+
+func TestDescribeAllServicesErrorMidway(t *testing.T) {
+	fake := &fakeECS{services: make(map[string]bool), describeErr: errors.New("ThrottlingException: Rate exceeded")}
+	for _, s := range names("web", 15) {
+		fake.services[s] = true
+	}
+
+	described, err := New(fake).DescribeAllServices("staging", aws.StringSlice(names("web", 15)))
+	if err != fake.describeErr {
+		t.Errorf("got %v, want the error of the second chunk", err)
+	}
+	if len(described) != 10 {
+		t.Errorf("got %d services, want the 10 of the first chunk", len(described))
+	}
+}
+
+func TestDescribeAllTasksNotMissing(t *testing.T) {
+	_, err := New(&fakeECS{}).DescribeAllTasks("staging", aws.StringSlice([]string{"a1b2"}))
+	if err == nil || IsMissing(err) {
+		t.Fatalf("got %v, want a failure other than missing", err)
+	}
+
+	if want := "1 resources failed to be described: a1b2 (ACCESS_DENIED: not authorized)"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}